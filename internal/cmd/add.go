@@ -2,14 +2,21 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
 	"github.com/spf13/cobra"
 )
 
+// addRequiredFlags must be set on `add` unless --from-profile fills them in.
+var addRequiredFlags = []string{"name", "profile", "tenancy", "compartment"}
+
 func newAddCmd() *cobra.Command {
 	var cfgPath string
 	var ctx config.Context
+	var tags []string
+	var fromProfile string
 
 	cmd := &cobra.Command{
 		Use:   "add",
@@ -23,13 +30,49 @@ func newAddCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if err := ctx.Validate(); err != nil {
+			cfg, err := config.Load(path)
+			if err != nil {
 				return err
 			}
-			cfg, err := config.Load(path)
+			if fromProfile == "" {
+				// The template compartment only counts in the template's own tenancy.
+				tenancy := ctx.TenancyOCID
+				if !flagChanged(cmd, "tenancy") {
+					tenancy = templateField(cfg.Options.Defaults, "tenancy")
+				}
+				var missing []string
+				for _, name := range addRequiredFlags {
+					fromTemplate := templateField(cfg.Options.Defaults, name) != ""
+					if name == "compartment" {
+						fromTemplate = cfg.Options.DefaultCompartmentFor(tenancy) != ""
+					}
+					if !flagChanged(cmd, name) && !fromTemplate {
+						missing = append(missing, fmt.Sprintf("%q", name))
+					}
+				}
+				if len(missing) > 0 {
+					return fmt.Errorf("required flag(s) %s not set (or use --from-profile or options.defaults)", strings.Join(missing, ", "))
+				}
+			}
+			if fromProfile != "" {
+				if err := fillFromProfile(cmd, cfg, &ctx, fromProfile); err != nil {
+					return err
+				}
+			}
+			applyContextDefaults(cmd, cfg.Options, &ctx)
+			ctx.Source = config.SourceManual
+			parsed, err := config.ParseTags(tags)
 			if err != nil {
 				return err
 			}
+			var baseTags map[string]string
+			if d := cfg.Options.Defaults; d != nil {
+				baseTags = d.Tags
+			}
+			ctx.Tags = config.MergeTags(baseTags, parsed)
+			if err := ctx.Validate(); err != nil {
+				return err
+			}
 			if err := cfg.UpsertContext(ctx); err != nil {
 				return err
 			}
@@ -53,11 +96,102 @@ func newAddCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&ctx.Region, "region", "r", "", "OCI region")
 	cmd.Flags().StringVarP(&ctx.User, "user", "u", "", "User hint")
 	cmd.Flags().StringVarP(&ctx.Notes, "notes", "N", "", "Notes")
-
-	_ = cmd.MarkFlagRequired("name")
-	_ = cmd.MarkFlagRequired("profile")
-	_ = cmd.MarkFlagRequired("tenancy")
-	_ = cmd.MarkFlagRequired("compartment")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Tag as key=value (repeatable)")
+	cmd.Flags().StringVar(&fromProfile, "from-profile", "", "Pre-fill profile, tenancy, region, user, and compartment (tenancy root) from this OCI CLI profile; explicit flags win")
 
 	return cmd
 }
+
+// fillFromProfile sets every ctx field whose flag was not given from the OCI
+// CLI profile, as import would. The compartment defaults to the root of the
+// resulting tenancy.
+func fillFromProfile(cmd *cobra.Command, cfg config.Config, ctx *config.Context, profile string) error {
+	base, err := contextFromOCIProfile(cfg, profile)
+	if err != nil {
+		return fmt.Errorf("--from-profile: %w", err)
+	}
+	fill := func(flag string, dst *string, val string) {
+		if !flagChanged(cmd, flag) {
+			*dst = val
+		}
+	}
+	fill("name", &ctx.Name, base.Name)
+	fill("profile", &ctx.Profile, base.Profile)
+	fill("tenancy", &ctx.TenancyOCID, base.TenancyOCID)
+	fill("region", &ctx.Region, base.Region)
+	fill("user", &ctx.User, base.User)
+	fill("compartment", &ctx.CompartmentOCID, ctx.TenancyOCID)
+	return nil
+}
+
+// templateField returns the options.defaults value for an add flag, or ""
+// when there is no template or the flag has no template field.
+func templateField(d *config.Context, flag string) string {
+	if d == nil {
+		return ""
+	}
+	switch flag {
+	case "profile":
+		return d.Profile
+	case "auth-method":
+		return d.AuthMethod
+	case "tenancy":
+		return d.TenancyOCID
+	case "compartment":
+		return d.CompartmentOCID
+	case "region":
+		return d.Region
+	case "user":
+		return d.User
+	case "notes":
+		return d.Notes
+	}
+	return ""
+}
+
+// applyContextDefaults fills ctx from options.defaults after any
+// --from-profile fill. Flags given on the command line always win, and so do
+// values read from the profile, except the tenancy-root compartment it
+// defaults to: the template's compartment replaces that, as it does for
+// import, when the template is not pinned to another tenancy.
+func applyContextDefaults(cmd *cobra.Command, opts config.Options, ctx *config.Context) {
+	d := opts.Defaults
+	if d == nil {
+		return
+	}
+	fill := func(flag string, dst *string) {
+		if val := templateField(d, flag); val != "" && *dst == "" && !flagChanged(cmd, flag) {
+			*dst = val
+		}
+	}
+	fill("profile", &ctx.Profile)
+	fill("tenancy", &ctx.TenancyOCID)
+	fill("region", &ctx.Region)
+	fill("user", &ctx.User)
+	fill("notes", &ctx.Notes)
+	// --auth-method has a non-empty default, so only an explicit flag beats the template.
+	if d.AuthMethod != "" && !flagChanged(cmd, "auth-method") {
+		ctx.AuthMethod = d.AuthMethod
+	}
+	if comp := opts.DefaultCompartmentFor(ctx.TenancyOCID); comp != "" && !flagChanged(cmd, "compartment") {
+		ctx.CompartmentOCID = comp
+	}
+}
+
+// contextFromOCIProfile reads profile from the configured OCI CLI config and
+// returns the context import would create for it.
+func contextFromOCIProfile(cfg config.Config, profile string) (config.Context, error) {
+	ociPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return config.Context{}, err
+	}
+	profiles, err := ocicfg.LoadProfiles(ociPath)
+	if err != nil {
+		return config.Context{}, err
+	}
+	p, ok := profiles[profile]
+	if !ok {
+		return config.Context{}, fmt.Errorf("profile %s not found in %s", profile, ociPath)
+	}
+	return config.ContextFromProfile(profile, p), nil
+}
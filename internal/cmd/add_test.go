@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestAddFromProfilePrefillsAndFlagsOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	profile := "[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\nuser=ocid1.user.oc1..dev\n"
+	if err := os.WriteFile(ociPath, []byte(profile), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	if err := config.Save(cfgPath, config.Config{Options: config.Options{OCIConfigPath: ociPath}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newRootCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(append([]string{"add"}, args...), "--config", cfgPath))
+		return cmd.Execute()
+	}
+
+	if err := run("--from-profile", "DEV"); err != nil {
+		t.Fatalf("add --from-profile: %v", err)
+	}
+	if err := run("--from-profile", "DEV", "--name", "dev-ash", "--region", "us-ashburn-1"); err != nil {
+		t.Fatalf("add --from-profile with overrides: %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	dev, err := loaded.GetContext("DEV")
+	if err != nil {
+		t.Fatalf("DEV context: %v", err)
+	}
+	if dev.Profile != "DEV" || dev.TenancyOCID != "ocid1.tenancy.oc1..dev" || dev.CompartmentOCID != dev.TenancyOCID || dev.Region != "us-phoenix-1" || dev.User != "ocid1.user.oc1..dev" || dev.Source != config.SourceManual {
+		t.Fatalf("unexpected prefilled context: %+v", dev)
+	}
+	ash, err := loaded.GetContext("dev-ash")
+	if err != nil {
+		t.Fatalf("dev-ash context: %v", err)
+	}
+	if ash.Profile != "DEV" || ash.Region != "us-ashburn-1" || ash.TenancyOCID != "ocid1.tenancy.oc1..dev" {
+		t.Fatalf("expected flags to override the profile: %+v", ash)
+	}
+
+	if err := run("--from-profile", "MISSING"); err == nil || !strings.Contains(err.Error(), "profile MISSING not found") {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+	if err := run("--name", "x", "--profile", "DEV"); err == nil || !strings.Contains(err.Error(), `required flag(s) "tenancy", "compartment" not set`) {
+		t.Fatalf("expected required flags error without --from-profile, got %v", err)
+	}
+}
+
+func TestAddFillsUnsetFieldsFromDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{Options: config.Options{Defaults: &config.Context{
+		TenancyOCID:     "ocid1.tenancy.oc1..team",
+		CompartmentOCID: "ocid1.compartment.oc1..shared",
+		Region:          "eu-frankfurt-1",
+		Tags:            map[string]string{"team": "core", "env": "dev"},
+	}}}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newRootCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(append([]string{"add"}, args...), "--config", cfgPath))
+		return cmd.Execute()
+	}
+
+	if err := run("--name", "x", "--profile", "X", "--tenancy", "ocid1.tenancy.oc1..team", "--tag", "env=prod"); err != nil {
+		t.Fatalf("add with template: %v", err)
+	}
+	if err := run("--name", "y", "--profile", "Y", "--region", "us-ashburn-1", "--compartment", "ocid1.compartment.oc1..mine"); err != nil {
+		t.Fatalf("add with overrides: %v", err)
+	}
+	if err := run("--name", "z", "--profile", "Z", "--tenancy", "ocid1.tenancy.oc1..other"); err == nil || !strings.Contains(err.Error(), `required flag(s) "compartment" not set`) {
+		t.Fatalf("expected the template compartment to be skipped for another tenancy, got %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	x, _ := loaded.GetContext("x")
+	if x.Region != "eu-frankfurt-1" || x.CompartmentOCID != "ocid1.compartment.oc1..shared" || x.Tags["team"] != "core" || x.Tags["env"] != "prod" {
+		t.Fatalf("expected unset fields from the template: %+v", x)
+	}
+	y, _ := loaded.GetContext("y")
+	if y.Region != "us-ashburn-1" || y.CompartmentOCID != "ocid1.compartment.oc1..mine" || y.TenancyOCID != "ocid1.tenancy.oc1..team" {
+		t.Fatalf("expected flags to win over the template: %+v", y)
+	}
+	if _, err := loaded.GetContext("z"); err == nil {
+		t.Fatalf("expected z not to be saved")
+	}
+}
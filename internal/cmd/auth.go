@@ -485,7 +485,7 @@ func runAuthEnsure(cmd *cobra.Command, cfg config.Config, ctx config.Context, na
 		Profile:    ctx.Profile,
 		AuthMethod: method,
 	}
-	if home, err := validateAuthContext(cmd, ctx, cfg.Options.OCIConfigPath); err == nil {
+	if home, err := validateAuthContext(cmd, ctx, ociConfigOption(cfg)); err == nil {
 		result.OK = true
 		result.State = authEnsureStateReady
 		result.Validated = true
@@ -495,9 +495,9 @@ func runAuthEnsure(cmd *cobra.Command, cfg config.Config, ctx config.Context, na
 		result.Error = err.Error()
 	}
 	if method == config.AuthMethodSecurityToken {
-		if err := runOCIForAuth(cmd, []string{"session", "refresh", "--profile", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath}); err == nil {
+		if err := runOCIForAuth(cmd, []string{"session", "refresh", "--profile", ctx.Profile, "--config-file", ociConfigOption(cfg)}); err == nil {
 			result.Refreshed = true
-			if home, validateErr := validateAuthContext(cmd, ctx, cfg.Options.OCIConfigPath); validateErr == nil {
+			if home, validateErr := validateAuthContext(cmd, ctx, ociConfigOption(cfg)); validateErr == nil {
 				result.OK = true
 				result.State = authEnsureStateRefreshed
 				result.Validated = true
@@ -523,14 +523,14 @@ func runAuthEnsure(cmd *cobra.Command, cfg config.Config, ctx config.Context, na
 			return finalizeAuthEnsureResult(result), fmt.Errorf("auth ensure failed for %s (%s): login required", name, method)
 		}
 		result.LoginAttempted = true
-		if err := runOCIForAuth(cmd, []string{"session", "authenticate", "--profile-name", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath, "--region", ctx.Region}); err != nil {
+		if err := runOCIForAuth(cmd, []string{"session", "authenticate", "--profile-name", ctx.Profile, "--config-file", ociConfigOption(cfg), "--region", ctx.Region}); err != nil {
 			result.State = authEnsureStateLoginFailed
 			result.Error = err.Error()
 			result.LoginRequired = true
 			result.LoginCommand = authLoginCommand(ctx)
 			return finalizeAuthEnsureResult(result), fmt.Errorf("auth ensure failed for %s (%s): %w", name, method, err)
 		}
-		if home, err := validateAuthContext(cmd, ctx, cfg.Options.OCIConfigPath); err == nil {
+		if home, err := validateAuthContext(cmd, ctx, ociConfigOption(cfg)); err == nil {
 			result.OK = true
 			result.State = authEnsureStateReady
 			result.Validated = true
@@ -902,9 +902,9 @@ func newAuthCmd() *cobra.Command {
 			method := config.NormalizeAuthMethod(ctx.AuthMethod)
 			switch method {
 			case config.AuthMethodSecurityToken:
-				return runOCIForAuth(cmd, []string{"session", "authenticate", "--profile-name", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath, "--region", ctx.Region})
+				return runOCIForAuth(cmd, []string{"session", "authenticate", "--profile-name", ctx.Profile, "--config-file", ociConfigOption(cfg), "--region", ctx.Region})
 			case config.AuthMethodAPIKey:
-				return runOCIForAuth(cmd, []string{"setup", "config", "--profile", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath})
+				return runOCIForAuth(cmd, []string{"setup", "config", "--profile", ctx.Profile, "--config-file", ociConfigOption(cfg)})
 			case config.AuthMethodInstancePrincipal:
 				return runOCIForAuth(cmd, []string{"setup", "instance-principal"})
 			default:
@@ -932,7 +932,7 @@ func newAuthCmd() *cobra.Command {
 			if method != config.AuthMethodSecurityToken {
 				return fmt.Errorf("refresh is only supported for security_token auth")
 			}
-			return runOCIForAuth(cmd, []string{"session", "refresh", "--profile", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath})
+			return runOCIForAuth(cmd, []string{"session", "refresh", "--profile", ctx.Profile, "--config-file", ociConfigOption(cfg)})
 		},
 	})
 
@@ -981,7 +981,7 @@ func newAuthCmd() *cobra.Command {
 				return err
 			}
 			method := config.NormalizeAuthMethod(ctx.AuthMethod)
-			homeRegion, err := validateAuthContext(cmd, ctx, cfg.Options.OCIConfigPath)
+			homeRegion, err := validateAuthContext(cmd, ctx, ociConfigOption(cfg))
 			if err != nil {
 				return fmt.Errorf("auth validate failed for method %s: %w", method, err)
 			}
@@ -1016,7 +1016,7 @@ func newAuthCmd() *cobra.Command {
 			method := config.NormalizeAuthMethod(ctx.AuthMethod)
 			switch method {
 			case config.AuthMethodAPIKey, config.AuthMethodSecurityToken:
-				return runOCIForAuth(cmd, []string{"setup", "config", "--profile", ctx.Profile, "--config-file", cfg.Options.OCIConfigPath})
+				return runOCIForAuth(cmd, []string{"setup", "config", "--profile", ctx.Profile, "--config-file", ociConfigOption(cfg)})
 			case config.AuthMethodInstancePrincipal:
 				return runOCIForAuth(cmd, []string{"setup", "instance-principal"})
 			default:
@@ -1337,13 +1337,9 @@ func printAuthToken(cmd *cobra.Command, entry authTokenCacheEntry, format string
 }
 
 func authTokenCachePath(cfg config.Config, service, issuer, clientID, scope string) (string, error) {
-	base := filepath.Dir(cfg.Options.SocketPath)
-	if base == "." || base == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		base = filepath.Join(home, ".oci-context")
+	base, err := stateDir(cfg)
+	if err != nil {
+		return "", err
 	}
 	sum := sha256.Sum256([]byte(strings.Join([]string{service, issuer, clientID, scope}, "\x00")))
 	return filepath.Join(base, "tokens", service+"-"+hex.EncodeToString(sum[:])[:16]+".json"), nil
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/adrianmross/oci-context/internal/daemon"
+	"github.com/adrianmross/oci-context/pkg/config"
+	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/spf13/cobra"
+)
+
+// daemonCacheTimeout bounds the best-effort clear_cache request to a running daemon.
+const daemonCacheTimeout = 2 * time.Second
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached identity, region, and compartment data",
+	}
+	cmd.AddCommand(newCacheClearCmd())
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var contextName string
+
+	cmd := &cobra.Command{
+		Use:   "clear [--context <name>]",
+		Short: "Remove cached identity, region, and compartment data (all, or one context's)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if contextName == "" {
+				return purgeCaches(cmd, cfg, nil)
+			}
+			ctx, err := cfg.GetContext(contextName)
+			if err != nil {
+				return fmt.Errorf("%s: %w", contextName, err)
+			}
+			return purgeCaches(cmd, cfg, &ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&contextName, "context", "x", "", "Only clear entries for this context's profile and tenancy")
+	return cmd
+}
+
+// purgeCaches removes ctx's entries (every entry when ctx is nil) from the
+// on-disk identity and region caches and, when a daemon is running, from its
+// compartment cache. Entries another context in cfg still uses are kept: the
+// identity cache is keyed by profile and tenancy, the region cache by tenancy,
+// and the daemon's by profile. An unreachable daemon is not an error: it has
+// nothing cached.
+func purgeCaches(cmd *cobra.Command, cfg config.Config, ctx *config.Context) error {
+	w := infoWriter(cmd.OutOrStdout())
+	path, err := identityCachePath(cfg)
+	if err != nil {
+		return err
+	}
+	scope := "all contexts"
+	ociCfgPath, profile, tenancy := "", "", ""
+	if ctx != nil {
+		scope = "context " + ctx.Name
+		profile, tenancy = ctx.Profile, ctx.TenancyOCID
+		if ociCfgPath, err = resolveOCIConfigPath(cfg); err != nil {
+			return err
+		}
+	}
+	if other := otherContextUsing(cfg, ctx, func(c config.Context) bool { return c.Profile == profile && c.TenancyOCID == tenancy }); other != "" {
+		fmt.Fprintf(w, "Kept identity cache entries for %s: context %s uses the same profile and tenancy\n", scope, other)
+	} else {
+		removed, err := oci.NewIdentityCache(path).Purge(ociCfgPath, profile, tenancy)
+		if err != nil {
+			return fmt.Errorf("purge identity cache: %w", err)
+		}
+		fmt.Fprintf(w, "Cleared %d identity cache entries for %s\n", removed, scope)
+	}
+	if other := otherContextUsing(cfg, ctx, func(c config.Context) bool { return c.TenancyOCID == tenancy }); other != "" {
+		fmt.Fprintf(w, "Kept region cache entries for %s: context %s uses the same tenancy\n", scope, other)
+	} else {
+		regionPath, err := regionCachePath(cfg)
+		if err != nil {
+			return err
+		}
+		regionsRemoved, err := oci.NewRegionCache(regionPath).Purge(tenancy)
+		if err != nil {
+			return fmt.Errorf("purge region cache: %w", err)
+		}
+		fmt.Fprintf(w, "Cleared %d region cache entries for %s\n", regionsRemoved, scope)
+	}
+	if other := otherContextUsing(cfg, ctx, func(c config.Context) bool { return c.Profile == profile }); other != "" {
+		slog.Debug("daemon cache kept", "context", ctx.Name, "shared_with", other)
+	} else if removed, err := clearDaemonCache(cfg.Options.SocketPath, ctx); err != nil {
+		slog.Debug("daemon cache not cleared", "socket", cfg.Options.SocketPath, "err", err)
+	} else {
+		fmt.Fprintf(w, "Cleared %d daemon compartment cache entries for %s\n", removed, scope)
+	}
+	return nil
+}
+
+// otherContextUsing returns the name of a context in cfg, other than ctx, for
+// which uses reports true, or "" when there is none or ctx is nil.
+func otherContextUsing(cfg config.Config, ctx *config.Context, uses func(config.Context) bool) string {
+	if ctx == nil {
+		return ""
+	}
+	for _, c := range cfg.Contexts {
+		if c.Name != ctx.Name && uses(c) {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// clearDaemonCache asks the daemon at socketPath to drop its cached
+// compartments for ctx, or all of them when ctx is nil.
+func clearDaemonCache(socketPath string, ctx *config.Context) (int, error) {
+	req := ipcmsg.Request{Method: "clear_cache"}
+	if ctx != nil {
+		raw, err := json.Marshal(ctx)
+		if err != nil {
+			return 0, err
+		}
+		req.Context = raw
+	}
+	start := time.Now()
+	conn, err := ipcmsg.DialTimeout(socketPath, daemonCacheTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(start.Add(daemonCacheTimeout))
+	// A daemon too old to list its capabilities is asked anyway.
+	if caps, err := conn.Negotiate(); err == nil && !caps.Supports(req.Method) {
+		return 0, fmt.Errorf("daemon %s does not support %s", caps.Version, req.Method)
+	} else if err != nil && !errors.Is(err, ipcmsg.ErrNotImplemented) {
+		return 0, err
+	}
+	if err := conn.SendRequest(req); err != nil {
+		return 0, err
+	}
+	var resp struct {
+		OK    bool                    `json:"ok"`
+		Error string                  `json:"error,omitempty"`
+		Data  daemon.CacheClearResult `json:"data,omitempty"`
+	}
+	if err := conn.ReadResponse(&resp); err != nil {
+		return 0, err
+	}
+	if !resp.OK {
+		return 0, fmt.Errorf("%s", resp.Error)
+	}
+	return resp.Data.Removed, nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestCacheClearAndDeletePurgeCache(t *testing.T) {
+	t.Setenv(oci.EnvIdentityCacheTTL, "10m")
+	t.Setenv(oci.EnvRegionCacheTTL, "1h")
+	t.Cleanup(func() { cliQuiet = false })
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	ctx := func(name, profile, tenancy string) config.Context {
+		return config.Context{Name: name, Profile: profile, TenancyOCID: tenancy, CompartmentOCID: tenancy, Region: "us-phoenix-1"}
+	}
+	cfg := config.Config{
+		// The socket directory doubles as the state directory for caches.
+		Options: config.Options{SocketPath: filepath.Join(tmp, "daemon.sock"), OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			ctx("dev", "DEV", "ocid1.tenancy.oc1..dev"),
+			ctx("prod", "PROD", "ocid1.tenancy.oc1..prod"),
+			{Name: "dev-app", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev", CompartmentOCID: "ocid1.compartment.oc1..app", Region: "us-phoenix-1"},
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cachePath, err := identityCachePath(cfg)
+	if err != nil {
+		t.Fatalf("identity cache path: %v", err)
+	}
+	regionPath, err := regionCachePath(cfg)
+	if err != nil {
+		t.Fatalf("region cache path: %v", err)
+	}
+	seed := func() {
+		fetch := oci.NewIdentityCache(cachePath).Wrap(func(_ context.Context, _, _, _, _, compartment, _ string) (oci.IdentityDetails, error) {
+			return oci.IdentityDetails{TenancyName: "t", CompartmentName: compartment}, nil
+		})
+		list := func(context.Context, string, string) ([]string, error) { return []string{"us-phoenix-1"}, nil }
+		for _, c := range cfg.Contexts {
+			if _, err := fetch(context.Background(), "/tmp/oci", c.Profile, c.Region, c.TenancyOCID, c.CompartmentOCID, ""); err != nil {
+				t.Fatalf("seed cache: %v", err)
+			}
+			if _, err := oci.NewRegionCache(regionPath).Lookup(context.Background(), c.TenancyOCID, "/tmp/oci", c.Profile, list); err != nil {
+				t.Fatalf("seed region cache: %v", err)
+			}
+		}
+	}
+	seed()
+
+	run := func(args ...string) string {
+		t.Helper()
+		root := newRootCmd()
+		out := &bytes.Buffer{}
+		root.SetOut(out)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(append(args, "--config", cfgPath))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	// No daemon is listening, so only the identity cache is reported.
+	if out := run("cache", "clear", "--context", "prod"); out != "Cleared 1 identity cache entries for context prod\nCleared 1 region cache entries for context prod\n" {
+		t.Fatalf("unexpected cache clear output: %q", out)
+	}
+	// dev-app still uses the DEV profile and the dev tenancy.
+	if out := run("delete", "dev", "--yes", "--purge-cache"); out != "Deleted context dev\nKept identity cache entries for context dev: context dev-app uses the same profile and tenancy\nKept region cache entries for context dev: context dev-app uses the same tenancy\n" {
+		t.Fatalf("unexpected delete output: %q", out)
+	}
+	// The last user purges them; -q keeps the confirmations quiet.
+	if out := run("delete", "dev-app", "--yes", "--purge-cache", "-q"); out != "Deleted context dev-app\n" {
+		t.Fatalf("unexpected quiet delete output: %q", out)
+	}
+	if n, err := oci.NewIdentityCache(cachePath).Purge("", "", ""); err != nil || n != 0 {
+		t.Fatalf("expected an empty cache, %d entries left (%v)", n, err)
+	}
+
+	seed()
+	if out := run("cache", "clear"); out != "Cleared 3 identity cache entries for all contexts\nCleared 2 region cache entries for all contexts\n" {
+		t.Fatalf("unexpected full clear output: %q", out)
+	}
+}
@@ -1,8 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+	"github.com/spf13/cobra"
 )
 
 type configPathCandidate struct {
@@ -24,17 +42,19 @@ type configPathResolution struct {
 // Priority:
 //  1. explicit --config
 //  2. if global flag set -> ~/.oci-context/config.yml
-//  3. project-local configs (in order):
+//  3. $OCI_CONTEXT_CONFIG
+//  4. project-local configs (in order):
 //     ./.oci-context.yml, ./.oci-context.json,
 //     ./.oci-context/config.yml, ./.oci-context/config.json,
 //     ./oci-context.yml, ./oci-context.json,
 //     ./oci-context/config.yml, ./oci-context/config.json
-//  4. fallback to ~/.oci-context/config.yml
+//  5. fallback to ~/.oci-context/config.yml
 func resolveConfigPath(cfg string, global bool) (string, error) {
 	resolution, err := resolveConfigPathInfo(cfg, global)
 	if err != nil {
 		return "", err
 	}
+	slog.Debug("config path resolved", "path", resolution.Path, "source", resolution.Source)
 	return resolution.Path, nil
 }
 
@@ -60,6 +80,12 @@ func resolveConfigPathInfo(cfg string, global bool) (configPathResolution, error
 		return resolution, nil
 	}
 
+	if env := strings.TrimSpace(os.Getenv(config.EnvConfigPath)); env != "" {
+		resolution.Path = env
+		resolution.Source = "env"
+		return resolution, nil
+	}
+
 	// project discovery (cwd)
 	if wd, err := os.Getwd(); err == nil {
 		resolution.WorkingDirectory = wd
@@ -109,3 +135,308 @@ func globalConfigPath() (string, error) {
 	}
 	return filepath.Join(home, ".oci-context", "config.yml"), nil
 }
+
+// loadConfigForCommand loads the resolved config file, or, when fromEnv is set,
+// an in-memory config whose current context comes from OCI_CONTEXT_* variables.
+// The returned path is empty for env-defined configs so callers never write it.
+func loadConfigForCommand(cmd *cobra.Command, cfgPath string, fromEnv bool) (config.Config, string, error) {
+	if fromEnv {
+		cfg, err := config.ConfigFromEnv(os.Getenv)
+		if err != nil {
+			return config.Config{}, "", fmt.Errorf("--from-env: %w", err)
+		}
+		return cfg, "", nil
+	}
+	useGlobal, err := cmd.Flags().GetBool("global")
+	if err != nil {
+		return config.Config{}, "", err
+	}
+	return loadReadConfig(cfgPath, useGlobal)
+}
+
+// loadReadConfig loads the resolved config for a read-only command. With
+// --merge and a discovered project config, the project file is layered over
+// the global config (see config.Merge). The returned path is the resolved
+// file, which is where writes go.
+func loadReadConfig(cfgPath string, useGlobal bool) (config.Config, string, error) {
+	resolution, err := resolveConfigPathInfo(cfgPath, useGlobal)
+	if err != nil {
+		return config.Config{}, "", err
+	}
+	path := resolution.Path
+	slog.Debug("config path resolved", "path", path, "source", resolution.Source)
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, "", err
+	}
+	if !cliMerge || resolution.Source != "project" {
+		return cfg, path, nil
+	}
+	if _, err := os.Stat(resolution.GlobalPath); err != nil {
+		return cfg, path, nil
+	}
+	global, err := config.Load(resolution.GlobalPath)
+	if err != nil {
+		return config.Config{}, "", fmt.Errorf("--merge: %w", err)
+	}
+	slog.Debug("config merged", "global", resolution.GlobalPath, "project", path)
+	return config.Merge(global, cfg), path, nil
+}
+
+// targetContext returns the context named by an explicit --context flag, or
+// the current context when name is empty. It never changes cfg.
+func targetContext(cfg config.Config, name string) (config.Context, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if cfg.CurrentContext == "" {
+			return config.Context{}, fmt.Errorf("no current context set")
+		}
+		name = cfg.CurrentContext
+	}
+	ctx, err := cfg.GetContext(name)
+	if err != nil {
+		return config.Context{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return ctx, nil
+}
+
+// warnInactiveCompartment notes on w when ctx's compartment was not ACTIVE
+// when last checked. The state is not re-checked here; validate refreshes it.
+func warnInactiveCompartment(w io.Writer, ctx config.Context) {
+	state := ctx.InactiveCompartmentState()
+	if state == "" {
+		return
+	}
+	fmt.Fprintf(w, "warn: context %s: compartment %s was %s when last checked; run `oci-context validate --record --context %s` to re-check\n", ctx.Name, ctx.CompartmentOCID, state, ctx.Name)
+}
+
+// runWithOutputFile runs fn and, when path is set, also writes everything fn
+// printed to stdout into path (mode 0644). Missing parent directories are
+// created with createDirs; without it a missing parent directory is an error
+// reported before fn runs. With outputOnly, stdout is suppressed and only the
+// file is written.
+func runWithOutputFile(cmd *cobra.Command, path string, outputOnly, createDirs bool, fn func() error) error {
+	if path == "" {
+		return fn()
+	}
+	if !createDirs {
+		if err := checkOutputDir(path); err != nil {
+			return err
+		}
+	}
+	orig := cmd.OutOrStdout()
+	var buf bytes.Buffer
+	if outputOnly {
+		cmd.SetOut(&buf)
+	} else {
+		cmd.SetOut(io.MultiWriter(orig, &buf))
+	}
+	defer cmd.SetOut(orig)
+	if err := fn(); err != nil {
+		return err
+	}
+	return writeOutputFile(path, buf.Bytes())
+}
+
+// checkOutputDir fails unless path's parent directory exists.
+func checkOutputDir(path string) error {
+	dir := filepath.Dir(path)
+	fi, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("--output-file %s: parent directory %s does not exist", path, dir)
+	}
+	if err != nil {
+		return fmt.Errorf("--output-file %s: %w", path, err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("--output-file %s: %s is not a directory", path, dir)
+	}
+	return nil
+}
+
+func writeOutputFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o644)
+}
+
+// infoWriter returns w, or io.Discard under the global --quiet flag. Route
+// progress and banner lines through it; warnings and errors bypass it.
+func infoWriter(w io.Writer) io.Writer {
+	if cliQuiet {
+		return io.Discard
+	}
+	return w
+}
+
+// networkTimeout picks the OCI call timeout: --timeout when given, else
+// options.network_timeout, else def.
+func networkTimeout(cmd *cobra.Command, cfg config.Config, def time.Duration) (time.Duration, error) {
+	if f := cmd.Flags().Lookup("timeout"); f != nil && f.Changed {
+		d, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return 0, err
+		}
+		if d <= 0 {
+			return 0, fmt.Errorf("--timeout must be positive")
+		}
+		return d, nil
+	}
+	return cfg.Options.NetworkTimeoutOr(def)
+}
+
+// printTable writes header and rows as aligned columns for `-o table`.
+func printTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// parseOutputTemplate parses --template for `-o template` before anything is
+// loaded or printed. It returns nil for other output formats.
+func parseOutputTemplate(output, text string) (*template.Template, error) {
+	if !strings.EqualFold(output, "template") {
+		if text != "" {
+			return nil, fmt.Errorf("--template requires -o template")
+		}
+		return nil, nil
+	}
+	if text == "" {
+		return nil, fmt.Errorf("-o template requires --template")
+	}
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplate executes tmpl over data and writes the result with a trailing
+// newline. Nothing is written if execution fails part way.
+func writeTemplate(w io.Writer, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("--template: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// stateDir is where runtime state (daemon socket, token and identity caches)
+// lives: the socket file's directory, else ~/.oci-context (also for TCP
+// and abstract sockets).
+func stateDir(cfg config.Config) (string, error) {
+	base := ""
+	if ipcmsg.IsFilesystemSocket(cfg.Options.SocketPath) {
+		base = filepath.Dir(cfg.Options.SocketPath)
+	}
+	if base == "." || base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".oci-context")
+	}
+	return base, nil
+}
+
+// ociConfigOption is cfg's resolved OCI CLI config path with a --oci-config
+// override applied. The override lasts for this run only and is never saved.
+func ociConfigOption(cfg config.Config) string {
+	if p, err := resolveOCIConfigPath(cfg); err == nil {
+		return p
+	}
+	return cfg.Options.OCIConfigPath
+}
+
+// resolveOCIConfigPath resolves the OCI CLI config file for cfg, honoring
+// --oci-config, then options.oci_config_path, then $OCI_CLI_CONFIG_FILE.
+func resolveOCIConfigPath(cfg config.Config) (string, error) {
+	return ocicfg.ResolveConfigPath(cliOCIConfig, cfg.Options.OCIConfigPath)
+}
+
+// ociContext returns parent carrying cfg's options.api_retries as the retry
+// policy for OCI identity calls made under it.
+func ociContext(parent context.Context, cfg config.Config) context.Context {
+	return oci.WithRetryAttempts(parent, cfg.Options.APIRetryAttempts())
+}
+
+// identityCachePath is the on-disk identity cache under stateDir.
+func identityCachePath(cfg config.Config) (string, error) {
+	dir, err := stateDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "identity.json"), nil
+}
+
+// regionCachePath is the on-disk region subscription cache under stateDir.
+func regionCachePath(cfg config.Config) (string, error) {
+	dir, err := stateDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "regions.json"), nil
+}
+
+// subscribedRegions lists the regions c's tenancy subscribes to through the
+// on-disk region cache, shared by `regions` and the TUI, unless noCache is set.
+func subscribedRegions(ctx context.Context, cfg config.Config, ociCfgPath string, c config.Context, noCache bool) ([]string, error) {
+	if noCache {
+		return listRegionSubscriptions(ctx, ociCfgPath, c.Profile)
+	}
+	path, err := regionCachePath(cfg)
+	if err != nil {
+		return listRegionSubscriptions(ctx, ociCfgPath, c.Profile)
+	}
+	return oci.NewRegionCache(path).Lookup(ctx, c.TenancyOCID, ociCfgPath, c.Profile, listRegionSubscriptions)
+}
+
+// identityFetcher returns fetchIdentity, wrapped in the on-disk identity cache
+// unless noCache is set or the cache location cannot be determined.
+func identityFetcher(cfg config.Config, noCache bool) oci.IdentityFetcher {
+	if noCache {
+		return fetchIdentity
+	}
+	path, err := identityCachePath(cfg)
+	if err != nil {
+		return fetchIdentity
+	}
+	return oci.NewIdentityCache(path).Wrap(fetchIdentity)
+}
+
+// withOCIHint appends a short remedy to classified OCI errors, keeping err in
+// the chain for errors.Is.
+func withOCIHint(err error) error {
+	if hint := oci.ErrorHint(err); hint != "" {
+		return fmt.Errorf("%w (%s)", err, hint)
+	}
+	return err
+}
+
+var braceToken = regexp.MustCompile(`\{[^{}]*\}`)
+
+// expandTemplate replaces {name} tokens with values[name] and returns any
+// tokens that have no value, leaving them in place.
+func expandTemplate(tpl string, values map[string]string) (string, []string) {
+	var unknown []string
+	out := braceToken.ReplaceAllStringFunc(tpl, func(tok string) string {
+		v, ok := values[tok[1:len(tok)-1]]
+		if !ok {
+			unknown = append(unknown, tok)
+			return tok
+		}
+		return v
+	})
+	return out, unknown
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
 )
 
 // pathsEqual normalizes symlinks (macOS /private/tmp) before comparison.
@@ -102,3 +106,138 @@ func TestResolveConfigPath_Explicit(t *testing.T) {
 		t.Fatalf("expected explicit path, got %s", got)
 	}
 }
+
+func TestResolveConfigPath_EnvBeforeProjectDiscovery(t *testing.T) {
+	withTempWd(t, func(tmp string) {
+		touch(t, filepath.Join(tmp, ".oci-context.yml"))
+		envPath := filepath.Join(tmp, "ci", "config.yml")
+		t.Setenv(config.EnvConfigPath, envPath)
+
+		info, err := resolveConfigPathInfo("", false)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if info.Source != "env" || info.Path != envPath {
+			t.Fatalf("want env %s, got %s (%s)", envPath, info.Path, info.Source)
+		}
+		if got, _ := resolveConfigPath("/tmp/custom.yml", false); got != "/tmp/custom.yml" {
+			t.Fatalf("--config should beat the env var, got %s", got)
+		}
+		home, _ := os.UserHomeDir()
+		if got, _ := resolveConfigPath("", true); !pathsEqual(got, filepath.Join(home, ".oci-context", "config.yml")) {
+			t.Fatalf("--global should beat the env var, got %s", got)
+		}
+
+		t.Setenv(config.EnvConfigPath, "")
+		got, err := resolveConfigPath("", false)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if !pathsEqual(got, filepath.Join(tmp, ".oci-context.yml")) {
+			t.Fatalf("empty env var should fall through to discovery, got %s", got)
+		}
+	})
+}
+
+func TestMergeFlagLayersProjectOverGlobal(t *testing.T) {
+	t.Cleanup(func() { cliMerge = false })
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.EnvConfigPath, "")
+	ctx := func(name, region string) config.Context {
+		return config.Context{
+			Name:            name,
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.tenancy.oc1..aaaa",
+			Region:          region,
+		}
+	}
+	globalPath := filepath.Join(home, ".oci-context", "config.yml")
+	if err := os.MkdirAll(filepath.Dir(globalPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := config.Save(globalPath, config.Config{
+		Contexts:       []config.Context{ctx("shared", "us-ashburn-1"), ctx("dev", "us-phoenix-1")},
+		CurrentContext: "shared",
+	}); err != nil {
+		t.Fatalf("save global: %v", err)
+	}
+	withTempWd(t, func(tmp string) {
+		projectPath := filepath.Join(tmp, ".oci-context.yml")
+		if err := config.Save(projectPath, config.Config{
+			Contexts: []config.Context{ctx("dev", "eu-frankfurt-1")},
+		}); err != nil {
+			t.Fatalf("save project: %v", err)
+		}
+
+		execute := func(args ...string) (string, error) {
+			cliMerge = false
+			root := newRootCmd()
+			out := &bytes.Buffer{}
+			root.SetOut(out)
+			root.SetErr(&bytes.Buffer{})
+			root.SetArgs(args)
+			err := root.Execute()
+			return out.String(), err
+		}
+		run := func(args ...string) string {
+			t.Helper()
+			out, err := execute(args...)
+			if err != nil {
+				t.Fatalf("%v: %v", args, err)
+			}
+			return out
+		}
+
+		if out := run("list", "-o", "json"); strings.Contains(out, "shared") {
+			t.Fatalf("expected only the project config without --merge, got %s", out)
+		}
+		out := run("--merge", "list", "-o", "json")
+		if !strings.Contains(out, "shared") || !strings.Contains(out, "eu-frankfurt-1") || strings.Contains(out, "us-phoenix-1") {
+			t.Fatalf("expected project dev layered over global contexts, got %s", out)
+		}
+		if out := run("--merge", "current"); strings.TrimSpace(out) != "shared" {
+			t.Fatalf("expected global current context when the project sets none, got %q", out)
+		}
+
+		// Write commands only ever touch the project file, so --merge is refused.
+		if _, err := execute("--merge", "use", "dev"); err == nil || err.Error() != "--merge is not supported by oci-context use: it reads and writes the resolved config file only" {
+			t.Fatalf("expected use to reject --merge, got %v", err)
+		}
+		run("use", "dev")
+		project, err := config.Load(projectPath)
+		if err != nil {
+			t.Fatalf("load project: %v", err)
+		}
+		global, err := config.Load(globalPath)
+		if err != nil {
+			t.Fatalf("load global: %v", err)
+		}
+		if project.CurrentContext != "dev" || global.CurrentContext != "shared" {
+			t.Fatalf("expected writes to go to the project file, got project=%q global=%q", project.CurrentContext, global.CurrentContext)
+		}
+		if out := run("--merge", "current"); strings.TrimSpace(out) != "dev" {
+			t.Fatalf("expected project current context to win, got %q", out)
+		}
+	})
+}
+
+func TestResolveOCIConfigPathHonorsEnvForDefaultConfig(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	if err := config.Save(cfgPath, config.DefaultConfig(tmp)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	t.Setenv("OCI_CLI_CONFIG_FILE", "/env/oci/config")
+	if got, err := resolveOCIConfigPath(cfg); err != nil || got != "/env/oci/config" {
+		t.Fatalf("expected $OCI_CLI_CONFIG_FILE, got %q %v", got, err)
+	}
+	if got := ociConfigOption(cfg); got != "/env/oci/config" {
+		t.Fatalf("expected ociConfigOption to resolve the env path, got %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newExportConfigCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var names []string
+	var outputPath string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "export-config [--contexts a,b] [-o file.yml]",
+		Short: "Write contexts to a portable file for import-config on another machine",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			contexts := cfg.Contexts
+			if len(names) > 0 {
+				contexts = make([]config.Context, 0, len(names))
+				for _, name := range names {
+					ctx, err := cfg.GetContext(name)
+					if err != nil {
+						return fmt.Errorf("%s: %w", name, err)
+					}
+					contexts = append(contexts, ctx)
+				}
+			}
+			data, err := config.MarshalBundle(contexts, asJSON || (outputPath != "" && strings.EqualFold(filepath.Ext(outputPath), ".json")))
+			if err != nil {
+				return err
+			}
+			if outputPath == "" {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := writeOutputFile(outputPath, data); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d contexts to %s\n", len(contexts), outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringSliceVar(&names, "contexts", nil, "Comma-separated context names to export (default: all)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write to this file instead of stdout (.json selects JSON)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Write JSON instead of YAML")
+	return cmd
+}
+
+func newImportConfigCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var overwrite bool
+
+	cmd := &cobra.Command{
+		Use:   "import-config <file>",
+		Short: "Merge contexts from an export-config file into this config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			bundle, err := config.ReadBundle(args[0])
+			if err != nil {
+				return err
+			}
+			// Validate everything first so a bad entry never leaves a half-merged config.
+			seen := make(map[string]bool, len(bundle.Contexts))
+			for _, ctx := range bundle.Contexts {
+				if err := ctx.Validate(); err != nil {
+					return fmt.Errorf("context %q invalid: %w", ctx.Name, err)
+				}
+				if seen[ctx.Name] {
+					return fmt.Errorf("context %q appears more than once in %s", ctx.Name, args[0])
+				}
+				seen[ctx.Name] = true
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			res, err := mergeContexts(&cfg, bundle.Contexts, overwrite)
+			if err != nil {
+				return err
+			}
+			for _, c := range res.conflicts {
+				fmt.Fprintf(cmd.ErrOrStderr(), "conflict: %s (differs in %s; use --overwrite to replace)\n", c.name, strings.Join(c.fields, ", "))
+			}
+			if len(res.added)+len(res.updated) > 0 {
+				if err := config.Save(path, cfg); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d contexts (updated %d, unchanged %d, conflicts %d) from %s\n",
+				len(res.added), len(res.updated), res.unchanged, len(res.conflicts), args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVarP(&overwrite, "overwrite", "w", false, "Replace existing contexts that differ")
+	return cmd
+}
+
+// mergeConflict is an incoming context whose name exists with other values.
+type mergeConflict struct {
+	name   string
+	fields []string
+}
+
+type mergeResult struct {
+	added     []string
+	updated   []string
+	unchanged int
+	conflicts []mergeConflict
+}
+
+// mergeContexts upserts incoming contexts by name. Contexts that `diff` would
+// call identical are left alone; differing ones are replaced only with
+// overwrite and otherwise reported as conflicts.
+func mergeContexts(cfg *config.Config, incoming []config.Context, overwrite bool) (mergeResult, error) {
+	var res mergeResult
+	for _, ctx := range incoming {
+		existing, err := cfg.GetContext(ctx.Name)
+		if err != nil {
+			if err := cfg.UpsertContext(ctx); err != nil {
+				return res, err
+			}
+			res.added = append(res.added, ctx.Name)
+			continue
+		}
+		diff := contextDiff(existing, ctx)
+		if len(diff) == 0 {
+			res.unchanged++
+			continue
+		}
+		if !overwrite {
+			fields := make([]string, 0, len(diff))
+			for name := range diff {
+				fields = append(fields, name)
+			}
+			sort.Strings(fields)
+			res.conflicts = append(res.conflicts, mergeConflict{name: ctx.Name, fields: fields})
+			continue
+		}
+		if err := cfg.UpsertContext(ctx); err != nil {
+			return res, err
+		}
+		res.updated = append(res.updated, ctx.Name)
+	}
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func TestExportConfigImportConfigMergesByName(t *testing.T) {
+	tmp := t.TempDir()
+	dev := config.Context{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..dev", Region: "us-phoenix-1"}
+	prod := config.Context{Name: "prod", Profile: "PROD", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..prod", Region: "us-ashburn-1"}
+	ops := config.Context{Name: "ops", Profile: "OPS", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..ops", Region: "us-ashburn-1"}
+	srcPath := filepath.Join(tmp, "src.yml")
+	if err := config.Save(srcPath, config.Config{Contexts: []config.Context{dev, prod, ops}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save src: %v", err)
+	}
+	// The destination already has an identical dev and a prod in another region.
+	localProd := prod
+	localProd.Region = "eu-frankfurt-1"
+	dstPath := filepath.Join(tmp, "dst.yml")
+	if err := config.Save(dstPath, config.Config{Contexts: []config.Context{dev, localProd}, CurrentContext: "prod"}); err != nil {
+		t.Fatalf("save dst: %v", err)
+	}
+
+	run := func(cmd *cobra.Command, args ...string) (string, string, error) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	bundlePath := filepath.Join(tmp, "share", "team.yml")
+	out, _, err := run(newExportConfigCmd(), "--config", srcPath, "--contexts", "dev,prod,ops", "-o", bundlePath)
+	if err != nil || !strings.Contains(out, "Exported 3 contexts") {
+		t.Fatalf("export-config: %v (%s)", err, out)
+	}
+	if _, _, err := run(newExportConfigCmd(), "--config", srcPath, "--contexts", "nope"); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown context error, got %v", err)
+	}
+
+	out, errOut, err := run(newImportConfigCmd(), bundlePath, "--config", dstPath)
+	if err != nil {
+		t.Fatalf("import-config: %v", err)
+	}
+	if !strings.Contains(out, "Imported 1 contexts (updated 0, unchanged 1, conflicts 1)") {
+		t.Fatalf("unexpected summary: %q", out)
+	}
+	if !strings.Contains(errOut, "conflict: prod (differs in region;") {
+		t.Fatalf("expected prod conflict, got %q", errOut)
+	}
+	loaded, err := config.Load(dstPath)
+	if err != nil {
+		t.Fatalf("load dst: %v", err)
+	}
+	got, _ := loaded.GetContext("prod")
+	if _, err := loaded.GetContext("ops"); err != nil || got.Region != "eu-frankfurt-1" || loaded.CurrentContext != "prod" {
+		t.Fatalf("expected ops added and local prod/current kept, got %+v", loaded)
+	}
+
+	out, _, err = run(newImportConfigCmd(), bundlePath, "--config", dstPath, "--overwrite")
+	if err != nil || !strings.Contains(out, "updated 1, unchanged 2, conflicts 0") {
+		t.Fatalf("import-config --overwrite: %v (%s)", err, out)
+	}
+	loaded, err = config.Load(dstPath)
+	if err != nil {
+		t.Fatalf("load dst: %v", err)
+	}
+	if got, _ := loaded.GetContext("prod"); got.Region != "us-ashburn-1" {
+		t.Fatalf("expected prod overwritten, got %+v", got)
+	}
+}
+
+func TestImportConfigRejectsInvalidContextsBeforeMerging(t *testing.T) {
+	tmp := t.TempDir()
+	bundlePath := filepath.Join(tmp, "bad.yml")
+	data, err := config.MarshalBundle([]config.Context{
+		{Name: "ok", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..ok"},
+		{Name: "broken", Profile: "DEV"},
+	}, false)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	if err := os.WriteFile(bundlePath, data, 0o644); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	dstPath := filepath.Join(tmp, "dst.yml")
+	if err := config.Save(dstPath, config.Config{}); err != nil {
+		t.Fatalf("save dst: %v", err)
+	}
+	cmd := newImportConfigCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs([]string{bundlePath, "--config", dstPath})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("expected invalid context error, got %v", err)
+	}
+	loaded, err := config.Load(dstPath)
+	if err != nil {
+		t.Fatalf("load dst: %v", err)
+	}
+	if len(loaded.Contexts) != 0 {
+		t.Fatalf("expected nothing merged, got %+v", loaded.Contexts)
+	}
+}
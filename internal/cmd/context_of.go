@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newContextOfCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var output string
+	var compartment, tenancy, region, profile string
+
+	cmd := &cobra.Command{
+		Use:   "context-of --compartment <ocid>|--tenancy <ocid>|--region <name>|--profile <name>",
+		Short: "List contexts that point at a compartment, tenancy, region, or profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			names := contextsMatching(cfg.Contexts, compartment, tenancy, region, profile)
+			if len(names) == 0 {
+				return fmt.Errorf("no context matches")
+			}
+			switch strings.ToLower(output) {
+			case "", "text":
+				for _, name := range names {
+					fmt.Fprintln(cmd.OutOrStdout(), name)
+				}
+				return nil
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(names)
+			case "yaml", "yml":
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				defer enc.Close()
+				return enc.Encode(names)
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json|yaml")
+	cmd.Flags().StringVar(&compartment, "compartment", "", "Compartment OCID")
+	cmd.Flags().StringVar(&tenancy, "tenancy", "", "Tenancy OCID")
+	cmd.Flags().StringVar(&region, "region", "", "Region name")
+	cmd.Flags().StringVar(&profile, "profile", "", "OCI CLI profile")
+	cmd.MarkFlagsOneRequired("compartment", "tenancy", "region", "profile")
+	return cmd
+}
+
+// contextsMatching returns, in config order, the names of contexts matching
+// every non-empty filter. OCIDs compare exactly; region and profile ignore case.
+func contextsMatching(contexts []config.Context, compartment, tenancy, region, profile string) []string {
+	var names []string
+	for _, ctx := range contexts {
+		if compartment != "" && ctx.CompartmentOCID != compartment {
+			continue
+		}
+		if tenancy != "" && ctx.TenancyOCID != tenancy {
+			continue
+		}
+		if region != "" && !strings.EqualFold(ctx.Region, region) {
+			continue
+		}
+		if profile != "" && !strings.EqualFold(ctx.Profile, profile) {
+			continue
+		}
+		names = append(names, ctx.Name)
+	}
+	return names
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestContextOfFindsMatchingContexts(t *testing.T) {
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..app", Region: "us-phoenix-1"},
+			{Name: "dev-east", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..app", Region: "us-ashburn-1"},
+			{Name: "prod", Profile: "PROD", TenancyOCID: "ocid1.tenancy.oc1..bbbb", CompartmentOCID: "ocid1.compartment.oc1..prod", Region: "us-ashburn-1"},
+		},
+	}
+	cfgPath := t.TempDir() + "/config.yml"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newContextOfCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--compartment", "ocid1.compartment.oc1..app"}, "dev\ndev-east\n"},
+		{[]string{"--compartment", "ocid1.compartment.oc1..app", "--region", "US-ASHBURN-1"}, "dev-east\n"},
+		{[]string{"--tenancy", "ocid1.tenancy.oc1..bbbb"}, "prod\n"},
+		{[]string{"--profile", "dev", "-o", "json"}, "[\n  \"dev\",\n  \"dev-east\"\n]\n"},
+	}
+	for _, tc := range cases {
+		got, err := run(tc.args...)
+		if err != nil {
+			t.Fatalf("context-of %v: %v", tc.args, err)
+		}
+		if got != tc.want {
+			t.Fatalf("context-of %v: want %q, got %q", tc.args, tc.want, got)
+		}
+	}
+
+	if _, err := run("--compartment", "ocid1.compartment.oc1..missing"); err == nil {
+		t.Fatalf("expected no-match error")
+	}
+	if _, err := run(); err == nil {
+		t.Fatalf("expected an error without filters")
+	}
+}
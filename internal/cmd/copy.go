@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newCopyCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var overwrite bool
+	var overrides contextOverrides
+
+	cmd := &cobra.Command{
+		Use:     "copy <src> <dst>",
+		Aliases: []string{"clone"},
+		Short:   "Duplicate a context under a new name, with optional overrides",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			src, dst := args[0], args[1]
+			if src == dst {
+				return fmt.Errorf("source and destination must differ")
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			ctx, err := cfg.GetContext(src)
+			if err != nil {
+				return err
+			}
+			if _, err := cfg.GetContext(dst); err == nil && !overwrite {
+				return fmt.Errorf("%s: %w (use --overwrite to replace it)", dst, config.ErrDuplicateName)
+			}
+			ctx.Name = dst
+			if err := overrides.apply(&ctx); err != nil {
+				return err
+			}
+			ctx.Source = config.SourceManual
+			if err := ctx.Validate(); err != nil {
+				return err
+			}
+			if err := cfg.UpsertContext(ctx); err != nil {
+				return err
+			}
+			if err := config.Save(path, cfg); err != nil {
+				return err
+			}
+			if dst == cfg.CurrentContext {
+				if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Copied context %s to %s\n", src, dst)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVarP(&overwrite, "overwrite", "w", false, "Replace dst if it already exists")
+	overrides.addFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestCopyClonesWithOverrides(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	dev := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+		Notes:           "dev notes",
+		Source:          config.SourceImport,
+	}
+	stage := dev
+	stage.Name = "stage"
+	cfg := config.Config{Contexts: []config.Context{dev, stage}, CurrentContext: "dev"}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := newCopyCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("dev", "prod", "--region", "us-ashburn-1", "--compartment", "ocid1.compartment.oc1..prod", "--tag", "env=prod")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if out != "Copied context dev to prod\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if _, err := run("dev", "stage"); !errors.Is(err, config.ErrDuplicateName) {
+		t.Fatalf("expected ErrDuplicateName, got %v", err)
+	}
+	if _, err := run("dev", "stage", "--overwrite", "--notes", "replaced"); err != nil {
+		t.Fatalf("copy --overwrite: %v", err)
+	}
+	if _, err := run("dev", "bad", "--tenancy", "ocid1.compartment.oc1..nope"); err == nil {
+		t.Fatalf("expected invalid tenancy override to fail")
+	}
+
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	gotDev, _ := loaded.GetContext("dev")
+	if !reflect.DeepEqual(gotDev, dev) {
+		t.Fatalf("source context changed: %+v", gotDev)
+	}
+	prod, err := loaded.GetContext("prod")
+	if err != nil {
+		t.Fatalf("prod missing: %v", err)
+	}
+	if prod.Region != "us-ashburn-1" || prod.CompartmentOCID != "ocid1.compartment.oc1..prod" || prod.TenancyOCID != dev.TenancyOCID || prod.Notes != "dev notes" || prod.Source != config.SourceManual || prod.Tags["env"] != "prod" {
+		t.Fatalf("unexpected clone: %+v", prod)
+	}
+	if st, _ := loaded.GetContext("stage"); st.Notes != "replaced" {
+		t.Fatalf("expected stage overwritten, got %+v", st)
+	}
+	if _, err := loaded.GetContext("bad"); err == nil {
+		t.Fatalf("failed copy should not create a context")
+	}
+	if loaded.CurrentContext != "dev" {
+		t.Fatalf("copy should not change current context, got %q", loaded.CurrentContext)
+	}
+}
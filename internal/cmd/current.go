@@ -1,29 +1,40 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/adrianmross/oci-context/pkg/config"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func newCurrentCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var output string
+	var resolve bool
 
 	cmd := &cobra.Command{
-		Use:   "current",
-		Short: "Show the current context name",
+		Use:         "current [-o json|yaml [--resolve]]",
+		Short:       "Show the current context name (or the full context with -o)",
+		Annotations: mergeAnnotations,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useGlobal, err := cmd.Flags().GetBool("global")
 			if err != nil {
 				return err
 			}
-			path, err := resolveConfigPath(cfgPath, useGlobal)
-			if err != nil {
-				return err
+			format := strings.ToLower(output)
+			switch format {
+			case "", "json", "yaml", "yml":
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+			if resolve && format == "" {
+				return fmt.Errorf("--resolve requires -o json or -o yaml")
 			}
-			cfg, err := config.Load(path)
+			cfg, _, err := loadReadConfig(cfgPath, useGlobal)
 			if err != nil {
 				return err
 			}
@@ -34,12 +45,28 @@ func newCurrentCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			var names map[string]resolvedNames
+			if resolve {
+				names = resolveContextNames(cmd, cfg, []config.Context{ctx})
+			}
+			switch format {
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(listViews([]config.Context{ctx}, names)[0])
+			case "yaml", "yml":
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				defer enc.Close()
+				return enc.Encode(listViews([]config.Context{ctx}, names)[0])
+			}
 			fmt.Fprintln(cmd.OutOrStdout(), ctx.Name)
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|yaml (default: the context name only)")
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "With -o, add tenancy and compartment names (OCI calls)")
 	return cmd
 }
 
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -40,6 +42,62 @@ func TestCurrentOutputs(t *testing.T) {
 	}
 }
 
+func TestCurrentStructuredOutputAndResolve(t *testing.T) {
+	ctx := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+		Source:          config.SourceManual,
+	}
+	cfgPath := t.TempDir() + "/config.yml"
+	if err := config.Save(cfgPath, config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}, Contexts: []config.Context{ctx}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newCurrentCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	restore := stubIdentityUnexpected(t)
+	out, err := run("-o", "json")
+	restore()
+	if err != nil {
+		t.Fatalf("current -o json: %v", err)
+	}
+	var got config.Context
+	if err := json.Unmarshal([]byte(out), &got); err != nil || !reflect.DeepEqual(got, ctx) {
+		t.Fatalf("expected the stored context, got %+v (%v)\n%s", got, err, out)
+	}
+	if strings.Contains(out, "tenancy_name") {
+		t.Fatalf("expected no resolved names without --resolve:\n%s", out)
+	}
+
+	defer stubIdentity()()
+	if out, err = run("-o", "json", "--resolve"); err != nil {
+		t.Fatalf("current -o json --resolve: %v", err)
+	}
+	var view map[string]any
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, out)
+	}
+	if view["name"] != "dev" || view["tenancy_name"] != "Tenancy Friendly" || view["compartment_name"] != "Compartment Friendly" {
+		t.Fatalf("unexpected resolved view: %v", view)
+	}
+	if out, err = run("-o", "yaml"); err != nil || !strings.Contains(out, "name: dev\n") {
+		t.Fatalf("current -o yaml: %v\n%s", err, out)
+	}
+	if _, err := run("--resolve"); err == nil || !strings.Contains(err.Error(), "--resolve requires -o json") {
+		t.Fatalf("expected --resolve without -o to fail, got %v", err)
+	}
+}
+
 func TestCurrentNoCurrentContext(t *testing.T) {
 	cfg := config.Config{}
 	tmp := t.TempDir()
@@ -8,9 +8,11 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/adrianmross/oci-context/internal/daemon"
@@ -96,6 +98,7 @@ func newDaemonCmd() *cobra.Command {
 	cmd.AddCommand(newDaemonUpCmd())
 	cmd.AddCommand(newDaemonDoctorCmd())
 	cmd.AddCommand(newDaemonServeCmd())
+	cmd.AddCommand(newDaemonStatusCmd())
 	cmd.AddCommand(newDaemonAuthStatusCmd())
 	cmd.AddCommand(newDaemonNudgeCmd())
 	cmd.AddCommand(newDaemonMonitorCmd())
@@ -778,6 +781,7 @@ func newDaemonServeCmd() *cobra.Command {
 	var validateInterval time.Duration
 	var refreshInterval time.Duration
 	var noRefreshOnValidateError bool
+	var requestTimeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "serve",
@@ -792,19 +796,27 @@ func newDaemonServeCmd() *cobra.Command {
 			opts.ValidateInterval = validateInterval
 			opts.RefreshInterval = refreshInterval
 			opts.RefreshOnValidateError = !noRefreshOnValidateError
+			opts.Version = version
+			opts.RequestTimeout = requestTimeout
 			svc, err := daemon.NewServiceWithOptions(path, opts)
 			if err != nil {
 				return err
 			}
 			fmt.Fprintf(
-				cmd.OutOrStdout(),
+				infoWriter(cmd.OutOrStdout()),
 				"Starting daemon with config %s (auto-refresh=%t validate-interval=%s refresh-interval=%s)\n",
 				path,
 				opts.AutoRefresh,
 				opts.ValidateInterval,
 				opts.RefreshInterval,
 			)
-			return svc.Serve()
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := svc.ServeContext(ctx); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Daemon stopped")
+			return nil
 		},
 	}
 
@@ -813,9 +825,109 @@ func newDaemonServeCmd() *cobra.Command {
 	cmd.Flags().DurationVar(&validateInterval, "validate-interval", 5*time.Minute, "How often to validate auth")
 	cmd.Flags().DurationVar(&refreshInterval, "refresh-interval", 15*time.Minute, "How often to refresh security-token auth")
 	cmd.Flags().BoolVar(&noRefreshOnValidateError, "no-refresh-on-validate-error", false, "Do not auto-refresh security-token on validate failure")
+	cmd.Flags().DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "How long a client may take to send each IPC request")
 	return cmd
 }
 
+type daemonStatusResult struct {
+	Up          bool    `json:"up" yaml:"up"`
+	Socket      string  `json:"socket" yaml:"socket"`
+	LatencyMS   float64 `json:"latency_ms,omitempty" yaml:"latency_ms,omitempty"`
+	PID         int     `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Version     string  `json:"version,omitempty" yaml:"version,omitempty"`
+	StaleSocket bool    `json:"stale_socket,omitempty" yaml:"stale_socket,omitempty"`
+	Error       string  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newDaemonStatusCmd() *cobra.Command {
+	var cfgPath string
+	var output string
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Ping the daemon and report whether it is up",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := daemon.EnsureConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			result := pingDaemon(cfg.Options.SocketPath, timeout)
+			if err := printDaemonOutput(cmd, output, result, func() error {
+				if result.Up {
+					fmt.Fprintf(cmd.OutOrStdout(), "daemon: up pid=%d version=%s latency=%.1fms socket=%s\n", result.PID, result.Version, result.LatencyMS, result.Socket)
+					return nil
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "daemon: down socket=%s error=%s\n", result.Socket, result.Error)
+				return nil
+			}); err != nil {
+				return err
+			}
+			if !result.Up {
+				return fmt.Errorf("daemon is not running")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json|yaml")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Second, "How long to wait for the daemon to answer")
+	return cmd
+}
+
+// pingDaemon sends a ping over the socket. A socket file that refuses
+// connections is reported as stale (left behind by a daemon that exited).
+func pingDaemon(socketPath string, timeout time.Duration) daemonStatusResult {
+	result := daemonStatusResult{Socket: socketPath}
+	start := time.Now()
+	conn, err := ipcmsg.DialTimeout(socketPath, timeout)
+	if err != nil {
+		if !ipcmsg.IsFilesystemSocket(socketPath) {
+			result.Error = err.Error()
+		} else if _, statErr := os.Stat(socketPath); statErr == nil && errors.Is(err, syscall.ECONNREFUSED) {
+			result.StaleSocket = true
+			result.Error = "stale socket (connection refused)"
+		} else if os.IsNotExist(statErr) {
+			result.Error = "socket not found"
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(start.Add(timeout))
+	if err := conn.SendRequest(ipcmsg.Request{Method: "ping"}); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	var resp struct {
+		OK    bool              `json:"ok"`
+		Error string            `json:"error,omitempty"`
+		Data  daemon.PingResult `json:"data,omitempty"`
+	}
+	if err := conn.ReadResponse(&resp); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if !resp.OK {
+		// Daemons that predate ping still answer, so they are up.
+		result.Up = true
+		result.Error = "ping unsupported: " + resp.Error
+		return result
+	}
+	result.Up = resp.Data.Pong
+	result.PID = resp.Data.PID
+	result.Version = resp.Data.Version
+	return result
+}
+
 func newDaemonAuthStatusCmd() *cobra.Command {
 	var cfgPath string
 	var contextName string
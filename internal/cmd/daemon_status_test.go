@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianmross/oci-context/internal/daemon"
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestDaemonStatusPingsRunningDaemon(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "d.sock")
+	cfgPath := filepath.Join(dir, "config.yml")
+	if err := config.Save(cfgPath, config.Config{Options: config.Options{SocketPath: socketPath}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	run := func() (daemonStatusResult, error) {
+		cmd := newDaemonStatusCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"--config", cfgPath, "-o", "json"})
+		err := cmd.Execute()
+		var out daemonStatusResult
+		if jerr := json.Unmarshal(buf.Bytes(), &out); jerr != nil {
+			t.Fatalf("decode output %q: %v", buf.String(), jerr)
+		}
+		return out, err
+	}
+
+	if out, err := run(); err == nil || out.Up || out.Error != "socket not found" {
+		t.Fatalf("expected missing socket to report down, got %+v %v", out, err)
+	}
+
+	// A socket file with no listener behind it is stale.
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	if out, err := run(); err == nil || !out.StaleSocket {
+		t.Fatalf("expected stale socket, got %+v %v", out, err)
+	}
+
+	opts := daemon.DefaultServiceOptions()
+	opts.Version = "v1.2.3"
+	svc, err := daemon.NewServiceWithOptions(cfgPath, opts)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- svc.ServeContext(ctx) }()
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	var out daemonStatusResult
+	for i := 0; i < 100; i++ {
+		if out, err = run(); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil || !out.Up || out.PID != os.Getpid() || out.Version != "v1.2.3" || !strings.HasSuffix(out.Socket, "d.sock") {
+		t.Fatalf("expected daemon up, got %+v %v", out, err)
+	}
+}
+
+func TestPingDaemonBoundsTCPConnect(t *testing.T) {
+	// A non-routable address never answers the SYN, so only the dial
+	// timeout ends the connect.
+	start := time.Now()
+	out := pingDaemon("tcp://10.255.255.1:9", 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the connect to give up after --timeout, took %s", elapsed)
+	}
+	if out.Up || out.Error == "" {
+		t.Fatalf("expected an unreachable daemon to report down, got %+v", out)
+	}
+}
@@ -1,26 +1,53 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/adrianmross/oci-context/pkg/config"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
+// stdinIsTerminal is a seam so tests can exercise the confirmation prompt.
+var stdinIsTerminal = func(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
 func newDeleteCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var all bool
+	var tagFilters []string
+	var yes bool
+	var purgeCache bool
 
 	cmd := &cobra.Command{
-		Use:   "delete <name>",
+		Use:   "delete <name> | --all | --tag key=value",
 		Short: "Delete a context",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useGlobal, err := cmd.Flags().GetBool("global")
 			if err != nil {
 				return err
 			}
-			name := args[0]
+			bulk := all || len(tagFilters) > 0
+			switch {
+			case all && len(tagFilters) > 0:
+				return fmt.Errorf("--all and --tag are mutually exclusive")
+			case bulk && len(args) > 0:
+				return fmt.Errorf("cannot combine a context name with --all or --tag")
+			case !bulk && len(args) == 0:
+				return fmt.Errorf("requires a context name, --all, or --tag")
+			}
+			wantTags, err := config.ParseTags(tagFilters)
+			if err != nil {
+				return fmt.Errorf("invalid --tag filter: %w", err)
+			}
 			path, err := resolveConfigPath(cfgPath, useGlobal)
 			if err != nil {
 				return err
@@ -29,18 +56,104 @@ func newDeleteCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if err := cfg.DeleteContext(name); err != nil {
-				return err
+			if !bulk {
+				name := args[0]
+				deleted, err := cfg.GetContext(name)
+				if err != nil {
+					return err
+				}
+				if !yes {
+					ok, err := confirmDelete(cmd, fmt.Sprintf("Delete context %q?", name))
+					if err != nil {
+						return err
+					}
+					if !ok {
+						return fmt.Errorf("aborted")
+					}
+				}
+				if err := cfg.DeleteContext(name); err != nil {
+					return err
+				}
+				if err := config.Save(path, cfg); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleted context %s\n", name)
+				if purgeCache {
+					return purgeCaches(cmd, cfg, &deleted)
+				}
+				return nil
+			}
+
+			var names []string
+			var deleted []config.Context
+			for _, ctx := range cfg.Contexts {
+				if all || ctx.HasTags(wantTags) {
+					names = append(names, ctx.Name)
+					deleted = append(deleted, ctx)
+				}
+			}
+			if len(names) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Deleted 0 contexts")
+				return nil
+			}
+			if !yes {
+				ok, err := confirmDelete(cmd, fmt.Sprintf("Delete %d contexts (%s)?", len(names), strings.Join(names, ", ")))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("aborted")
+				}
+			}
+			for _, name := range names {
+				if err := cfg.DeleteContext(name); err != nil {
+					return err
+				}
 			}
 			if err := config.Save(path, cfg); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Deleted context %s\n", name)
+			noun := "contexts"
+			if len(names) == 1 {
+				noun = "context"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d %s\n", len(names), noun)
+			if purgeCache {
+				for i := range deleted {
+					if err := purgeCaches(cmd, cfg, &deleted[i]); err != nil {
+						return err
+					}
+				}
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVar(&all, "all", false, "Delete every context")
+	cmd.Flags().StringArrayVar(&tagFilters, "tag", nil, "Delete contexts with tag key=value (repeatable; all must match)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	cmd.Flags().BoolVar(&purgeCache, "purge-cache", false, "Also remove cached identity and daemon compartment data for the deleted contexts")
 	return cmd
 }
+
+// confirmDelete asks question on stdin. It refuses instead of prompting when
+// stdin is not a terminal or --no-interactive is set, so scripts must pass
+// --yes explicitly.
+func confirmDelete(cmd *cobra.Command, question string) (bool, error) {
+	if cliNoInteractive || !stdinIsTerminal(cmd.InOrStdin()) {
+		return false, fmt.Errorf("refusing to delete without confirmation: stdin is not interactive (pass --yes)")
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N] ", question)
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
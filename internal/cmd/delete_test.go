@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestDeleteBulk(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	base := config.Context{
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+	}
+	var contexts []config.Context
+	for _, c := range []struct{ name, env string }{{"dev", "scratch"}, {"tmp", "scratch"}, {"prod", "prod"}} {
+		ctx := base
+		ctx.Name = c.name
+		ctx.Tags = map[string]string{"env": c.env}
+		contexts = append(contexts, ctx)
+	}
+	if err := config.Save(cfgPath, config.Config{Contexts: contexts, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := newDeleteCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	prev := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return true }
+	defer func() { stdinIsTerminal = prev }()
+
+	if _, err := run("", "dev", "--all"); err == nil {
+		t.Fatalf("expected name with --all to fail")
+	}
+	if _, err := run("n\n", "--tag", "env=scratch"); err == nil || !strings.Contains(err.Error(), "aborted") {
+		t.Fatalf("expected declined prompt to abort, got %v", err)
+	}
+	out, err := run("y\n", "--tag", "env=scratch")
+	if err != nil {
+		t.Fatalf("delete --tag: %v", err)
+	}
+	if out != "Deleted 2 contexts\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(loaded.Contexts) != 1 || loaded.Contexts[0].Name != "prod" || loaded.CurrentContext != "" {
+		t.Fatalf("unexpected config after tag delete: %+v", loaded)
+	}
+
+	if out, err := run("", "--all", "--yes"); err != nil || out != "Deleted 1 context\n" {
+		t.Fatalf("delete --all --yes: %q %v", out, err)
+	}
+	loaded, err = config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(loaded.Contexts) != 0 {
+		t.Fatalf("expected no contexts, got %+v", loaded.Contexts)
+	}
+}
+
+func TestDeleteSingleConfirms(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	ctx := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+	}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{ctx}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	interactive := false
+	prev := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return interactive }
+	defer func() { stdinIsTerminal = prev }()
+
+	run := func(stdin string, args ...string) (string, string, error) {
+		cmd := newDeleteCmd()
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	if _, _, err := run("y\n", "dev"); err == nil || !strings.Contains(err.Error(), "pass --yes") {
+		t.Fatalf("expected non-tty delete to require --yes, got %v", err)
+	}
+	interactive = true
+	if _, prompt, err := run("\n", "dev"); err == nil || prompt != "Delete context \"dev\"? [y/N] " {
+		t.Fatalf("expected default answer to abort, got prompt %q err %v", prompt, err)
+	}
+	if _, _, err := run("y\n", "nope"); err == nil {
+		t.Fatalf("expected missing context to fail before prompting")
+	}
+	interactive = false
+	out, _, err := run("", "dev", "-y")
+	if err != nil || out != "Deleted context dev\n" {
+		t.Fatalf("delete -y: %q %v", out, err)
+	}
+}
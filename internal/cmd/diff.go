@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// diffValues is one differing field in `diff -o json|yaml`.
+type diffValues struct {
+	A string `json:"a" yaml:"a"`
+	B string `json:"b" yaml:"b"`
+}
+
+// diffField names a compared context field and how to read it.
+type diffField struct {
+	name string
+	get  func(config.Context) string
+}
+
+var diffFields = []diffField{
+	{"profile", func(c config.Context) string { return c.Profile }},
+	{"auth", func(c config.Context) string { return config.NormalizeAuthMethod(c.AuthMethod) }},
+	{"tenancy", func(c config.Context) string { return c.TenancyOCID }},
+	{"compartment", func(c config.Context) string { return c.CompartmentOCID }},
+	{"region", func(c config.Context) string { return c.Region }},
+	{"user", func(c config.Context) string { return c.User }},
+	{"tags", func(c config.Context) string { return config.FormatTags(c.Tags) }},
+	{"notes", func(c config.Context) string { return c.Notes }},
+}
+
+var diffMismatchStyle = lipgloss.NewStyle().Foreground(statusErrColor).Bold(true)
+
+func newDiffCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var output string
+
+	cmd := &cobra.Command{
+		Use:         "diff <a> <b>",
+		Short:       "Compare two contexts field by field",
+		Annotations: mergeAnnotations,
+		Args:        cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			cfg, _, err := loadReadConfig(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			a, err := cfg.GetContext(args[0])
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			b, err := cfg.GetContext(args[1])
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[1], err)
+			}
+			switch strings.ToLower(output) {
+			case "", "text":
+				printContextDiff(cmd.OutOrStdout(), a, b)
+				return nil
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(contextDiff(a, b))
+			case "yaml", "yml":
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				defer enc.Close()
+				return enc.Encode(contextDiff(a, b))
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json|yaml")
+	return cmd
+}
+
+// contextDiff returns only the fields whose values differ between a and b.
+func contextDiff(a, b config.Context) map[string]diffValues {
+	out := map[string]diffValues{}
+	for _, f := range diffFields {
+		va, vb := f.get(a), f.get(b)
+		if va != vb {
+			out[f.name] = diffValues{A: va, B: vb}
+		}
+	}
+	return out
+}
+
+// printContextDiff prints every field in aligned columns, marking mismatches
+// with "~" and highlighting them on color terminals.
+func printContextDiff(w io.Writer, a, b config.Context) {
+	nameWidth, aWidth := len("field"), len(a.Name)
+	for _, f := range diffFields {
+		nameWidth = max(nameWidth, len(f.name))
+		aWidth = max(aWidth, len(f.get(a)))
+	}
+	row := func(mark, name, va, vb string) string {
+		return fmt.Sprintf("%s %-*s  %-*s  %s", mark, nameWidth, name, aWidth, va, vb)
+	}
+	fmt.Fprintln(w, strings.TrimRight(row(" ", "field", a.Name, b.Name), " "))
+	differ := 0
+	for _, f := range diffFields {
+		va, vb := f.get(a), f.get(b)
+		if va == vb {
+			fmt.Fprintln(w, strings.TrimRight(row(" ", f.name, va, vb), " "))
+			continue
+		}
+		differ++
+		fmt.Fprintln(w, diffMismatchStyle.Render(strings.TrimRight(row("~", f.name, va, vb), " ")))
+	}
+	if differ == 0 {
+		fmt.Fprintf(w, "Contexts %s and %s are identical\n", a.Name, b.Name)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestDiffContexts(t *testing.T) {
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "staging", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..stg", Region: "us-phoenix-1"},
+			{Name: "prod", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..prd", Region: "us-ashburn-1", Notes: "careful"},
+		},
+	}
+	cfgPath := t.TempDir() + "/config.yml"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newDiffCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("staging", "prod")
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	want := strings.Join([]string{
+		"  field        staging                     prod",
+		"  profile      DEFAULT                     DEFAULT",
+		"  auth         api_key                     api_key",
+		"  tenancy      ocid1.tenancy.oc1..aaaa     ocid1.tenancy.oc1..aaaa",
+		"~ compartment  ocid1.compartment.oc1..stg  ocid1.compartment.oc1..prd",
+		"~ region       us-phoenix-1                us-ashburn-1",
+		"  user",
+		"  tags",
+		"~ notes                                    careful",
+		"",
+	}, "\n")
+	if out != want {
+		t.Fatalf("output mismatch\nwant:\n%s\ngot:\n%s", want, out)
+	}
+
+	out, err = run("staging", "prod", "-o", "json")
+	if err != nil {
+		t.Fatalf("diff json: %v", err)
+	}
+	var got map[string]diffValues
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(got) != 3 || got["region"] != (diffValues{A: "us-phoenix-1", B: "us-ashburn-1"}) || got["notes"].B != "careful" {
+		t.Fatalf("unexpected json diff: %+v", got)
+	}
+
+	out, err = run("staging", "staging")
+	if err != nil || !strings.HasSuffix(out, "Contexts staging and staging are identical\n") {
+		t.Fatalf("expected identical message, got %q (%v)", out, err)
+	}
+
+	if _, err := run("staging", "missing"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected missing context error, got %v", err)
+	}
+}
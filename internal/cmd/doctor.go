@@ -118,7 +118,7 @@ func buildDoctorResult(cmd *cobra.Command, configPath string, cfg config.Config,
 			Region:     ctx.Region,
 			AuthMethod: method,
 		},
-		OCIConfig: inspectPath(cfg.Options.OCIConfigPath),
+		OCIConfig: inspectPath(ociConfigOption(cfg)),
 		OCICLI:    inspectOCICLI(cmd.Context()),
 		Daemon: doctorDaemonStatus{
 			Socket: cfg.Options.SocketPath,
@@ -89,7 +89,7 @@ func TestDoctorJSONUsesBestEffortProbes(t *testing.T) {
 	if got.ConfigPath != tmp+"/config.yml" || got.CurrentContext != "dev" {
 		t.Fatalf("unexpected config context fields: %+v", got)
 	}
-	if !got.OCIConfig.Exists || !got.OCIConfig.IsFile {
+	if got.OCIConfig.Path != ociConfigPath || !got.OCIConfig.Exists || !got.OCIConfig.IsFile {
 		t.Fatalf("expected OCI config presence, got %+v", got.OCIConfig)
 	}
 	if !got.OCICLI.Available || got.OCICLI.Version != "3.55.0" {
@@ -167,3 +167,72 @@ func TestDoctorTextIncludesBestEffortFailures(t *testing.T) {
 		}
 	}
 }
+
+// runDoctorJSONWithOCIOption runs `doctor -o json` through the root command
+// with every probe failing fast, so only the reported paths matter.
+func runDoctorJSONWithOCIOption(t *testing.T, ociConfigOption string, args ...string) doctorResult {
+	t.Helper()
+	origCapture := runOCICaptureForAuth
+	origRun := runOCIForAuth
+	origLookPath := lookPathForDoctor
+	origFetchDaemon := fetchDaemonAuthStatusForDoctor
+	t.Cleanup(func() {
+		runOCICaptureForAuth = origCapture
+		runOCIForAuth = origRun
+		lookPathForDoctor = origLookPath
+		fetchDaemonAuthStatusForDoctor = origFetchDaemon
+		cliOCIConfig = ""
+	})
+	runOCICaptureForAuth = func(_ *cobra.Command, _ []string) ([]byte, error) { return nil, fmt.Errorf("expired") }
+	runOCIForAuth = func(_ *cobra.Command, _ []string) error { return fmt.Errorf("refresh failed") }
+	lookPathForDoctor = func(file string) (string, error) { return "", fmt.Errorf("%s not found", file) }
+	fetchDaemonAuthStatusForDoctor = func(_ config.Config, _ string) (daemonpkg.AuthStatus, error) {
+		return daemonpkg.AuthStatus{}, fmt.Errorf("socket missing")
+	}
+
+	tmp := t.TempDir()
+	cfgPath := tmp + "/config.yml"
+	if err := config.Save(cfgPath, config.Config{
+		Options: config.Options{OCIConfigPath: ociConfigOption, SocketPath: tmp + "/missing.sock"},
+		Contexts: []config.Context{{
+			Name:        "dev",
+			Profile:     "DEFAULT",
+			AuthMethod:  config.AuthMethodSecurityToken,
+			TenancyOCID: "ocid1.tenancy.oc1..aaaa",
+			Region:      "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	root := newRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"doctor", "--config", cfgPath, "--output", "json"}, args...))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("doctor: %v\n%s", err, out.String())
+	}
+	var got doctorResult
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal doctor json: %v\n%s", err, out.String())
+	}
+	return got
+}
+
+func TestDoctorReportsOCIConfigFromEnv(t *testing.T) {
+	envPath := t.TempDir() + "/env-oci-config"
+	t.Setenv("OCI_CLI_CONFIG_FILE", envPath)
+	if got := runDoctorJSONWithOCIOption(t, ""); got.OCIConfig.Path != envPath {
+		t.Fatalf("expected doctor to report $OCI_CLI_CONFIG_FILE %s, got %+v", envPath, got.OCIConfig)
+	}
+}
+
+func TestDoctorReportsOCIConfigFromFlag(t *testing.T) {
+	flagPath := t.TempDir() + "/flag-oci-config"
+	t.Setenv("OCI_CLI_CONFIG_FILE", "/env/oci-config")
+	if got := runDoctorJSONWithOCIOption(t, "/option/oci-config", "--oci-config", flagPath); got.OCIConfig.Path != flagPath {
+		t.Fatalf("expected --oci-config %s to win over the option and env, got %+v", flagPath, got.OCIConfig)
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// runEditor is a seam so tests can edit the file without launching a real editor.
+var runEditor = func(argv []string, path string, stdin io.Reader, stdout, stderr io.Writer) error {
+	c := exec.Command(argv[0], append(argv[1:], path)...)
+	c.Stdin = stdin
+	c.Stdout = stdout
+	c.Stderr = stderr
+	return c.Run()
+}
+
+func newEditCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config in $EDITOR and validate it on save",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			// Snapshot under the lock, then release it while the editor is open.
+			before, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			backup, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			if err := runEditor(editorCommand(), path, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				return fmt.Errorf("editor: %w", err)
+			}
+
+			after, err := config.LoadEdited(path, backup)
+			if err != nil {
+				return fmt.Errorf("edit rejected, restored %s: %w", path, err)
+			}
+			changes := configChanges(before, after)
+			if len(changes) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No changes")
+				return nil
+			}
+			for _, line := range changes {
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			}
+			return syncOCIDefaultsForCurrent(after)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	return cmd
+}
+
+// editorCommand splits $EDITOR so values like "code --wait" work, falling back to vi.
+func editorCommand() []string {
+	if fields := strings.Fields(os.Getenv("EDITOR")); len(fields) > 0 {
+		return fields
+	}
+	return []string{"vi"}
+}
+
+// configChanges summarizes before -> after as +/-/~ lines.
+func configChanges(before, after config.Config) []string {
+	var out []string
+	if before.CurrentContext != after.CurrentContext {
+		out = append(out, fmt.Sprintf("~ current_context: %s -> %s", before.CurrentContext, after.CurrentContext))
+	}
+	if before.CurrentService != after.CurrentService {
+		out = append(out, fmt.Sprintf("~ current_service: %s -> %s", before.CurrentService, after.CurrentService))
+	}
+	out = append(out, fieldChanges("options", before.Options, after.Options)...)
+	if !reflect.DeepEqual(before.TokenServices, after.TokenServices) {
+		out = append(out, "~ token_services")
+	}
+
+	old := make(map[string]config.Context, len(before.Contexts))
+	for _, ctx := range before.Contexts {
+		old[ctx.Name] = ctx
+	}
+	seen := make(map[string]bool, len(after.Contexts))
+	for _, ctx := range after.Contexts {
+		seen[ctx.Name] = true
+		prev, ok := old[ctx.Name]
+		if !ok {
+			out = append(out, "+ context "+ctx.Name)
+			continue
+		}
+		out = append(out, fieldChanges("contexts."+ctx.Name, prev, ctx)...)
+	}
+	var removed []string
+	for name := range old {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		out = append(out, "- context "+name)
+	}
+	return out
+}
+
+// fieldChanges compares two structs of the same type field by field, naming
+// fields by their yaml key.
+func fieldChanges(prefix string, a, b any) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := va.Type()
+	var out []string
+	for i := 0; i < t.NumField(); i++ {
+		fa, fb := va.Field(i).Interface(), vb.Field(i).Interface()
+		if reflect.DeepEqual(fa, fb) {
+			continue
+		}
+		key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		out = append(out, fmt.Sprintf("~ %s.%s: %s -> %s", prefix, key, formatFieldValue(fa), formatFieldValue(fb)))
+	}
+	return out
+}
+
+func formatFieldValue(v any) string {
+	switch x := v.(type) {
+	case map[string]string:
+		return "{" + config.FormatTags(x) + "}"
+	case string:
+		if x == "" {
+			return `""`
+		}
+		return x
+	case []string:
+		return "[" + strings.Join(x, ",") + "]"
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestEditValidatesAndSummarizesChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	dev := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+		Source:          config.SourceManual,
+	}
+	old := dev
+	old.Name = "old"
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{dev, old}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	var edit func(path string) error
+	prev := runEditor
+	runEditor = func(argv []string, path string, _ io.Reader, _, _ io.Writer) error {
+		if strings.Join(argv, " ") != "myeditor --wait" {
+			t.Fatalf("unexpected editor argv: %v", argv)
+		}
+		return edit(path)
+	}
+	defer func() { runEditor = prev }()
+	t.Setenv("EDITOR", "myeditor --wait")
+
+	run := func() (string, error) {
+		cmd := newEditCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs([]string{"--config", cfgPath})
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	edit = func(path string) error {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(strings.Replace(string(b), "curr", "curent", 1)), 0o600)
+	}
+	before, _ := os.ReadFile(cfgPath)
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "edit rejected") {
+		t.Fatalf("expected invalid edit to be rejected, got %v", err)
+	}
+	after, _ := os.ReadFile(cfgPath)
+	if !bytes.Equal(before, after) {
+		t.Fatalf("expected original config restored, got:\n%s", after)
+	}
+
+	edit = func(path string) error {
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		prod := dev
+		prod.Name = "prod"
+		cfg.Contexts = []config.Context{cfg.Contexts[0], prod}
+		cfg.Contexts[0].Region = "us-ashburn-1"
+		return config.Save(path, cfg)
+	}
+	out, err := run()
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	want := "~ contexts.dev.region: us-phoenix-1 -> us-ashburn-1\n+ context prod\n- context old\n"
+	if out != want {
+		t.Fatalf("unexpected summary\nwant:\n%s\ngot:\n%s", want, out)
+	}
+
+	edit = func(string) error { return errors.New("exit status 1") }
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "editor:") {
+		t.Fatalf("expected editor failure, got %v", err)
+	}
+}
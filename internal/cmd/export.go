@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/adrianmross/oci-context/pkg/config"
@@ -17,79 +21,188 @@ type exportContextView struct {
 func newExportCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var fromEnv bool
 	var format string
+	var outputPath string
+	var appendOutput bool
+	var all bool
+	var contextName string
+	var inherit bool
+	var varStyle string
 
 	cmd := &cobra.Command{
-		Use:   "export",
-		Short: "Export current context as env or json",
+		Use:         "export",
+		Short:       "Export current context as env, dotenv, or json",
+		Annotations: mergeAnnotations,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			useGlobal, err := cmd.Flags().GetBool("global")
-			if err != nil {
-				return err
-			}
-			path, err := resolveConfigPath(cfgPath, useGlobal)
-			if err != nil {
-				return err
+			if appendOutput && outputPath == "" {
+				return fmt.Errorf("--append requires --output")
 			}
-			cfg, err := config.Load(path)
+			style, err := config.ParseEnvStyle(varStyle)
 			if err != nil {
 				return err
 			}
-			if cfg.CurrentContext == "" {
-				return fmt.Errorf("no current context set")
+			if cmd.Flags().Changed("var-style") && format != "env" && format != "dotenv" && format != "" {
+				return fmt.Errorf("--var-style only applies to the env and dotenv formats")
 			}
-			ctx, err := cfg.GetContext(cfg.CurrentContext)
+			cfg, _, err := loadConfigForCommand(cmd, cfgPath, fromEnv)
 			if err != nil {
 				return err
 			}
-
-			switch format {
-			case "env", "":
-				lines := []string{}
-				if ctx.Profile != "" {
-					lines = append(lines, fmt.Sprintf("export OCI_CLI_PROFILE=%s", ctx.Profile))
-				}
-				if ctx.Region != "" {
-					lines = append(lines, fmt.Sprintf("export OCI_CLI_REGION=%s", ctx.Region))
+			out := &bytes.Buffer{}
+			if all {
+				if err := writeAllContextsExport(out, cfg, format, style); err != nil {
+					return err
 				}
-				if cfg.Options.OCIConfigPath != "" {
-					lines = append(lines, fmt.Sprintf("export OCI_CLI_CONFIG_FILE=%s", cfg.Options.OCIConfigPath))
+			} else {
+				if contextName != "" && format == "oci-env" {
+					return fmt.Errorf("--context does not support the oci-env format")
 				}
-				lines = append(lines,
-					fmt.Sprintf("export OCI_TENANCY_OCID=%s", ctx.TenancyOCID),
-					fmt.Sprintf("export OCI_COMPARTMENT_OCID=%s", ctx.CompartmentOCID),
-				)
-				if ctx.Region != "" {
-					lines = append(lines, fmt.Sprintf("export OCI_REGION=%s", ctx.Region))
+				if inherit && format != "env" && format != "" {
+					return fmt.Errorf("--inherit only supports the env format")
 				}
-				fmt.Fprintln(cmd.OutOrStdout(), strings.Join(lines, "\n"))
-			case "oci-env":
-				if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+				ctx, err := targetContext(cfg, contextName)
+				if err != nil {
 					return err
 				}
-				rcPath, err := managedOCIRCPath()
-				if err != nil {
+				if inherit {
+					writeInheritedEnvExport(out, cfg, ctx, style, os.LookupEnv)
+				} else if err := writeContextExport(out, cfg, ctx, format, style); err != nil {
 					return err
 				}
-				fmt.Fprintln(cmd.OutOrStdout(), strings.Join(ociEnvExportLines(cfg, rcPath), "\n"))
-			case "json":
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				if err := enc.Encode(exportContextView{
-					Context:        ctx,
-					CurrentService: cfg.CurrentService,
-				}); err != nil {
+			}
+			if outputPath != "" {
+				if err := writeExportFile(outputPath, out.Bytes(), appendOutput); err != nil {
 					return err
 				}
-			default:
-				return fmt.Errorf("unsupported format: %s", format)
+				fmt.Fprintf(infoWriter(cmd.ErrOrStderr()), "Wrote %s\n", outputPath)
+				return nil
 			}
-			return nil
+			_, err = cmd.OutOrStdout().Write(out.Bytes())
+			return err
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
-	cmd.Flags().StringVarP(&format, "format", "f", "env", "Output format: env|json|oci-env")
+	cmd.Flags().BoolVar(&fromEnv, "from-env", false, "Use an ephemeral context from OCI_CONTEXT_* environment variables instead of the config file")
+	cmd.Flags().StringVarP(&format, "format", "f", "env", "Output format: env|dotenv|json|oci-env")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write to this file (mode 0600) instead of stdout")
+	cmd.Flags().BoolVar(&appendOutput, "append", false, "With --output, append instead of overwriting")
+	cmd.Flags().BoolVar(&all, "all", false, "Export every context: one commented block each, or a JSON object keyed by name")
+	cmd.Flags().StringVarP(&contextName, "context", "x", "", "Export this context instead of the current one (does not switch)")
+	cmd.Flags().BoolVar(&inherit, "inherit", false, "Only emit env lines that change the current environment, with unset for variables the context leaves empty")
+	cmd.Flags().StringVar(&varStyle, "var-style", config.EnvStyleOCIContext, "Variable names for env and dotenv: oci-context (OCI_TENANCY_OCID, OCI_REGION) or oci-cli (OCI_CLI_TENANCY, OCI_CLI_REGION)")
+	cmd.MarkFlagsMutuallyExclusive("all", "context")
+	cmd.MarkFlagsMutuallyExclusive("all", "inherit")
 	return cmd
 }
+
+// writeContextExport renders one context in format, naming env and dotenv
+// variables in style.
+func writeContextExport(out io.Writer, cfg config.Config, ctx config.Context, format, style string) error {
+	switch format {
+	case "env", "":
+		for _, kv := range config.ExportEnv(ctx, ociConfigOption(cfg), style) {
+			fmt.Fprintf(out, "export %s=%s\n", kv[0], kv[1])
+		}
+	case "dotenv":
+		for _, kv := range config.ExportEnv(ctx, ociConfigOption(cfg), style) {
+			fmt.Fprintf(out, "%s=%s\n", kv[0], kv[1])
+		}
+	case "oci-env":
+		if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+			return err
+		}
+		rcPath, err := managedOCIRCPath()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, strings.Join(ociEnvExportLines(cfg, rcPath), "\n"))
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(exportContextView{
+			Context:        ctx,
+			CurrentService: cfg.CurrentService,
+		}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	return nil
+}
+
+// writeAllContextsExport renders every context in config order. env and
+// dotenv get a "# context: <name>" header per block; json is one object keyed
+// by context name.
+func writeAllContextsExport(out io.Writer, cfg config.Config, format, style string) error {
+	switch format {
+	case "env", "", "dotenv":
+		for i, ctx := range cfg.Contexts {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "# context: %s\n", ctx.Name)
+			if err := writeContextExport(out, cfg, ctx, format, style); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		views := make(map[string]exportContextView, len(cfg.Contexts))
+		for _, ctx := range cfg.Contexts {
+			views[ctx.Name] = exportContextView{Context: ctx}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(views)
+	case "oci-env":
+		return fmt.Errorf("--all does not support the oci-env format")
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeInheritedEnvExport writes the env format minus variables lookup already
+// has at the same value. Variables set in the environment but empty for ctx
+// are unset.
+func writeInheritedEnvExport(out io.Writer, cfg config.Config, ctx config.Context, style string, lookup func(string) (string, bool)) {
+	want := map[string]string{}
+	for _, kv := range config.ExportEnv(ctx, ociConfigOption(cfg), style) {
+		want[kv[0]] = kv[1]
+	}
+	for _, name := range config.EnvNames(style) {
+		have, set := lookup(name)
+		switch val := want[name]; {
+		case val == "" && set && have != "":
+			fmt.Fprintf(out, "unset %s\n", name)
+		case val != "" && (!set || have != val):
+			fmt.Fprintf(out, "export %s=%s\n", name, val)
+		}
+	}
+}
+
+// writeExportFile writes or appends data to path and keeps it private (0600).
+func writeExportFile(path string, data []byte, appendOutput bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendOutput {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestExportDotenvToFile(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	envPath := filepath.Join(tmp, "compose", ".env")
+
+	run := func(args ...string) (string, error) {
+		cmd := newExportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append([]string{"--config", cfgPath}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	want := strings.Join([]string{
+		"OCI_CLI_PROFILE=DEFAULT",
+		"OCI_CLI_REGION=us-phoenix-1",
+		"OCI_CLI_CONFIG_FILE=/tmp/oci",
+		"OCI_TENANCY_OCID=ocid1.tenancy.oc1..aaaa",
+		"OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..bbbb",
+		"OCI_REGION=us-phoenix-1",
+		"",
+	}, "\n")
+	got, err := run("--format", "dotenv")
+	if err != nil {
+		t.Fatalf("export dotenv: %v", err)
+	}
+	if got != want {
+		t.Fatalf("dotenv mismatch\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(envPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(envPath, []byte("STALE=1\n"), 0o644); err != nil {
+		t.Fatalf("seed env file: %v", err)
+	}
+	if got, err := run("-f", "dotenv", "--output", envPath); err != nil || got != "" {
+		t.Fatalf("export --output: out=%q err=%v", got, err)
+	}
+	b, _ := os.ReadFile(envPath)
+	if string(b) != want {
+		t.Fatalf("expected overwrite, got:\n%s", b)
+	}
+	if fi, err := os.Stat(envPath); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 env file, got %v (%v)", fi.Mode().Perm(), err)
+	}
+
+	if _, err := run("-f", "dotenv", "--output", envPath, "--append"); err != nil {
+		t.Fatalf("export --append: %v", err)
+	}
+	b, _ = os.ReadFile(envPath)
+	if string(b) != want+want {
+		t.Fatalf("expected appended content, got:\n%s", b)
+	}
+
+	if _, err := run("--append"); err == nil || !strings.Contains(err.Error(), "--append requires --output") {
+		t.Fatalf("expected --append validation error, got %v", err)
+	}
+}
+
+func TestExportAllContexts(t *testing.T) {
+	t.Setenv("OCI_CLI_CONFIG_FILE", "/tmp/oci-env")
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev", CompartmentOCID: "ocid1.compartment.oc1..dev", Region: "us-phoenix-1"},
+			{Name: "prod", Profile: "PROD", TenancyOCID: "ocid1.tenancy.oc1..prod", CompartmentOCID: "ocid1.compartment.oc1..prod"},
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newExportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--all"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	want := strings.Join([]string{
+		"# context: dev",
+		"OCI_CLI_PROFILE=DEV",
+		"OCI_CLI_REGION=us-phoenix-1",
+		"OCI_CLI_CONFIG_FILE=/tmp/oci-env",
+		"OCI_TENANCY_OCID=ocid1.tenancy.oc1..dev",
+		"OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..dev",
+		"OCI_REGION=us-phoenix-1",
+		"",
+		"# context: prod",
+		"OCI_CLI_PROFILE=PROD",
+		"OCI_CLI_CONFIG_FILE=/tmp/oci-env",
+		"OCI_TENANCY_OCID=ocid1.tenancy.oc1..prod",
+		"OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..prod",
+		"",
+	}, "\n")
+	got, err := run("--format", "dotenv")
+	if err != nil {
+		t.Fatalf("export --all: %v", err)
+	}
+	if got != want {
+		t.Fatalf("dotenv mismatch\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	got, err = run("--format", "json")
+	if err != nil {
+		t.Fatalf("export --all json: %v", err)
+	}
+	var views map[string]config.Context
+	if err := json.Unmarshal([]byte(got), &views); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, got)
+	}
+	if len(views) != 2 || views["prod"].Profile != "PROD" {
+		t.Fatalf("unexpected json: %s", got)
+	}
+
+	if _, err := run("--format", "oci-env"); err == nil {
+		t.Fatalf("expected oci-env with --all to fail")
+	}
+}
+
+func TestExportContextFlagDoesNotSwitch(t *testing.T) {
+	t.Setenv("OCI_CLI_CONFIG_FILE", "/tmp/oci-env")
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev"},
+			{Name: "prod", Profile: "PROD", TenancyOCID: "ocid1.tenancy.oc1..prod", CompartmentOCID: "ocid1.compartment.oc1..prod"},
+		},
+		CurrentContext: "missing",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newExportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--config", cfgPath}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	got, err := run("-x", "prod", "--format", "dotenv")
+	if err != nil {
+		t.Fatalf("export --context: %v", err)
+	}
+	want := "OCI_CLI_PROFILE=PROD\nOCI_CLI_CONFIG_FILE=/tmp/oci-env\nOCI_TENANCY_OCID=ocid1.tenancy.oc1..prod\nOCI_COMPARTMENT_OCID=ocid1.compartment.oc1..prod\n"
+	if got != want {
+		t.Fatalf("dotenv mismatch\nwant: %q\ngot:  %q", want, got)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.CurrentContext != "missing" {
+		t.Fatalf("current context changed to %q", loaded.CurrentContext)
+	}
+
+	if _, err := run("--context", "nope"); err == nil || !strings.Contains(err.Error(), "nope:") {
+		t.Fatalf("expected unknown context error, got %v", err)
+	}
+	if _, err := run("--context", "prod", "--all"); err == nil {
+		t.Fatalf("expected --context with --all to fail")
+	}
+	if _, err := run("--context", "prod", "--format", "oci-env"); err == nil {
+		t.Fatalf("expected --context with oci-env to fail")
+	}
+}
+
+func TestExportInheritEmitsOnlyChanges(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEV",
+			TenancyOCID:     "ocid1.tenancy.oc1..dev",
+			CompartmentOCID: "ocid1.compartment.oc1..dev",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	for _, name := range config.EnvNames(config.EnvStyleOCIContext) {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	t.Setenv("OCI_CLI_PROFILE", "DEV")
+	// The resolved OCI config path comes from the environment, so it is unchanged.
+	t.Setenv("OCI_CLI_CONFIG_FILE", "/tmp/oci-env")
+	t.Setenv("OCI_TENANCY_OCID", "ocid1.tenancy.oc1..other")
+	t.Setenv("OCI_REGION", "us-phoenix-1")
+	run := func(args ...string) (string, error) {
+		cmd := newExportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--inherit"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	got, err := run()
+	if err != nil {
+		t.Fatalf("export --inherit: %v", err)
+	}
+	want := "export OCI_TENANCY_OCID=ocid1.tenancy.oc1..dev\nexport OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..dev\nunset OCI_REGION\n"
+	if got != want {
+		t.Fatalf("inherit mismatch\nwant: %q\ngot:  %q", want, got)
+	}
+	if _, err := run("--format", "dotenv"); err == nil {
+		t.Fatalf("expected --inherit with dotenv to fail")
+	}
+}
+
+func TestExportVarStyleOCICLI(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newExportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--config", cfgPath}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	got, err := run("--var-style", "oci-cli")
+	if err != nil {
+		t.Fatalf("export --var-style oci-cli: %v", err)
+	}
+	want := strings.Join([]string{
+		"export OCI_CLI_PROFILE=DEFAULT",
+		"export OCI_CLI_REGION=us-phoenix-1",
+		"export OCI_CLI_CONFIG_FILE=/tmp/oci",
+		"export OCI_CLI_TENANCY=ocid1.tenancy.oc1..aaaa",
+		"export OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..bbbb",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("oci-cli style mismatch\nwant:\n%s\ngot:\n%s", want, got)
+	}
+	if _, err := run("--var-style", "terraform"); err == nil || !strings.Contains(err.Error(), `unsupported variable style "terraform"`) {
+		t.Fatalf("expected an unknown style error, got %v", err)
+	}
+	if _, err := run("--var-style", "oci-cli", "--format", "json"); err == nil {
+		t.Fatalf("expected --var-style with json to fail")
+	}
+}
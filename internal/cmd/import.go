@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
 	"github.com/adrianmross/oci-context/pkg/ocicfg"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +20,13 @@ func newImportCmd() *cobra.Command {
 	var useGlobal bool
 	var ociCfgPath string
 	var overwrite bool
+	var prune bool
+	var dryRun bool
+	var nameTemplate string
+	var profilePrefix string
+	var compartmentRoot string
+	var profileFilter string
+	var timeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "import",
@@ -24,6 +36,10 @@ func newImportCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			template := nameTemplate
+			if profilePrefix != "" {
+				template = profilePrefix + "{profile}"
+			}
 			path, err := resolveConfigPath(cfgPath, useGlobal)
 			if err != nil {
 				return err
@@ -33,61 +49,276 @@ func newImportCmd() *cobra.Command {
 				return err
 			}
 
-			if ociCfgPath == "" {
-				home, err := os.UserHomeDir()
+			ociPath, err := ocicfg.ResolveConfigPath(ociCfgPath, cfg.Options.OCIConfigPath)
+			if err != nil {
+				return err
+			}
+
+			profiles, err := ocicfg.LoadProfiles(ociPath)
+			if err != nil {
+				return err
+			}
+			selected, filtered, err := filterProfiles(profiles, profileFilter)
+			if err != nil {
+				return err
+			}
+
+			var root *importRoot
+			if want := strings.TrimSpace(compartmentRoot); want != "" {
+				timeout, err := networkTimeout(cmd, cfg, statusLookupTimeout)
 				if err != nil {
 					return err
 				}
-				ociCfgPath = filepath.Join(home, ".oci", "config")
+				root = &importRoot{want: want, ociPath: ociPath, timeout: timeout}
+				// Each tenancy's lookup runs under cmd's context.
+				cmd.SetContext(ociContext(cmd.Context(), cfg))
 			}
 
-			profiles, err := ocicfg.LoadProfiles(ociCfgPath)
+			logw, verb := importLogger(cmd, dryRun)
+			imported, skipped, err := importProfiles(cmd, &cfg, selected, template, root, overwrite, dryRun)
 			if err != nil {
 				return err
 			}
 
-			imported := 0
-			skipped := 0
-			for name, p := range profiles {
-				ctx := config.Context{
-					Name:            name,
-					Profile:         name,
-					AuthMethod:      config.AuthMethodAPIKey,
-					TenancyOCID:     p.Tenancy,
-					CompartmentOCID: p.Tenancy, // default to root compartment
-					Region:          p.Region,
-					User:            p.User,
-					Notes:           "imported from OCI CLI config",
-				}
-				if err := ctx.Validate(); err != nil {
-					return fmt.Errorf("profile %s invalid: %w", name, err)
-				}
-				if !overwrite {
-					// if exists, skip
-					if _, err := cfg.GetContext(name); err == nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "skip: %s (exists)\n", name)
-						skipped++
-						continue
+			pruned := 0
+			if prune {
+				for _, name := range staleImportedContexts(cfg, profiles) {
+					if err := cfg.DeleteContext(name); err != nil {
+						return err
 					}
+					fmt.Fprintf(logw, "%s: %s (profile removed)\n", verb("prune"), name)
+					pruned++
 				}
-				if err := cfg.UpsertContext(ctx); err != nil {
-					return err
-				}
-				fmt.Fprintf(cmd.ErrOrStderr(), "import: %s (profile)\n", name)
-				imported++
 			}
 
-			if err := config.Save(path, cfg); err != nil {
+			suffix := ""
+			if dryRun {
+				suffix = " (dry run)"
+			} else if err := config.Save(path, cfg); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d profiles (skipped %d) from %s\n", imported, skipped, ociCfgPath)
+			counts := fmt.Sprintf("skipped %d", skipped)
+			if prune {
+				counts += fmt.Sprintf(", pruned %d", pruned)
+			}
+			if profileFilter != "" {
+				counts += fmt.Sprintf(", filtered out %d", filtered)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d profiles (%s) from %s%s\n", imported, counts, ociPath, suffix)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to oci-context config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
-	cmd.Flags().StringVarP(&ociCfgPath, "oci-config", "o", "", "Path to OCI CLI config (default ~/.oci/config)")
+	cmd.Flags().StringVarP(&ociCfgPath, "oci-config", "o", "", "Path to OCI CLI config (default: config option, $OCI_CLI_CONFIG_FILE, then ~/.oci/config)")
 	cmd.Flags().BoolVarP(&overwrite, "overwrite", "w", false, "Overwrite existing contexts with same name")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove imported contexts whose OCI profile no longer exists")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Print planned imports, overwrites, skips, and prunes without saving")
+	cmd.Flags().StringVar(&nameTemplate, "name-template", "{profile}", "Context name template using {profile}, {region}, {tenancy_short}")
+	cmd.Flags().StringVar(&profilePrefix, "profile-prefix", "", "Prefix for context names (shorthand for --name-template <prefix>{profile})")
+	cmd.Flags().StringVar(&profileFilter, "profile-filter", "", "Only import profiles matching these comma-separated names or globs (e.g. prod-*)")
+	cmd.Flags().StringVar(&compartmentRoot, "compartment-root", "", "Use this top-level compartment (name or OCID) of each tenancy instead of the root")
+	cmd.Flags().DurationVar(&timeout, "timeout", statusLookupTimeout, "Timeout per tenancy for --compartment-root lookups (overrides options.network_timeout)")
+	cmd.MarkFlagsMutuallyExclusive("name-template", "profile-prefix")
 	return cmd
 }
+
+// importLogger returns where import progress goes and how actions are worded:
+// dry runs print the plan to stdout; real imports log progress to stderr.
+func importLogger(cmd *cobra.Command, dryRun bool) (io.Writer, func(string) string) {
+	if dryRun {
+		return cmd.OutOrStdout(), func(action string) string { return "would " + action }
+	}
+	return infoWriter(cmd.ErrOrStderr()), func(action string) string { return action }
+}
+
+// importProfiles upserts one context per OCI CLI profile, in profile name
+// order, logging each import or skip. Existing contexts are kept unless
+// overwrite is set. Contexts start at the tenancy root, at root's pick when
+// it is non-nil, or else at the options.defaults compartment for their
+// tenancy. cfg is modified in place even for dry runs.
+func importProfiles(cmd *cobra.Command, cfg *config.Config, profiles map[string]ocicfg.Profile, template string, root *importRoot, overwrite, dryRun bool) (imported, skipped int, err error) {
+	logw, verb := importLogger(cmd, dryRun)
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	planned := make(map[string]string, len(names))
+	for _, profile := range names {
+		p := profiles[profile]
+		name, err := renderImportName(template, profile, p)
+		if err != nil {
+			return 0, 0, err
+		}
+		if other, dup := planned[name]; dup {
+			return 0, 0, fmt.Errorf("name template %q maps profiles %s and %s to the same context %s", template, other, profile, name)
+		}
+		planned[name] = profile
+		label := "profile"
+		if name != profile {
+			label = "profile " + profile
+		}
+		ctx := config.ContextFromProfile(profile, p)
+		ctx.Name = name
+		ctx.Notes = config.ImportedNotes
+		ctx.Source = config.SourceImport
+		if err := ctx.Validate(); err != nil {
+			return 0, 0, fmt.Errorf("profile %s invalid: %w", profile, err)
+		}
+		if err := p.VerifyKey(); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s key: %v\n", profile, err)
+		}
+		_, existsErr := cfg.GetContext(name)
+		exists := existsErr == nil
+		if exists && !overwrite {
+			fmt.Fprintf(logw, "%s: %s (exists)\n", verb("skip"), name)
+			skipped++
+			continue
+		}
+		if root != nil {
+			root.apply(cmd, &ctx)
+		} else if comp := cfg.Options.DefaultCompartmentFor(ctx.TenancyOCID); comp != "" {
+			ctx.SetCompartment(comp, "")
+		}
+		if err := cfg.UpsertContext(ctx); err != nil {
+			return 0, 0, err
+		}
+		if exists && dryRun {
+			fmt.Fprintf(logw, "%s: %s (%s)\n", verb("overwrite"), name, label)
+		} else {
+			fmt.Fprintf(logw, "%s: %s (%s)\n", verb("import"), name, label)
+		}
+		imported++
+	}
+	return imported, skipped, nil
+}
+
+// filterProfiles keeps the profiles whose name matches any comma-separated
+// name or path.Match glob in filter and reports how many were left out. An
+// empty filter keeps every profile.
+func filterProfiles(profiles map[string]ocicfg.Profile, filter string) (map[string]ocicfg.Profile, int, error) {
+	var patterns []string
+	for _, p := range strings.Split(filter, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, 0, fmt.Errorf("invalid --profile-filter pattern %q: %w", p, err)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	if len(patterns) == 0 {
+		return profiles, 0, nil
+	}
+	kept := make(map[string]ocicfg.Profile, len(profiles))
+	for name, p := range profiles {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				kept[name] = p
+				break
+			}
+		}
+	}
+	return kept, len(profiles) - len(kept), nil
+}
+
+// importRoot resolves `import --compartment-root` once per tenancy: a
+// compartment directly under the tenancy root, matched by name or OCID.
+type importRoot struct {
+	want    string
+	ociPath string
+	timeout time.Duration
+	// resolved caches each tenancy's pick; a zero value means the root.
+	resolved map[string]oci.Compartment
+}
+
+// apply moves ctx from the tenancy root to the chosen compartment. A lookup
+// error, miss, or ambiguous name warns and leaves ctx at the root.
+func (r *importRoot) apply(cmd *cobra.Command, ctx *config.Context) {
+	if r.want == ctx.TenancyOCID {
+		return
+	}
+	comp, ok := r.resolved[ctx.TenancyOCID]
+	if !ok {
+		comp = r.lookup(cmd, *ctx)
+		if r.resolved == nil {
+			r.resolved = make(map[string]oci.Compartment)
+		}
+		r.resolved[ctx.TenancyOCID] = comp
+	}
+	if comp.ID != "" {
+		ctx.SetCompartment(comp.ID, comp.Status)
+	}
+}
+
+func (r *importRoot) lookup(cmd *cobra.Command, ctx config.Context) oci.Compartment {
+	c, cancel := context.WithTimeout(cmd.Context(), r.timeout)
+	defer cancel()
+	children, err := fetchCompartments(c, r.ociPath, ctx.Profile, ctx.Region, ctx.TenancyOCID)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s: --compartment-root: %v; using the tenancy root\n", ctx.Profile, withOCIHint(err))
+		return oci.Compartment{}
+	}
+	var matches []oci.Compartment
+	for _, child := range children {
+		if child.Status != "" && child.Status != "ACTIVE" {
+			continue
+		}
+		if child.ID == r.want || child.Name == r.want {
+			matches = append(matches, child)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0]
+	case 0:
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s: compartment %q not found under the tenancy root; using the root\n", ctx.Profile, r.want)
+	default:
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s: compartment %q is ambiguous (%d matches) under the tenancy root; using the root\n", ctx.Profile, r.want, len(matches))
+	}
+	return oci.Compartment{}
+}
+
+// renderImportName expands an import --name-template for one profile.
+// {tenancy_short} is the last 8 characters of the tenancy OCID.
+func renderImportName(template, profile string, p ocicfg.Profile) (string, error) {
+	name, unknown := expandTemplate(template, map[string]string{
+		"profile":       profile,
+		"region":        p.Region,
+		"tenancy_short": tenancyShort(p.Tenancy),
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown --name-template token %s (use {profile}, {region}, {tenancy_short})", strings.Join(unknown, ", "))
+	}
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("--name-template %q produces an empty name for profile %s", template, profile)
+	}
+	return name, nil
+}
+
+func tenancyShort(ocid string) string {
+	id := ocid[strings.LastIndex(ocid, ".")+1:]
+	if len(id) > 8 {
+		return id[len(id)-8:]
+	}
+	return id
+}
+
+// staleImportedContexts returns names of imported contexts whose profile is gone.
+// Only contexts with Source == import are considered, so manual entries are never pruned.
+func staleImportedContexts(cfg config.Config, profiles map[string]ocicfg.Profile) []string {
+	var stale []string
+	for _, ctx := range cfg.Contexts {
+		if ctx.Source != config.SourceImport {
+			continue
+		}
+		if _, ok := profiles[ctx.Profile]; ok {
+			continue
+		}
+		stale = append(stale, ctx.Name)
+	}
+	return stale
+}
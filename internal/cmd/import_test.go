@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+)
+
+func TestImportPruneRemovesOnlyStaleImportedContexts(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	if err := os.WriteFile(ociPath, []byte("[KEEP]\ntenancy=ocid1.tenancy.oc1..keep\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "KEEP", Profile: "KEEP", TenancyOCID: "ocid1.tenancy.oc1..keep", CompartmentOCID: "ocid1.tenancy.oc1..keep", Notes: config.ImportedNotes},
+			{Name: "GONE", Profile: "GONE", TenancyOCID: "ocid1.tenancy.oc1..gone", CompartmentOCID: "ocid1.tenancy.oc1..gone", Notes: config.ImportedNotes},
+			{Name: "manual", Profile: "GONE", TenancyOCID: "ocid1.tenancy.oc1..gone", CompartmentOCID: "ocid1.tenancy.oc1..gone", Notes: "hand made", Source: config.SourceManual},
+		},
+		CurrentContext: "manual",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newImportCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath, "--oci-config", ociPath, "--prune"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 0 profiles (skipped 1, pruned 1)") {
+		t.Fatalf("unexpected summary: %q", out.String())
+	}
+
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	names := []string{}
+	for _, ctx := range loaded.Contexts {
+		names = append(names, ctx.Name)
+	}
+	if strings.Join(names, ",") != "KEEP,manual" {
+		t.Fatalf("expected GONE pruned and manual kept, got %v", names)
+	}
+}
+
+func TestImportDryRunPrintsPlanWithoutSaving(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	ociCfg := "[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n\n[NEW]\ntenancy=ocid1.tenancy.oc1..new\nregion=us-ashburn-1\n"
+	if err := os.WriteFile(ociPath, []byte(ociCfg), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "DEV", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..old", CompartmentOCID: "ocid1.tenancy.oc1..old", Source: config.SourceImport},
+		},
+		CurrentContext: "DEV",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	before, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newImportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--oci-config", ociPath}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("import %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	got := run("-n")
+	want := strings.Join([]string{
+		"would skip: DEV (exists)",
+		"would import: NEW (profile)",
+		"Imported 1 profiles (skipped 1) from " + ociPath + " (dry run)",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("dry-run output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+	}
+
+	got = run("--dry-run", "--overwrite")
+	if !strings.Contains(got, "would overwrite: DEV (profile)") || !strings.Contains(got, "Imported 2 profiles (skipped 0) from "+ociPath+" (dry run)") {
+		t.Fatalf("unexpected overwrite plan: %q", got)
+	}
+
+	after, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Fatalf("dry run modified config:\n%s", after)
+	}
+}
+
+func TestImportNameTemplate(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	ociCfg := "[DEV]\ntenancy=ocid1.tenancy.oc1..aaaaaaaadevtenancy1\nregion=us-phoenix-1\n\n[PROD]\ntenancy=ocid1.tenancy.oc1..aaaaaaaaprodtenant\nregion=us-ashburn-1\n"
+	if err := os.WriteFile(ociPath, []byte(ociCfg), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	existing := config.Context{Name: "oci-DEV", Profile: "OTHER", TenancyOCID: "ocid1.tenancy.oc1..x", CompartmentOCID: "ocid1.tenancy.oc1..x", Source: config.SourceManual}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{existing}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newImportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--oci-config", ociPath}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("--name-template", "{profile}-{nope}"); err == nil || !strings.Contains(err.Error(), "{nope}") {
+		t.Fatalf("expected unknown token error, got %v", err)
+	}
+	if _, err := run("--name-template", "static"); err == nil || !strings.Contains(err.Error(), "same context static") {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+
+	out, err := run("--profile-prefix", "oci-")
+	if err != nil {
+		t.Fatalf("import --profile-prefix: %v", err)
+	}
+	if !strings.Contains(out, "skip: oci-DEV (exists)") || !strings.Contains(out, "import: oci-PROD (profile PROD)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if _, err := run("--name-template", "{tenancy_short}-{profile}-{region}"); err != nil {
+		t.Fatalf("import --name-template: %v", err)
+	}
+
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	names := []string{}
+	for _, ctx := range loaded.Contexts {
+		names = append(names, ctx.Name+"="+ctx.Profile)
+	}
+	want := "oci-DEV=OTHER,oci-PROD=PROD,tenancy1-DEV-us-phoenix-1=DEV,odtenant-PROD-us-ashburn-1=PROD"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("unexpected contexts %v", names)
+	}
+}
+
+func TestImportQuietSuppressesProgress(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	if err := os.WriteFile(ociPath, []byte("[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	if err := config.Save(cfgPath, config.Config{}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	t.Cleanup(func() { cliQuiet = false })
+
+	cmd := newRootCmd()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"-q", "import", "--config", cfgPath, "--oci-config", ociPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if strings.Contains(errOut.String(), "import: DEV") {
+		t.Fatalf("expected --quiet to suppress progress, got %q", errOut.String())
+	}
+	if !strings.Contains(out.String(), "Imported 1 profiles (skipped 0)") {
+		t.Fatalf("expected summary on stdout, got %q", out.String())
+	}
+}
+
+func TestImportCompartmentRootPerTenancy(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	ociCfg := "[A1]\ntenancy=ocid1.tenancy.oc1..a\nregion=us-phoenix-1\n\n[A2]\ntenancy=ocid1.tenancy.oc1..a\nregion=us-ashburn-1\n\n[B]\ntenancy=ocid1.tenancy.oc1..b\nregion=us-phoenix-1\n"
+	if err := os.WriteFile(ociPath, []byte(ociCfg), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	tree := map[string][]oci.Compartment{
+		"ocid1.tenancy.oc1..a": {
+			{ID: "ocid1.compartment.oc1..team-a", Name: "Team", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..old", Name: "Old", Status: "DELETED"},
+		},
+		"ocid1.tenancy.oc1..b": {
+			{ID: "ocid1.compartment.oc1..team-b1", Name: "Team", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..team-b2", Name: "Team", Status: "ACTIVE"},
+		},
+	}
+	calls := 0
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(_ context.Context, _, _, _, parentID string) ([]oci.Compartment, error) {
+		calls++
+		return tree[parentID], nil
+	}
+	run := func(root string) (map[string]config.Context, string) {
+		t.Helper()
+		cfgPath := filepath.Join(t.TempDir(), "config.yml")
+		if err := config.Save(cfgPath, config.Config{}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cmd := newImportCmd()
+		errOut := &bytes.Buffer{}
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{"--config", cfgPath, "--oci-config", ociPath, "--compartment-root", root})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("import --compartment-root %s: %v", root, err)
+		}
+		loaded, err := config.Load(cfgPath)
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		byName := make(map[string]config.Context)
+		for _, c := range loaded.Contexts {
+			byName[c.Name] = c
+		}
+		return byName, errOut.String()
+	}
+
+	got, warnings := run("Team")
+	if got["A1"].CompartmentOCID != "ocid1.compartment.oc1..team-a" || got["A2"].CompartmentOCID != "ocid1.compartment.oc1..team-a" || got["A1"].CompartmentState != "ACTIVE" {
+		t.Fatalf("expected tenancy a contexts under Team: %+v %+v", got["A1"], got["A2"])
+	}
+	if got["B"].CompartmentOCID != "ocid1.tenancy.oc1..b" || !strings.Contains(warnings, `warn: B: compartment "Team" is ambiguous (2 matches)`) {
+		t.Fatalf("expected ambiguous tenancy b at root with a warning: %+v\n%s", got["B"], warnings)
+	}
+	if calls != 2 {
+		t.Fatalf("expected one lookup per tenancy, got %d", calls)
+	}
+
+	got, warnings = run("ocid1.compartment.oc1..old")
+	if got["A1"].CompartmentOCID != "ocid1.tenancy.oc1..a" || !strings.Contains(warnings, `warn: A1: compartment "ocid1.compartment.oc1..old" not found`) {
+		t.Fatalf("expected an inactive match to fall back to root: %+v\n%s", got["A1"], warnings)
+	}
+	if got, _ = run("ocid1.compartment.oc1..team-b2"); got["B"].CompartmentOCID != "ocid1.compartment.oc1..team-b2" {
+		t.Fatalf("expected an OCID to pick that compartment: %+v", got["B"])
+	}
+}
+
+func TestImportCompartmentRootTimeout(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	if err := os.WriteFile(ociPath, []byte("[A]\ntenancy=ocid1.tenancy.oc1..a\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	var budget time.Duration
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(ctx context.Context, _, _, _, _ string) ([]oci.Compartment, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatalf("expected the --compartment-root lookup to have a deadline")
+		}
+		budget = time.Until(deadline)
+		return nil, nil
+	}
+	run := func(options config.Options, args ...string) error {
+		cfgPath := filepath.Join(t.TempDir(), "config.yml")
+		if err := config.Save(cfgPath, config.Config{Options: options}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cmd := newImportCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--oci-config", ociPath, "--compartment-root", "Team"}, args...))
+		return cmd.Execute()
+	}
+
+	if err := run(config.Options{NetworkTimeout: "1m"}, "--timeout", "2s"); err != nil {
+		t.Fatalf("import --timeout 2s: %v", err)
+	}
+	if budget > 2*time.Second {
+		t.Fatalf("expected --timeout to override options.network_timeout, got %s", budget)
+	}
+	if err := run(config.Options{NetworkTimeout: "3s"}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if budget > 3*time.Second || budget < 2*time.Second {
+		t.Fatalf("expected options.network_timeout without --timeout, got %s", budget)
+	}
+	if err := run(config.Options{}, "--timeout", "0s"); err == nil || !strings.Contains(err.Error(), "--timeout must be positive") {
+		t.Fatalf("expected a non-positive --timeout to be rejected, got %v", err)
+	}
+}
+
+func TestImportProfileFilter(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	var ociCfg strings.Builder
+	for _, p := range []string{"prod-a", "prod-b", "dev", "staging"} {
+		ociCfg.WriteString("[" + p + "]\ntenancy=ocid1.tenancy.oc1..t\nregion=us-phoenix-1\n\n")
+	}
+	if err := os.WriteFile(ociPath, []byte(ociCfg.String()), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	existing := config.Context{Name: "prod-a", Profile: "prod-a", TenancyOCID: "ocid1.tenancy.oc1..t", CompartmentOCID: "ocid1.tenancy.oc1..t", Notes: "mine"}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{existing}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newImportCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--oci-config", ociPath}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("import %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	if out := run("--profile-filter", "prod-*"); !strings.Contains(out, "Imported 1 profiles (skipped 1, filtered out 2)") {
+		t.Fatalf("unexpected summary: %q", out)
+	}
+	if out := run("--profile-filter", "prod-*, dev", "--overwrite", "--prune"); !strings.Contains(out, "Imported 3 profiles (skipped 0, pruned 0, filtered out 1)") {
+		t.Fatalf("unexpected summary with --overwrite: %q", out)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	var names []string
+	for _, c := range loaded.Contexts {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "prod-a,prod-b,dev" {
+		t.Fatalf("expected only filtered profiles imported, got %v", names)
+	}
+
+	cmd := newImportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath, "--oci-config", ociPath, "--profile-filter", "prod-["})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --profile-filter pattern") {
+		t.Fatalf("expected a bad pattern error, got %v", err)
+	}
+}
+
+func TestImportStartsAtDefaultsCompartment(t *testing.T) {
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	ociCfg := "[TEAM]\ntenancy=ocid1.tenancy.oc1..team\nregion=us-phoenix-1\n\n[OTHER]\ntenancy=ocid1.tenancy.oc1..other\nregion=us-phoenix-1\n"
+	if err := os.WriteFile(ociPath, []byte(ociCfg), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{Options: config.Options{Defaults: &config.Context{
+		TenancyOCID:     "ocid1.tenancy.oc1..team",
+		CompartmentOCID: "ocid1.compartment.oc1..shared",
+		Region:          "eu-frankfurt-1",
+	}}}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cmd := newImportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath, "--oci-config", ociPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	team, _ := loaded.GetContext("TEAM")
+	if team.CompartmentOCID != "ocid1.compartment.oc1..shared" || team.Region != "us-phoenix-1" {
+		t.Fatalf("expected the template compartment and the profile's region: %+v", team)
+	}
+	other, _ := loaded.GetContext("OTHER")
+	if other.CompartmentOCID != other.TenancyOCID {
+		t.Fatalf("expected another tenancy to stay at its root: %+v", other)
+	}
+
+	// A template compartment without a tenancy is refused on load; it is
+	// never applied to any tenancy either.
+	profiles, err := ocicfg.LoadProfiles(ociPath)
+	if err != nil {
+		t.Fatalf("load profiles: %v", err)
+	}
+	unpinned := config.Config{Options: config.Options{Defaults: &config.Context{CompartmentOCID: "ocid1.compartment.oc1..shared"}}}
+	if _, _, err := importProfiles(cmd, &unpinned, profiles, "{profile}", nil, false, false); err != nil {
+		t.Fatalf("import with an unpinned template: %v", err)
+	}
+	for _, ctx := range unpinned.Contexts {
+		if ctx.CompartmentOCID != ctx.TenancyOCID {
+			t.Fatalf("expected %s to stay at its tenancy root, got %s", ctx.Name, ctx.CompartmentOCID)
+		}
+	}
+}
@@ -1,16 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
 	"github.com/spf13/cobra"
 )
 
+// listRegionSubscriptions is a seam so the init wizard can be tested offline.
+var listRegionSubscriptions = oci.ListRegionSubscriptions
+
 func newInitCmd() *cobra.Command {
 	var cfgPath string
+	var interactive bool
+	var activeOnly, allComps bool
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -27,10 +36,73 @@ func newInitCmd() *cobra.Command {
 				return err
 			}
 			fmt.Fprintf(cmd.OutOrStdout(), "Initialized config at %s\n", cfgPath)
-			return nil
+			if !interactive {
+				return nil
+			}
+			return runInitWizard(cmd, cfgPath, activeOnly && !allComps)
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Walk through importing OCI CLI profiles or creating a first context")
+	addActiveOnlyFlags(cmd, &activeOnly, &allComps)
+	cmd.Flags().Duration("timeout", tuiNetworkTimeout, "Timeout per OCI call in the interactive setup (overrides options.network_timeout)")
 	return cmd
 }
+
+// runInitWizard offers to import every OCI CLI profile or to build one context
+// step by step. It is skipped, not failed, when stdin is not a terminal.
+func runInitWizard(cmd *cobra.Command, path string, activeOnly bool) error {
+	if cliNoInteractive || !stdinIsTerminal(cmd.InOrStdin()) {
+		fmt.Fprintln(infoWriter(cmd.ErrOrStderr()), "Skipping interactive setup: stdin is not a terminal (use `oci-context import` or `oci-context add`)")
+		return nil
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	ociPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return err
+	}
+	profiles, err := ocicfg.LoadProfiles(ociPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("read OCI config %s: %w", ociPath, err)
+	}
+	if len(profiles) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No OCI CLI profiles found in %s; run `oci setup config` first\n", ociPath)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Found %d OCI CLI profiles in %s\n", len(profiles), ociPath)
+	fmt.Fprintln(cmd.OutOrStdout(), "1) Import all profiles as contexts")
+	fmt.Fprintln(cmd.OutOrStdout(), "2) Create one context step by step")
+	fmt.Fprintln(cmd.OutOrStdout(), "0) Skip")
+	choice, err := readChoiceZero(cmd, 2, false)
+	if err != nil {
+		return err
+	}
+	switch choice {
+	case 0:
+		imported, skipped, err := importProfiles(cmd, &cfg, profiles, "{profile}", nil, false, false)
+		if err != nil {
+			return err
+		}
+		if cfg.CurrentContext == "" && len(cfg.Contexts) > 0 {
+			cfg.CurrentContext = cfg.Contexts[0].Name
+		}
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+		if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Imported %d profiles (skipped %d) from %s\n", imported, skipped, ociPath)
+		fmt.Fprintf(cmd.OutOrStdout(), "Current context: %s\n", cfg.CurrentContext)
+		return nil
+	case 1:
+		return promptContextSetup(cmd, path, cfg, ociPath, profiles, true, activeOnly)
+	default:
+		return nil
+	}
+}
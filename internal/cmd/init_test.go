@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func runInitInteractive(t *testing.T, tty bool, stdin string, extraArgs ...string) (string, string, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	ociPath := filepath.Join(tmp, ".oci", "config")
+	if err := os.MkdirAll(filepath.Dir(ociPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	ociCfg := "[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n\n[PROD]\ntenancy=ocid1.tenancy.oc1..prod\nregion=us-ashburn-1\n"
+	if err := os.WriteFile(ociPath, []byte(ociCfg), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+
+	prevTTY := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return tty }
+	t.Cleanup(func() { stdinIsTerminal = prevTTY })
+
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cmd := newInitCmd()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(append([]string{"--interactive", "--config", cfgPath}, extraArgs...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init --interactive: %v", err)
+	}
+	return cfgPath, out.String(), errOut.String()
+}
+
+func TestInitInteractiveSkipsWithoutTerminal(t *testing.T) {
+	cfgPath, out, errOut := runInitInteractive(t, false, "")
+	if !strings.Contains(out, "Initialized config") || !strings.Contains(errOut, "Skipping interactive setup") {
+		t.Fatalf("expected init then skip note, got out=%q err=%q", out, errOut)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Contexts) != 0 {
+		t.Fatalf("expected no contexts, got %+v", cfg.Contexts)
+	}
+}
+
+func TestInitInteractiveImportsProfiles(t *testing.T) {
+	cfgPath, out, _ := runInitInteractive(t, true, "1\n")
+	if !strings.Contains(out, "Imported 2 profiles (skipped 0)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Contexts) != 2 || cfg.CurrentContext != "DEV" {
+		t.Fatalf("expected DEV and PROD with DEV current, got %+v (current %q)", cfg.Contexts, cfg.CurrentContext)
+	}
+}
+
+func TestInitInteractiveCreatesOneContext(t *testing.T) {
+	prevRegions := listRegionSubscriptions
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) {
+		return []string{"eu-frankfurt-1", "us-ashburn-1"}, nil
+	}
+	t.Cleanup(func() { listRegionSubscriptions = prevRegions })
+	prevComps := fetchCompartments
+	fetchCompartments = func(_ context.Context, _, _, _, parent string) ([]oci.Compartment, error) {
+		if parent == "ocid1.tenancy.oc1..prod" {
+			return []oci.Compartment{{ID: "ocid1.compartment.oc1..app", Name: "app", Status: "ACTIVE"}}, nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prevComps })
+
+	// create one context, profile PROD, region eu-frankfurt-1, compartment app
+	cfgPath, out, _ := runInitInteractive(t, true, "2\n2\n1\n1\n")
+	if !strings.Contains(out, "Selected context PROD with compartment ocid1.compartment.oc1..app") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ctx, err := cfg.GetContext("PROD")
+	if err != nil {
+		t.Fatalf("get PROD: %v", err)
+	}
+	if cfg.CurrentContext != "PROD" || ctx.Region != "eu-frankfurt-1" || ctx.CompartmentOCID != "ocid1.compartment.oc1..app" {
+		t.Fatalf("unexpected context %+v (current %q)", ctx, cfg.CurrentContext)
+	}
+}
+
+func TestInitInteractiveUsesNetworkTimeout(t *testing.T) {
+	var budgets []time.Duration
+	budget := func(ctx context.Context) {
+		if deadline, ok := ctx.Deadline(); ok {
+			budgets = append(budgets, time.Until(deadline))
+		}
+	}
+	prevRegions := listRegionSubscriptions
+	listRegionSubscriptions = func(ctx context.Context, _, _ string) ([]string, error) {
+		budget(ctx)
+		return nil, nil
+	}
+	t.Cleanup(func() { listRegionSubscriptions = prevRegions })
+	prevComps := fetchCompartments
+	fetchCompartments = func(ctx context.Context, _, _, _, _ string) ([]oci.Compartment, error) {
+		budget(ctx)
+		return nil, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prevComps })
+
+	runInitInteractive(t, true, "2\n2\n", "--timeout", "2s")
+	if len(budgets) != 2 {
+		t.Fatalf("expected a region and a compartment call with deadlines, got %v", budgets)
+	}
+	for _, b := range budgets {
+		if b > 2*time.Second {
+			t.Fatalf("expected --timeout 2s to bound each call, got %v", budgets)
+		}
+	}
+}
+
+func TestInitInteractiveHidesInactiveCompartmentsUnlessAll(t *testing.T) {
+	prevRegions := listRegionSubscriptions
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) { return nil, nil }
+	t.Cleanup(func() { listRegionSubscriptions = prevRegions })
+	prevComps := fetchCompartments
+	fetchCompartments = func(_ context.Context, _, _, _, parent string) ([]oci.Compartment, error) {
+		if parent == "ocid1.tenancy.oc1..prod" {
+			return []oci.Compartment{
+				{ID: "ocid1.compartment.oc1..old", Name: "old", Status: "DELETED"},
+				{ID: "ocid1.compartment.oc1..app", Name: "app", Status: "ACTIVE"},
+			}, nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prevComps })
+
+	// choice 1 is the first compartment offered: app when DELETED ones are hidden
+	cfgPath, out, _ := runInitInteractive(t, true, "2\n2\n1\n")
+	if strings.Contains(out, "old [DELETED]") || !strings.Contains(out, "Selected context PROD with compartment ocid1.compartment.oc1..app") {
+		t.Fatalf("expected DELETED compartment hidden by default, got %q", out)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if ctx, _ := cfg.GetContext("PROD"); ctx.CompartmentState != config.CompartmentStateActive {
+		t.Fatalf("expected ACTIVE compartment state, got %+v", ctx)
+	}
+
+	_, out, _ = runInitInteractive(t, true, "2\n2\n1\n", "--all")
+	if !strings.Contains(out, "1) old [DELETED]") || !strings.Contains(out, "compartment ocid1.compartment.oc1..old") {
+		t.Fatalf("expected --all to offer the DELETED compartment, got %q", out)
+	}
+}
+
+func TestInitInteractiveCompartmentBackNavigation(t *testing.T) {
+	prevRegions := listRegionSubscriptions
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) { return nil, nil }
+	t.Cleanup(func() { listRegionSubscriptions = prevRegions })
+	prevComps := fetchCompartments
+	fetchCompartments = func(_ context.Context, _, _, _, parent string) ([]oci.Compartment, error) {
+		switch parent {
+		case "ocid1.tenancy.oc1..prod":
+			return []oci.Compartment{
+				{ID: "ocid1.compartment.oc1..app", Name: "app", Status: "ACTIVE"},
+				{ID: "ocid1.compartment.oc1..data", Name: "data", Status: "ACTIVE"},
+			}, nil
+		case "ocid1.compartment.oc1..app":
+			return []oci.Compartment{{ID: "ocid1.compartment.oc1..team", Name: "team", Status: "ACTIVE"}}, nil
+		case "ocid1.compartment.oc1..team":
+			return []oci.Compartment{{ID: "ocid1.compartment.oc1..svc", Name: "svc", Status: "ACTIVE"}}, nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prevComps })
+
+	// app, team, back to app, back (as -1) to the tenancy, then data
+	cfgPath, out, _ := runInitInteractive(t, true, "2\n2\n1\n1\nb\n-1\n2\n")
+	if !strings.Contains(out, "b) back to ocid1.compartment.oc1..app") || !strings.Contains(out, "Selected context PROD with compartment ocid1.compartment.oc1..data") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if first := out[:strings.Index(out, "1) app")]; strings.Contains(first, "b) back") {
+		t.Fatalf("expected no back option at the starting level, got %q", first)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if ctx, _ := cfg.GetContext("PROD"); ctx.CompartmentOCID != "ocid1.compartment.oc1..data" {
+		t.Fatalf("unexpected context %+v", ctx)
+	}
+}
+
+func TestInitInteractiveBacksOutOfLeafCompartment(t *testing.T) {
+	prevRegions := listRegionSubscriptions
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) { return nil, nil }
+	t.Cleanup(func() { listRegionSubscriptions = prevRegions })
+	prevComps := fetchCompartments
+	fetchCompartments = func(_ context.Context, _, _, _, parent string) ([]oci.Compartment, error) {
+		if parent == "ocid1.tenancy.oc1..prod" {
+			return []oci.Compartment{
+				{ID: "ocid1.compartment.oc1..leaf", Name: "leaf", Status: "ACTIVE"},
+				{ID: "ocid1.compartment.oc1..data", Name: "data", Status: "ACTIVE"},
+			}, nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prevComps })
+
+	// leaf has no children: back out of it, then pick data and stay there
+	cfgPath, out, _ := runInitInteractive(t, true, "2\n2\n1\nb\n2\n0\n")
+	if !strings.Contains(out, "No child compartments.\nb) back to ocid1.tenancy.oc1..prod\n0) stay at ocid1.compartment.oc1..leaf") {
+		t.Fatalf("expected a back option at the leaf, got %q", out)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if ctx, _ := cfg.GetContext("PROD"); ctx.CompartmentOCID != "ocid1.compartment.oc1..data" {
+		t.Fatalf("unexpected context %+v", ctx)
+	}
+}
+
+func TestInitInteractiveFailsOnBrokenOCIConfig(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	ociPath := filepath.Join(tmp, ".oci", "config")
+	if err := os.MkdirAll(filepath.Dir(ociPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(ociPath, []byte("[DEV]\ntenancy=ocid1.tenancy.oc1..dev\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	prevTTY := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { stdinIsTerminal = prevTTY })
+
+	cmd := newInitCmd()
+	errOut := &bytes.Buffer{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOut)
+	cmd.SilenceUsage = true
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SetArgs([]string{"--interactive", "--config", filepath.Join(tmp, "config.yml")})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "profile DEV missing region") {
+		t.Fatalf("expected the parse error, got %v", err)
+	}
+	if strings.Contains(errOut.String(), "No OCI CLI profiles found") {
+		t.Fatalf("expected no empty-config hint, got %q", errOut.String())
+	}
+}
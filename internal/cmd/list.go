@@ -3,9 +3,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -14,85 +20,337 @@ func newListCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
 	var output string
+	var outputFile string
+	var outputOnly bool
 	var verbose bool
+	var grep string
+	var tagFilters []string
+	var sortBy string
+	var reverse bool
+	var resolve bool
+	var templateText string
+	var groupBy string
+	var noNotes bool
 
 	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List contexts",
+		Use:         "list",
+		Short:       "List contexts",
+		Annotations: mergeAnnotations,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			useGlobal, err := cmd.Flags().GetBool("global")
-			if err != nil {
-				return err
-			}
-			path, err := resolveConfigPath(cfgPath, useGlobal)
-			if err != nil {
-				return err
-			}
-			cfg, err := config.Load(path)
-			if err != nil {
-				return err
-			}
+			return runWithOutputFile(cmd, outputFile, outputOnly, false, func() error {
+				useGlobal, err := cmd.Flags().GetBool("global")
+				if err != nil {
+					return err
+				}
+				var re *regexp.Regexp
+				if grep != "" {
+					re, err = regexp.Compile(grep)
+					if err != nil {
+						return fmt.Errorf("invalid --grep pattern: %w", err)
+					}
+				}
+				wantTags, err := config.ParseTags(tagFilters)
+				if err != nil {
+					return fmt.Errorf("invalid --tag filter: %w", err)
+				}
+				sortKey, err := contextSortKey(sortBy)
+				if err != nil {
+					return err
+				}
+				tmpl, err := parseOutputTemplate(output, templateText)
+				if err != nil {
+					return err
+				}
+				grouping, err := parseGroupBy(groupBy)
+				if err != nil {
+					return err
+				}
+				if grouping != "" {
+					switch strings.ToLower(output) {
+					case "", "json", "yaml", "yml":
+					default:
+						return fmt.Errorf("--group-by only supports the default, json, and yaml output")
+					}
+					if sortKey == nil {
+						sortKey, _ = contextSortKey("name")
+					}
+				}
+				if noNotes && strings.ToLower(output) != "plain" {
+					// Only -o plain prints notes as a field that can be dropped.
+					return fmt.Errorf("--no-notes only applies to -o plain")
+				}
+				if resolve {
+					// These formats print raw fields only; resolving would
+					// spend OCI calls on names that are never shown.
+					switch strings.ToLower(output) {
+					case "plain", "template":
+						return fmt.Errorf("--resolve is not supported with -o %s", strings.ToLower(output))
+					}
+				}
+				cfg, _, err := loadReadConfig(cfgPath, useGlobal)
+				if err != nil {
+					return err
+				}
+				contexts := filterContextsByTags(filterContextsByGrep(cfg.Contexts, re), wantTags)
+				contexts = sortContexts(contexts, sortKey, reverse)
+				hl := func(v string) string { return highlightGrepMatches(re, v) }
+				var names map[string]resolvedNames
+				if resolve {
+					names = resolveContextNames(cmd, cfg, contexts)
+				}
 
-			switch strings.ToLower(output) {
-			case "":
-				// Default: human-friendly list
-				for _, ctx := range cfg.Contexts {
-					marker := " "
-					if ctx.Name == cfg.CurrentContext {
-						marker = "*"
+				switch strings.ToLower(output) {
+				case "":
+					// Default: human-friendly list
+					printLine := func(indent string, ctx config.Context) {
+						marker := " "
+						if ctx.Name == cfg.CurrentContext {
+							marker = "*"
+						}
+						if verbose {
+							tagsPart := ""
+							if len(ctx.Tags) > 0 {
+								tagsPart = " tags=" + hl(config.FormatTags(ctx.Tags))
+							}
+							fmt.Fprintf(cmd.OutOrStdout(), "%s%s %s (profile=%s auth=%s region=%s tenancy=%s compartment=%s user=%s source=%s%s)\n",
+								indent,
+								marker,
+								hl(ctx.Name),
+								hl(ctx.Profile),
+								hl(config.NormalizeAuthMethod(ctx.AuthMethod)),
+								hl(ctx.Region),
+								hl(withResolvedName(ctx.TenancyOCID, names[ctx.Name].Tenancy)),
+								hl(withResolvedName(ctx.CompartmentOCID, names[ctx.Name].Compartment)),
+								hl(ctx.User),
+								hl(ctx.Source),
+								tagsPart,
+							)
+							return
+						}
+						namesPart := ""
+						if n := names[ctx.Name]; n.Tenancy != "" {
+							namesPart += " tenancy=" + hl(n.Tenancy)
+						}
+						if n := names[ctx.Name]; n.Compartment != "" {
+							namesPart += " compartment=" + hl(n.Compartment)
+						}
+						fmt.Fprintf(cmd.OutOrStdout(), "%s%s %s (profile=%s region=%s%s)\n", indent, marker, hl(ctx.Name), hl(ctx.Profile), hl(ctx.Region), namesPart)
+					}
+					if grouping != "" {
+						for _, g := range groupContextsByTenancy(contexts, names) {
+							fmt.Fprintf(cmd.OutOrStdout(), "%s:\n", g.label)
+							for _, ctx := range g.contexts {
+								printLine("  ", ctx)
+							}
+						}
+						return nil
+					}
+					for _, ctx := range contexts {
+						printLine("", ctx)
+					}
+					return nil
+				case "json":
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					if grouping != "" {
+						return enc.Encode(groupedListViews(groupContextsByTenancy(contexts, names), names))
 					}
-					if verbose {
-						fmt.Fprintf(cmd.OutOrStdout(), "%s %s (profile=%s auth=%s region=%s tenancy=%s compartment=%s user=%s)\n",
+					return enc.Encode(listViews(contexts, names))
+				case "jsonl":
+					// One compact object per line so consumers can stream it.
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					for _, v := range listViews(contexts, names) {
+						if err := enc.Encode(v); err != nil {
+							return err
+						}
+					}
+					return nil
+				case "yaml", "yml":
+					enc := yaml.NewEncoder(cmd.OutOrStdout())
+					defer enc.Close()
+					if grouping != "" {
+						return enc.Encode(groupedListViews(groupContextsByTenancy(contexts, names), names))
+					}
+					return enc.Encode(listViews(contexts, names))
+				case "template":
+					return writeTemplate(cmd.OutOrStdout(), tmpl, contexts)
+				case "table":
+					return printContextTable(cmd.OutOrStdout(), contexts, cfg.CurrentContext, verbose, names)
+				case "plain":
+					for _, ctx := range contexts {
+						marker := ""
+						if ctx.Name == cfg.CurrentContext {
+							marker = "*"
+						}
+						line := fmt.Sprintf("context=%s%s profile=%s auth=%s region=%s tenancy=%s compartment=%s user=%s",
+							plainValue(ctx.Name),
 							marker,
-							ctx.Name,
-							ctx.Profile,
+							plainValue(ctx.Profile),
 							config.NormalizeAuthMethod(ctx.AuthMethod),
-							ctx.Region,
-							ctx.TenancyOCID,
-							ctx.CompartmentOCID,
-							ctx.User,
+							plainValue(ctx.Region),
+							plainValue(ctx.TenancyOCID),
+							plainValue(ctx.CompartmentOCID),
+							plainValue(ctx.User),
 						)
-						continue
-					}
-					fmt.Fprintf(cmd.OutOrStdout(), "%s %s (profile=%s region=%s)\n", marker, ctx.Name, ctx.Profile, ctx.Region)
-				}
-				return nil
-			case "json":
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(cfg.Contexts)
-			case "yaml", "yml":
-				enc := yaml.NewEncoder(cmd.OutOrStdout())
-				defer enc.Close()
-				return enc.Encode(cfg.Contexts)
-			case "plain":
-				for _, ctx := range cfg.Contexts {
-					marker := ""
-					if ctx.Name == cfg.CurrentContext {
-						marker = "*"
+						if !noNotes {
+							line += " notes=" + plainValue(ctx.Notes)
+						}
+						fmt.Fprintln(cmd.OutOrStdout(), line)
 					}
-					fmt.Fprintf(cmd.OutOrStdout(), "context=%s%s profile=%s auth=%s region=%s tenancy=%s compartment=%s user=%s notes=%s\n",
-						ctx.Name,
-						marker,
-						ctx.Profile,
-						config.NormalizeAuthMethod(ctx.AuthMethod),
-						ctx.Region,
-						ctx.TenancyOCID,
-						ctx.CompartmentOCID,
-						ctx.User,
-						ctx.Notes,
-					)
+					return nil
+				default:
+					return fmt.Errorf("unsupported output format: %s", output)
 				}
-				return nil
-			default:
-				return fmt.Errorf("unsupported output format: %s", output)
-			}
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
-	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|yaml|plain (default: human-readable)")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed fields in human-readable output")
+	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|jsonl|yaml|plain|table|template (default: human-readable)")
+	cmd.Flags().StringVar(&templateText, "template", "", "Go text/template for -o template, over the listed contexts (e.g. '{{range .}}{{.Name}} {{.Region}}{{\"\\n\"}}{{end}}')")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Also write formatted output to this file; its directory must exist")
+	cmd.Flags().BoolVar(&outputOnly, "output-only", false, "With --output-file, write only the file and suppress stdout")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed fields in human-readable and table output")
+	cmd.Flags().StringVar(&grep, "grep", "", "Only show contexts where a regex matches any field")
+	cmd.Flags().StringArrayVar(&tagFilters, "tag", nil, "Only show contexts with tag key=value (repeatable; all must match)")
+	cmd.Flags().StringVarP(&sortBy, "sort", "s", "", "Sort by name|region|profile (default: config file order)")
+	cmd.Flags().BoolVarP(&reverse, "reverse", "r", false, "Reverse the listing order")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group contexts under a header per tenancy: tenancy (names need --resolve)")
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "Look up tenancy and compartment names (OCI calls; not for -o plain or template)")
+	cmd.Flags().BoolVar(&noNotes, "no-notes", false, "Leave the notes field out of -o plain (rejected with other formats)")
 	return cmd
 }
+
+// printContextTable prints contexts as columns with a "*" marking the current
+// one; verbose adds tenancy and user columns.
+func printContextTable(w io.Writer, contexts []config.Context, current string, verbose bool, names map[string]resolvedNames) error {
+	header := []string{" ", "NAME", "PROFILE", "REGION", "COMPARTMENT"}
+	if verbose {
+		header = append(header, "TENANCY", "USER")
+	}
+	rows := make([][]string, 0, len(contexts))
+	for _, ctx := range contexts {
+		marker := " "
+		if ctx.Name == current {
+			marker = "*"
+		}
+		n := names[ctx.Name]
+		row := []string{marker, ctx.Name, ctx.Profile, ctx.Region, withResolvedName(ctx.CompartmentOCID, n.Compartment)}
+		if verbose {
+			row = append(row, withResolvedName(ctx.TenancyOCID, n.Tenancy), ctx.User)
+		}
+		rows = append(rows, row)
+	}
+	return printTable(w, header, rows)
+}
+
+// plainValue formats one key=value value for -o plain. Values with
+// whitespace, quotes, backslashes, or control characters are written as a
+// double-quoted Go string (as strconv.Quote does) so every line splits on
+// spaces; everything else, including empty values, is written bare.
+func plainValue(v string) string {
+	if strings.IndexFunc(v, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '\\' || !unicode.IsPrint(r)
+	}) < 0 {
+		return v
+	}
+	return strconv.Quote(v)
+}
+
+// contextSearchText joins every searchable context field into one line for --grep.
+func contextSearchText(ctx config.Context) string {
+	return strings.Join([]string{
+		ctx.Name,
+		ctx.Profile,
+		config.NormalizeAuthMethod(ctx.AuthMethod),
+		ctx.Region,
+		ctx.TenancyOCID,
+		ctx.CompartmentOCID,
+		ctx.User,
+		ctx.Notes,
+		ctx.Source,
+		config.FormatTags(ctx.Tags),
+	}, " ")
+}
+
+func filterContextsByGrep(contexts []config.Context, re *regexp.Regexp) []config.Context {
+	if re == nil {
+		return contexts
+	}
+	out := make([]config.Context, 0, len(contexts))
+	for _, ctx := range contexts {
+		if re.MatchString(contextSearchText(ctx)) {
+			out = append(out, ctx)
+		}
+	}
+	return out
+}
+
+// contextSortKey maps a --sort value to a field accessor; nil keeps file order.
+func contextSortKey(by string) (func(config.Context) string, error) {
+	switch strings.ToLower(strings.TrimSpace(by)) {
+	case "":
+		return nil, nil
+	case "name":
+		return func(c config.Context) string { return c.Name }, nil
+	case "region":
+		return func(c config.Context) string { return c.Region }, nil
+	case "profile":
+		return func(c config.Context) string { return c.Profile }, nil
+	default:
+		return nil, fmt.Errorf("unsupported --sort %q (use name, region, or profile)", by)
+	}
+}
+
+// sortContexts returns a sorted copy, breaking ties by name so output is stable.
+func sortContexts(contexts []config.Context, key func(config.Context) string, reverse bool) []config.Context {
+	if key == nil && !reverse {
+		return contexts
+	}
+	out := append([]config.Context(nil), contexts...)
+	if key != nil {
+		sort.SliceStable(out, func(i, j int) bool {
+			ki, kj := key(out[i]), key(out[j])
+			if ki != kj {
+				return ki < kj
+			}
+			return out[i].Name < out[j].Name
+		})
+	}
+	if reverse {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out
+}
+
+func filterContextsByTags(contexts []config.Context, want map[string]string) []config.Context {
+	if len(want) == 0 {
+		return contexts
+	}
+	out := make([]config.Context, 0, len(contexts))
+	for _, ctx := range contexts {
+		if ctx.HasTags(want) {
+			out = append(out, ctx)
+		}
+	}
+	return out
+}
+
+var grepMatchStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+
+// highlightGrepMatches styles regex matches in v; styling is dropped when the
+// output is not a color terminal.
+func highlightGrepMatches(re *regexp.Regexp, v string) string {
+	if re == nil || v == "" {
+		return v
+	}
+	return re.ReplaceAllStringFunc(v, func(m string) string {
+		if m == "" {
+			return m
+		}
+		return grepMatchStyle.Render(m)
+	})
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+// tenancyGroup is one tenancy's contexts for `list --group-by tenancy`.
+type tenancyGroup struct {
+	tenancy  string
+	label    string
+	contexts []config.Context
+}
+
+// parseGroupBy validates --group-by; "" means no grouping.
+func parseGroupBy(by string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(by)) {
+	case "":
+		return "", nil
+	case "tenancy":
+		return "tenancy", nil
+	default:
+		return "", fmt.Errorf("unsupported --group-by %q (use tenancy)", by)
+	}
+}
+
+// groupContextsByTenancy groups contexts by tenancy OCID, keeping their order
+// within each group. A group is labeled with the tenancy name from --resolve
+// when known, else the abbreviated OCID. Distinct tenancies that end up with
+// the same label get their full OCID appended so headers and json/yaml keys
+// stay unique; groups are sorted by label.
+func groupContextsByTenancy(contexts []config.Context, names map[string]resolvedNames) []tenancyGroup {
+	index := make(map[string]int)
+	var groups []tenancyGroup
+	for _, ctx := range contexts {
+		i, ok := index[ctx.TenancyOCID]
+		if !ok {
+			i = len(groups)
+			index[ctx.TenancyOCID] = i
+			groups = append(groups, tenancyGroup{tenancy: ctx.TenancyOCID, label: abbreviateOCID(ctx.TenancyOCID)})
+		}
+		if n := names[ctx.Name].Tenancy; n != "" {
+			groups[i].label = n
+		}
+		groups[i].contexts = append(groups[i].contexts, ctx)
+	}
+	seen := make(map[string]int, len(groups))
+	for _, g := range groups {
+		seen[g.label]++
+	}
+	for i, g := range groups {
+		if seen[g.label] > 1 {
+			groups[i].label = fmt.Sprintf("%s (%s)", g.label, g.tenancy)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].label < groups[j].label })
+	return groups
+}
+
+// groupedListViews is the json/yaml shape of a grouped list: label -> contexts.
+func groupedListViews(groups []tenancyGroup, names map[string]resolvedNames) map[string][]any {
+	out := make(map[string][]any, len(groups))
+	for _, g := range groups {
+		out[g.label] = listViews(g.contexts, names)
+	}
+	return out
+}
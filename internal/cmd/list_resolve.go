@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// resolvedNames are the friendly names `list --resolve` found for a context.
+type resolvedNames struct {
+	Tenancy     string `json:"tenancy_name,omitempty" yaml:"tenancy_name,omitempty"`
+	Compartment string `json:"compartment_name,omitempty" yaml:"compartment_name,omitempty"`
+}
+
+// listContextView is a context plus its resolved names in structured output.
+type listContextView struct {
+	config.Context `yaml:",inline"`
+	resolvedNames  `yaml:",inline"`
+}
+
+// listViews returns contexts unchanged without --resolve, else one view per
+// context with any resolved names attached.
+func listViews(contexts []config.Context, names map[string]resolvedNames) []any {
+	out := make([]any, 0, len(contexts))
+	for _, ctx := range contexts {
+		if names == nil {
+			out = append(out, ctx)
+			continue
+		}
+		out = append(out, listContextView{Context: ctx, resolvedNames: names[ctx.Name]})
+	}
+	return out
+}
+
+// resolveContextNames looks up tenancy and compartment names for each context,
+// options.identity_concurrency at a time. A failed lookup prints a warning
+// and leaves that context out, so it falls back to raw OCIDs.
+func resolveContextNames(cmd *cobra.Command, cfg config.Config, contexts []config.Context) map[string]resolvedNames {
+	names := make(map[string]resolvedNames, len(contexts))
+	ociCfgPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: resolve names: %v\n", err)
+		return names
+	}
+	timeout, err := cfg.Options.NetworkTimeoutOr(statusLookupTimeout)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: resolve names: %v\n", err)
+		return names
+	}
+	fetch := identityFetcher(cfg, false)
+	sem := make(chan struct{}, cfg.Options.IdentityConcurrencyLimit())
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			ctx, cancel := context.WithTimeout(ociContext(cmd.Context(), cfg), timeout)
+			defer cancel()
+			details, err := fetch(ctx, ociCfgPath, c.Profile, c.Region, c.TenancyOCID, c.CompartmentOCID, c.User)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s: resolve names: %v\n", c.Name, withOCIHint(err))
+				return
+			}
+			names[c.Name] = resolvedNames{Tenancy: details.TenancyName, Compartment: details.CompartmentName}
+		}()
+	}
+	wg.Wait()
+	return names
+}
+
+// withResolvedName renders "ocid (name)", or just the OCID when name is unknown.
+func withResolvedName(ocid, name string) string {
+	if name == "" {
+		return ocid
+	}
+	return ocid + " (" + name + ")"
+}
@@ -2,11 +2,17 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,6 +28,7 @@ func TestListOutputs(t *testing.T) {
 				Region:          "us-phoenix-1",
 				User:            "ocid1.user.oc1..cccc",
 				Notes:           "dev-notes",
+				Source:          config.SourceManual,
 			},
 			{
 				Name:            "prod",
@@ -31,6 +38,7 @@ func TestListOutputs(t *testing.T) {
 				Region:          "us-ashburn-1",
 				User:            "ocid1.user.oc1..xxxx",
 				Notes:           "",
+				Source:          config.SourceManual,
 			},
 		},
 		CurrentContext: "dev",
@@ -70,8 +78,8 @@ func TestListOutputs(t *testing.T) {
 					t.Fatalf("unexpected error: %v", err)
 				}
 				want := strings.Join([]string{
-					"* dev (profile=DEFAULT auth=api_key region=us-phoenix-1 tenancy=ocid1.tenancy.oc1..aaaa compartment=ocid1.compartment.oc1..bbbb user=ocid1.user.oc1..cccc)",
-					"  prod (profile=PROD auth=api_key region=us-ashburn-1 tenancy=ocid1.tenancy.oc1..zzzz compartment=ocid1.compartment.oc1..yyyy user=ocid1.user.oc1..xxxx)",
+					"* dev (profile=DEFAULT auth=api_key region=us-phoenix-1 tenancy=ocid1.tenancy.oc1..aaaa compartment=ocid1.compartment.oc1..bbbb user=ocid1.user.oc1..cccc source=manual)",
+					"  prod (profile=PROD auth=api_key region=us-ashburn-1 tenancy=ocid1.tenancy.oc1..zzzz compartment=ocid1.compartment.oc1..yyyy user=ocid1.user.oc1..xxxx source=manual)",
 					"",
 				}, "\n")
 				if got != want {
@@ -97,6 +105,53 @@ func TestListOutputs(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "plain output quotes notes with spaces",
+			mutate: func(c config.Config) config.Config {
+				c.Contexts = append([]config.Context(nil), c.Contexts...)
+				c.Contexts[0].Notes = `team "a" db`
+				c.Contexts[1].Notes = "on-call"
+				return c
+			},
+			args: []string{"list", "-o", "plain"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+				if len(lines) != 2 || !strings.HasSuffix(lines[0], ` notes="team \"a\" db"`) || !strings.HasSuffix(lines[1], " notes=on-call") {
+					t.Fatalf("unexpected plain notes:\n%s", got)
+				}
+			},
+		},
+		{
+			name: "plain output without notes",
+			mutate: func(c config.Config) config.Config {
+				c.Contexts = append([]config.Context(nil), c.Contexts...)
+				c.Contexts[0].Notes = "team a db"
+				return c
+			},
+			args: []string{"list", "-o", "plain", "--no-notes"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := "context=dev* profile=DEFAULT auth=api_key region=us-phoenix-1 tenancy=ocid1.tenancy.oc1..aaaa compartment=ocid1.compartment.oc1..bbbb user=ocid1.user.oc1..cccc\n"
+				if !strings.HasPrefix(got, want) || strings.Contains(got, "notes=") {
+					t.Fatalf("unexpected output %q", got)
+				}
+			},
+		},
+		{
+			name:   "no-notes rejected outside plain",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "json", "--no-notes"},
+			assert: func(t *testing.T, got string, err error) {
+				if err == nil || err.Error() != "--no-notes only applies to -o plain" {
+					t.Fatalf("expected --no-notes to be rejected, got %v (%q)", err, got)
+				}
+			},
+		},
 		{
 			name:   "json output",
 			mutate: func(c config.Config) config.Config { return c },
@@ -114,12 +169,35 @@ func TestListOutputs(t *testing.T) {
 					t.Fatalf("expected %d contexts, got %d", len(want), len(out))
 				}
 				for i := range want {
-					if out[i] != want[i] {
+					if !reflect.DeepEqual(out[i], want[i]) {
 						t.Fatalf("context %d mismatch: want %+v got %+v", i, want[i], out[i])
 					}
 				}
 			},
 		},
+		{
+			name:   "jsonl output with sort",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "jsonl", "--sort", "name", "-r"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+				if len(lines) != 2 {
+					t.Fatalf("expected one line per context, got %q", got)
+				}
+				for i, want := range []config.Context{baseCfg.Contexts[1], baseCfg.Contexts[0]} {
+					var ctx config.Context
+					if err := json.Unmarshal([]byte(lines[i]), &ctx); err != nil {
+						t.Fatalf("line %d is not json: %v", i, err)
+					}
+					if !reflect.DeepEqual(ctx, want) {
+						t.Fatalf("line %d mismatch: want %+v got %+v", i, want, ctx)
+					}
+				}
+			},
+		},
 		{
 			name:   "yaml output",
 			mutate: func(c config.Config) config.Config { return c },
@@ -137,18 +215,245 @@ func TestListOutputs(t *testing.T) {
 					t.Fatalf("expected %d contexts, got %d", len(want), len(out))
 				}
 				for i := range want {
-					if out[i] != want[i] {
+					if !reflect.DeepEqual(out[i], want[i]) {
 						t.Fatalf("context %d mismatch: want %+v got %+v", i, want[i], out[i])
 					}
 				}
 			},
 		},
+		{
+			name:   "table output",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "table"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := strings.Join([]string{
+					"   NAME  PROFILE  REGION        COMPARTMENT",
+					"*  dev   DEFAULT  us-phoenix-1  ocid1.compartment.oc1..bbbb",
+					"   prod  PROD     us-ashburn-1  ocid1.compartment.oc1..yyyy",
+					"",
+				}, "\n")
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:   "verbose table output",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "table", "-v"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := strings.Join([]string{
+					"   NAME  PROFILE  REGION        COMPARTMENT                  TENANCY                  USER",
+					"*  dev   DEFAULT  us-phoenix-1  ocid1.compartment.oc1..bbbb  ocid1.tenancy.oc1..aaaa  ocid1.user.oc1..cccc",
+					"   prod  PROD     us-ashburn-1  ocid1.compartment.oc1..yyyy  ocid1.tenancy.oc1..zzzz  ocid1.user.oc1..xxxx",
+					"",
+				}, "\n")
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:   "grep matches tenancy ocid substring",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "--grep", "tenancy\\.oc1\\.\\.zz"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := "  prod (profile=PROD region=us-ashburn-1)\n"
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:   "grep matches notes word",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "--grep", "dev-notes", "-o", "json"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				var out []config.Context
+				if err := json.Unmarshal([]byte(got), &out); err != nil {
+					t.Fatalf("unmarshal json: %v", err)
+				}
+				if len(out) != 1 || out[0].Name != "dev" {
+					t.Fatalf("expected only dev to match, got %+v", out)
+				}
+			},
+		},
+		{
+			name: "tag filter",
+			mutate: func(c config.Config) config.Config {
+				c.Contexts = append([]config.Context(nil), c.Contexts...)
+				c.Contexts[0].Tags = map[string]string{"env": "dev"}
+				c.Contexts[1].Tags = map[string]string{"env": "prod", "team": "core"}
+				return c
+			},
+			args: []string{"list", "--tag", "env=prod", "--tag", "team=core", "-v"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := "  prod (profile=PROD auth=api_key region=us-ashburn-1 tenancy=ocid1.tenancy.oc1..zzzz compartment=ocid1.compartment.oc1..yyyy user=ocid1.user.oc1..xxxx source=manual tags=env=prod,team=core)\n"
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:      "invalid tag filter",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--tag", "env"},
+			assertErr: "invalid --tag filter",
+		},
+		{
+			name:   "sort by region",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "--sort", "region"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := "  prod (profile=PROD region=us-ashburn-1)\n* dev (profile=DEFAULT region=us-phoenix-1)\n"
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:   "sort by name reversed plain",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-s", "name", "-r", "-o", "plain"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				lines := strings.Split(strings.TrimSpace(got), "\n")
+				if len(lines) != 2 || !strings.HasPrefix(lines[0], "context=prod ") || !strings.HasPrefix(lines[1], "context=dev* ") {
+					t.Fatalf("unexpected order: %q", got)
+				}
+			},
+		},
+		{
+			name:      "unsupported sort",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--sort", "tenancy"},
+			assertErr: "unsupported --sort",
+		},
+		{
+			name:      "grep invalid regex",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--grep", "("},
+			assertErr: "invalid --grep pattern",
+		},
 		{
 			name:      "unsupported output",
 			mutate:    func(c config.Config) config.Config { return c },
 			args:      []string{"list", "-o", "xml"},
 			assertErr: "unsupported output format: xml",
 		},
+		{
+			name:   "template output",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "template", "--template", `{{range .}}{{.Name}}={{.Region}};{{end}}`},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if want := "dev=us-phoenix-1;prod=us-ashburn-1;\n"; got != want {
+					t.Fatalf("want %q, got %q", want, got)
+				}
+			},
+		},
+		{
+			name:   "invalid template prints nothing",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "template", "--template", `{{range .}}{{.Name}`},
+			assert: func(t *testing.T, got string, err error) {
+				if err == nil || !strings.Contains(err.Error(), "invalid --template") || strings.Contains(got, "us-phoenix-1") {
+					t.Fatalf("expected a parse error and no output, got %v / %q", err, got)
+				}
+			},
+		},
+		{
+			name:   "template execution error prints nothing",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "-o", "template", "--template", `{{range .}}printed-{{.Name}} {{.Nope}}{{end}}`},
+			assert: func(t *testing.T, got string, err error) {
+				if err == nil || strings.Contains(got, "printed-dev") {
+					t.Fatalf("expected an execution error and no output, got %v / %q", err, got)
+				}
+			},
+		},
+		{
+			name: "group by tenancy human output",
+			mutate: func(c config.Config) config.Config {
+				c = copyConfig(c)
+				c.Contexts = append(c.Contexts, config.Context{Name: "ci", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.tenancy.oc1..aaaa", Region: "us-ashburn-1"})
+				return c
+			},
+			args: []string{"list", "--group-by", "tenancy"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := strings.Join([]string{
+					"ocid1.…..aaaa:",
+					"    ci (profile=DEFAULT region=us-ashburn-1)",
+					"  * dev (profile=DEFAULT region=us-phoenix-1)",
+					"ocid1.…..zzzz:",
+					"    prod (profile=PROD region=us-ashburn-1)",
+					"",
+				}, "\n")
+				if got != want {
+					t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+				}
+			},
+		},
+		{
+			name:   "group by tenancy json output",
+			mutate: func(c config.Config) config.Config { return c },
+			args:   []string{"list", "--group-by", "tenancy", "-o", "json"},
+			assert: func(t *testing.T, got string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				var groups map[string][]config.Context
+				if err := json.Unmarshal([]byte(got), &groups); err != nil {
+					t.Fatalf("unmarshal: %v\n%s", err, got)
+				}
+				if len(groups) != 2 || len(groups["ocid1.…..aaaa"]) != 1 || groups["ocid1.…..zzzz"][0].Name != "prod" {
+					t.Fatalf("unexpected groups %+v", groups)
+				}
+			},
+		},
+		{
+			name:      "group by unsupported key",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--group-by", "region"},
+			assertErr: "unsupported --group-by",
+		},
+		{
+			name:      "group by with table output",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--group-by", "tenancy", "-o", "table"},
+			assertErr: "--group-by only supports",
+		},
+		{
+			name:      "template flag without template output",
+			mutate:    func(c config.Config) config.Config { return c },
+			args:      []string{"list", "--template", "{{.}}"},
+			assertErr: "--template requires -o template",
+		},
 	}
 
 	for _, tt := range tests {
@@ -181,3 +486,237 @@ func TestListOutputs(t *testing.T) {
 		})
 	}
 }
+
+func TestListOutputFileWritesJSON(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := tmp + "/config.yml"
+	cfg := config.Config{
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+			Source:          config.SourceManual,
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	outPath := filepath.Join(tmp, "contexts.json")
+
+	cmd := newListCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--config", cfgPath, "-o", "json", "--output-file", outPath, "--output-only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected --output-only to suppress stdout, got %q", buf.String())
+	}
+	b, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output file: %v", err)
+	}
+	var out []config.Context
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("output file is not json: %v\n%s", err, b)
+	}
+	if len(out) != 1 || !reflect.DeepEqual(out[0], cfg.Contexts[0]) {
+		t.Fatalf("unexpected file contents: %+v", out)
+	}
+	fi, err := os.Stat(outPath)
+	if err != nil {
+		t.Fatalf("stat output file: %v", err)
+	}
+	if fi.Mode().Perm() != 0o644 {
+		t.Fatalf("expected 0644 output file, got %v", fi.Mode().Perm())
+	}
+}
+
+func TestListOutputFileRequiresParentDir(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{Contexts: []config.Context{{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa"}}}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	outPath := filepath.Join(tmp, "missing", "contexts.json")
+
+	cmd := newListCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--config", cfgPath, "-o", "json", "--output-file", outPath})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "parent directory") || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected a missing parent directory error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing on stdout before the error, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Dir(outPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected the parent directory not to be created, got %v", err)
+	}
+}
+
+func TestListQuietIsTheGlobalFlag(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Contexts:       []config.Context{{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa"}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	outPath := filepath.Join(tmp, "contexts.json")
+	t.Cleanup(func() { cliQuiet = false })
+
+	cmd := newRootCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"list", "-q", "--config", cfgPath, "-o", "json", "--output-file", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !cliQuiet {
+		t.Fatalf("expected -q on list to set the global --quiet")
+	}
+	if !strings.Contains(buf.String(), `"name": "dev"`) {
+		t.Fatalf("expected -q to keep the result on stdout, got %q", buf.String())
+	}
+}
+
+func TestListResolveShowsNamesAndDegradesPerContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..app", Region: "us-phoenix-1"},
+			{Name: "gone", Profile: "GONE", TenancyOCID: "ocid1.tenancy.oc1..gone", CompartmentOCID: "ocid1.compartment.oc1..gone", Region: "us-phoenix-1"},
+		},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	prev := fetchIdentity
+	t.Cleanup(func() { fetchIdentity = prev })
+	fetchIdentity = func(_ context.Context, _, profile, _, tenancyOCID, compartmentOCID, _ string) (oci.IdentityDetails, error) {
+		if profile == "GONE" {
+			return oci.IdentityDetails{}, errors.New("boom")
+		}
+		return oci.IdentityDetails{TenancyName: "acme", TenancyOCID: tenancyOCID, CompartmentName: "app", CompartmentOCID: compartmentOCID}, nil
+	}
+
+	run := func(args ...string) (string, string) {
+		cmd := newListCmd()
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs(append(args, "--config", cfgPath, "--resolve"))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("list --resolve %v: %v", args, err)
+		}
+		return out.String(), errOut.String()
+	}
+
+	out, errOut := run()
+	want := "* dev (profile=DEV region=us-phoenix-1 tenancy=acme compartment=app)\n  gone (profile=GONE region=us-phoenix-1)\n"
+	if out != want {
+		t.Fatalf("unexpected output\nwant: %q\ngot:  %q", want, out)
+	}
+	if !strings.Contains(errOut, "warn: gone: resolve names: boom") {
+		t.Fatalf("expected per-context warning, got %q", errOut)
+	}
+
+	out, _ = run("-o", "json")
+	var views []map[string]any
+	if err := json.Unmarshal([]byte(out), &views); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, out)
+	}
+	if views[0]["tenancy_name"] != "acme" || views[0]["compartment_name"] != "app" || views[1]["tenancy_name"] != nil {
+		t.Fatalf("unexpected json views: %v", views)
+	}
+
+	out, _ = run("-o", "yaml")
+	if !strings.Contains(out, "compartment_name: app") || !strings.Contains(out, "name: dev") {
+		t.Fatalf("expected inline yaml names, got:\n%s", out)
+	}
+}
+
+func TestListResolveRejectsFormatsWithoutNames(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..aaaa", Region: "us-phoenix-1"}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	prev := fetchIdentity
+	t.Cleanup(func() { fetchIdentity = prev })
+	calls := 0
+	fetchIdentity = func(context.Context, string, string, string, string, string, string) (oci.IdentityDetails, error) {
+		calls++
+		return oci.IdentityDetails{}, nil
+	}
+
+	for _, args := range [][]string{{"-o", "plain"}, {"-o", "template", "--template", "{{range .}}{{.Name}}{{end}}"}} {
+		cmd := newListCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append(args, "--config", cfgPath, "--resolve"))
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "--resolve is not supported with -o "+args[1]) {
+			t.Fatalf("list --resolve %v: expected rejection, got %v", args, err)
+		}
+	}
+	if calls != 0 {
+		t.Fatalf("expected no OCI lookups, got %d", calls)
+	}
+}
+
+func TestGroupContextsByTenancyPrefersResolvedNames(t *testing.T) {
+	contexts := []config.Context{
+		{Name: "a", TenancyOCID: "ocid1.tenancy.oc1..zzzz"},
+		{Name: "b", TenancyOCID: "ocid1.tenancy.oc1..aaaa"},
+		{Name: "c", TenancyOCID: "ocid1.tenancy.oc1..zzzz"},
+	}
+	names := map[string]resolvedNames{"c": {Tenancy: "acme"}}
+	groups := groupContextsByTenancy(contexts, names)
+	if len(groups) != 2 || groups[0].label != "acme" || groups[1].label != "ocid1.…..aaaa" {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+	if got := groups[0].contexts; len(got) != 2 || got[0].Name != "a" || got[1].Name != "c" {
+		t.Fatalf("expected members in list order, got %+v", got)
+	}
+}
+
+func TestGroupContextsByTenancyKeepsCollidingLabelsApart(t *testing.T) {
+	contexts := []config.Context{
+		{Name: "a", TenancyOCID: "ocid1.tenancy.oc1..one"},
+		{Name: "b", TenancyOCID: "ocid1.tenancy.oc1..two"},
+		{Name: "c", TenancyOCID: "ocid1.tenancy.oc1..three"},
+	}
+	names := map[string]resolvedNames{"a": {Tenancy: "acme"}, "b": {Tenancy: "acme"}, "c": {Tenancy: "other"}}
+	groups := groupContextsByTenancy(contexts, names)
+	var labels []string
+	for _, g := range groups {
+		labels = append(labels, g.label)
+	}
+	want := []string{"acme (ocid1.tenancy.oc1..one)", "acme (ocid1.tenancy.oc1..two)", "other"}
+	if strings.Join(labels, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected labels %q", labels)
+	}
+	if views := groupedListViews(groups, names); len(views) != 3 || len(views["acme (ocid1.tenancy.oc1..one)"]) != 1 {
+		t.Fatalf("expected one key per tenancy, got %v", views)
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+var (
+	cliLogLevel string
+	cliDebug    bool
+)
+
+// configureLogging installs a leveled text logger on w when --log-level or
+// --debug is given. Otherwise the default logger is left alone, which drops
+// the debug lines emitted by this module.
+func configureLogging(w io.Writer) error {
+	level := strings.TrimSpace(cliLogLevel)
+	if cliDebug {
+		level = "debug"
+	}
+	if level == "" {
+		return nil
+	}
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("--log-level %q: want debug, info, warn, or error", level)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})))
+	return nil
+}
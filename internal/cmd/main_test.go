@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestMain(m *testing.M) {
+	// Tests stub fetchIdentity and listRegionSubscriptions per case; shared
+	// on-disk caches would leak results between them. Tests that exercise a
+	// cache re-enable it.
+	os.Setenv(oci.EnvIdentityCacheTTL, "0")
+	os.Setenv(oci.EnvRegionCacheTTL, "0")
+	os.Exit(m.Run())
+}
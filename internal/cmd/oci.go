@@ -12,22 +12,16 @@ import (
 func newOCICmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var fromEnv bool
 
 	cmd := &cobra.Command{
-		Use:   "oci [--] <oci args...>",
-		Short: "Run OCI CLI with current context defaults",
-		Long:  "Executes the OCI CLI and injects current context defaults for --profile, --region, and --compartment-id when they are not already specified.",
-		Args:  cobra.MinimumNArgs(1),
+		Use:         "oci [--] <oci args...>",
+		Short:       "Run OCI CLI with current context defaults",
+		Annotations: mergeAnnotations,
+		Long:        "Executes the OCI CLI and injects current context defaults for --profile, --region, and --compartment-id when they are not already specified.",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			useGlobal, err := cmd.Flags().GetBool("global")
-			if err != nil {
-				return err
-			}
-			path, err := resolveConfigPath(cfgPath, useGlobal)
-			if err != nil {
-				return err
-			}
-			cfg, err := config.Load(path)
+			cfg, _, err := loadConfigForCommand(cmd, cfgPath, fromEnv)
 			if err != nil {
 				return err
 			}
@@ -39,7 +33,7 @@ func newOCICmd() *cobra.Command {
 				return err
 			}
 
-			finalArgs := buildOCIArgs(args, ctx, cfg.Options.OCIConfigPath)
+			finalArgs := buildOCIArgs(args, ctx, ociConfigOption(cfg))
 			ociCmd := exec.CommandContext(cmd.Context(), "oci", finalArgs...)
 			ociCmd.Stdin = cmd.InOrStdin()
 			ociCmd.Stdout = cmd.OutOrStdout()
@@ -57,6 +51,7 @@ func newOCICmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVar(&fromEnv, "from-env", false, "Use an ephemeral context from OCI_CONTEXT_* environment variables instead of the config file")
 	return cmd
 }
 
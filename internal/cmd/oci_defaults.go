@@ -57,8 +57,8 @@ func ociEnvExportLines(cfg config.Config, rcPath string) []string {
 	lines := []string{
 		fmt.Sprintf("export OCI_CLI_RC_FILE=%s", rcPath),
 	}
-	if cfg.Options.OCIConfigPath != "" {
-		lines = append(lines, fmt.Sprintf("export OCI_CLI_CONFIG_FILE=%s", cfg.Options.OCIConfigPath))
+	if p := ociConfigOption(cfg); p != "" {
+		lines = append(lines, fmt.Sprintf("export OCI_CLI_CONFIG_FILE=%s", p))
 	}
 	return lines
 }
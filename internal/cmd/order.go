@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newOrderCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var top, bottom, up, down bool
+
+	cmd := &cobra.Command{
+		Use:   "order <name> --top|--bottom|--up|--down",
+		Short: "Move a context within the list order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			name := args[0]
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			var delta int
+			switch {
+			case top:
+				delta = -len(cfg.Contexts)
+			case bottom:
+				delta = len(cfg.Contexts)
+			case up:
+				delta = -1
+			case down:
+				delta = 1
+			}
+			if err := cfg.MoveContext(name, delta); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if err := config.Save(path, cfg); err != nil {
+				return err
+			}
+			for i, ctx := range cfg.Contexts {
+				if ctx.Name == name {
+					fmt.Fprintf(cmd.OutOrStdout(), "Moved context %s to position %d of %d\n", name, i+1, len(cfg.Contexts))
+					break
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVar(&top, "top", false, "Move the context to the top of the list")
+	cmd.Flags().BoolVar(&bottom, "bottom", false, "Move the context to the bottom of the list")
+	cmd.Flags().BoolVar(&up, "up", false, "Move the context up one position")
+	cmd.Flags().BoolVar(&down, "down", false, "Move the context down one position")
+	cmd.MarkFlagsMutuallyExclusive("top", "bottom", "up", "down")
+	cmd.MarkFlagsOneRequired("top", "bottom", "up", "down")
+	return cmd
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestOrderMovesContext(t *testing.T) {
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "a", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..aaaa", Region: "us-phoenix-1"},
+			{Name: "b", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..bbbb", Region: "us-phoenix-1"},
+			{Name: "c", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..cccc", Region: "us-phoenix-1"},
+		},
+		CurrentContext: "a",
+	}
+	cfgPath := t.TempDir() + "/config.yml"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newOrderCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+	order := func() string {
+		loaded, err := config.Load(cfgPath)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		var names []string
+		for _, ctx := range loaded.Contexts {
+			names = append(names, ctx.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	steps := []struct {
+		args []string
+		want string
+		out  string
+	}{
+		{[]string{"c", "--top"}, "c,a,b", "Moved context c to position 1 of 3\n"},
+		{[]string{"c", "--up"}, "c,a,b", "Moved context c to position 1 of 3\n"},
+		{[]string{"a", "--down"}, "c,b,a", "Moved context a to position 3 of 3\n"},
+		{[]string{"c", "--bottom"}, "b,a,c", "Moved context c to position 3 of 3\n"},
+	}
+	for _, s := range steps {
+		out, err := run(s.args...)
+		if err != nil {
+			t.Fatalf("order %v: %v", s.args, err)
+		}
+		if out != s.out {
+			t.Fatalf("order %v: output %q, want %q", s.args, out, s.out)
+		}
+		if got := order(); got != s.want {
+			t.Fatalf("order %v: got %s, want %s", s.args, got, s.want)
+		}
+	}
+
+	if _, err := run("missing", "--top"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected missing context error, got %v", err)
+	}
+	if _, err := run("a"); err == nil {
+		t.Fatalf("expected error without a direction flag")
+	}
+	if _, err := run("a", "--up", "--down"); err == nil {
+		t.Fatalf("expected error for conflicting flags")
+	}
+}
@@ -65,7 +65,7 @@ func buildPathsResult(resolution configPathResolution) pathsResult {
 		result.ConfigError = err.Error()
 		return result
 	}
-	result.OCIConfigPath = cfg.Options.OCIConfigPath
+	result.OCIConfigPath = ociConfigOption(cfg)
 	result.SocketPath = cfg.Options.SocketPath
 	return result
 }
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fetchCompartments is a seam so pick can walk a fake hierarchy in tests.
+var fetchCompartments = oci.FetchCompartments
+
+// compartmentLookupTimeout bounds a name lookup across the whole tenancy.
+const compartmentLookupTimeout = 60 * time.Second
+
+type pickResult struct {
+	Context         string `json:"context" yaml:"context"`
+	CompartmentID   string `json:"compartment_id" yaml:"compartment_id"`
+	CompartmentName string `json:"compartment_name" yaml:"compartment_name"`
+	Path            string `json:"path" yaml:"path"`
+	Saved           bool   `json:"saved" yaml:"saved"`
+}
+
+func newPickCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var contextName string
+	var compartmentName string
+	var output string
+	var save bool
+	var timeout time.Duration
+	var maxDepth int
+
+	cmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Resolve a compartment by name without a TTY and print its OCID",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(compartmentName) == "" {
+				return fmt.Errorf("--compartment-name is required")
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			target := strings.TrimSpace(contextName)
+			if target == "" {
+				target = cfg.CurrentContext
+			}
+			if target == "" {
+				return fmt.Errorf("no current context set; pass --context")
+			}
+			ctx, err := cfg.GetContext(target)
+			if err != nil {
+				return err
+			}
+			ociCfgPath, err := resolveOCIConfigPath(cfg)
+			if err != nil {
+				return err
+			}
+
+			c, cancel := context.WithTimeout(ociContext(cmd.Context(), cfg), timeout)
+			defer cancel()
+			match, err := resolveCompartmentByName(c, ociCfgPath, ctx, compartmentName, maxDepth, cfg.Options.IdentityConcurrencyLimit())
+			if err != nil {
+				return err
+			}
+			result := pickResult{
+				Context:         ctx.Name,
+				CompartmentID:   match.comp.ID,
+				CompartmentName: match.comp.Name,
+				Path:            match.path,
+			}
+
+			if save {
+				ctx.SetCompartment(match.comp.ID, match.comp.Status)
+				ctx.Source = config.SourceManual
+				if err := cfg.UpsertContext(ctx); err != nil {
+					return err
+				}
+				if err := config.Save(path, cfg); err != nil {
+					return err
+				}
+				if ctx.Name == cfg.CurrentContext {
+					if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+						return err
+					}
+				}
+				result.Saved = true
+				fmt.Fprintf(infoWriter(cmd.ErrOrStderr()), "Saved compartment %s to context %s\n", match.path, ctx.Name)
+			}
+
+			switch strings.ToLower(output) {
+			case "", "text":
+				fmt.Fprintln(cmd.OutOrStdout(), result.CompartmentID)
+				return nil
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			case "yaml", "yml":
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				defer enc.Close()
+				return enc.Encode(result)
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVar(&contextName, "context", "", "Context whose tenancy is searched (default current)")
+	cmd.Flags().StringVar(&compartmentName, "compartment-name", "", "Compartment name, or a trailing path like Eng/Platform to disambiguate")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json|yaml")
+	cmd.Flags().BoolVar(&save, "save", false, "Store the resolved compartment on the context")
+	cmd.Flags().DurationVar(&timeout, "timeout", compartmentLookupTimeout, "Overall timeout for compartment lookups")
+	addMaxDepthFlag(cmd, &maxDepth)
+	return cmd
+}
+
+// addMaxDepthFlag registers --max-depth, which bounds how many levels below
+// the tenancy root a compartment search lists.
+func addMaxDepthFlag(cmd *cobra.Command, maxDepth *int) {
+	cmd.Flags().IntVar(maxDepth, "max-depth", 0, fmt.Sprintf("Levels below the tenancy root to search (0 = up to %d)", oci.DefaultWalkDepth))
+}
+
+type compartmentMatch struct {
+	comp oci.Compartment
+	path string
+}
+
+// resolveCompartmentByName walks the tenancy with oci.WalkCompartments, down
+// to maxDepth levels (0 for oci.DefaultWalkDepth) with up to workers
+// concurrent list calls, and returns the single ACTIVE
+// compartment whose name (or trailing name path) matches. Multiple matches are
+// reported with their full paths; a cycle in the hierarchy is an error.
+func resolveCompartmentByName(ctx context.Context, ociCfgPath string, c config.Context, name string, maxDepth, workers int) (compartmentMatch, error) {
+	want := splitCompartmentPath(name)
+	list := func(ctx context.Context, parentID string) ([]oci.Compartment, error) {
+		return fetchCompartments(ctx, ociCfgPath, c.Profile, c.Region, parentID)
+	}
+	children, err := oci.WalkCompartments(ctx, c.TenancyOCID, list, oci.WalkOptions{MaxDepth: maxDepth, Workers: workers, ActiveOnly: true})
+	if err != nil {
+		return compartmentMatch{}, err
+	}
+	var matches []compartmentMatch
+	var match func(parentID string, path []string)
+	match = func(parentID string, path []string) {
+		for _, child := range children[parentID] {
+			childPath := append(append([]string(nil), path...), child.Name)
+			if hasPathSuffix(childPath, want) {
+				matches = append(matches, compartmentMatch{comp: child, path: strings.Join(childPath, "/")})
+			}
+			match(child.ID, childPath)
+		}
+	}
+	match(c.TenancyOCID, nil)
+	switch len(matches) {
+	case 0:
+		return compartmentMatch{}, fmt.Errorf("compartment %q not found in tenancy of context %s", name, c.Name)
+	case 1:
+		return matches[0], nil
+	}
+	paths := make([]string, 0, len(matches))
+	for _, m := range matches {
+		paths = append(paths, m.path)
+	}
+	sort.Strings(paths)
+	return compartmentMatch{}, fmt.Errorf("compartment %q is ambiguous (%d matches: %s); use a longer path", name, len(matches), strings.Join(paths, ", "))
+}
+
+func splitCompartmentPath(name string) []string {
+	var parts []string
+	for _, p := range strings.Split(name, "/") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func hasPathSuffix(path, suffix []string) bool {
+	if len(suffix) == 0 || len(suffix) > len(path) {
+		return false
+	}
+	offset := len(path) - len(suffix)
+	for i, s := range suffix {
+		if path[offset+i] != s {
+			return false
+		}
+	}
+	return true
+}
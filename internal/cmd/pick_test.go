@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestPickResolvesCompartmentByName(t *testing.T) {
+	tenancy := "ocid1.tenancy.oc1..ten"
+	tree := map[string][]oci.Compartment{
+		tenancy: {
+			{ID: "ocid1.compartment.oc1..eng", Name: "Eng", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..ops", Name: "Ops", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..old", Name: "Platform", Status: "DELETED"},
+		},
+		"ocid1.compartment.oc1..eng": {
+			{ID: "ocid1.compartment.oc1..platform", Name: "Platform", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..shared-eng", Name: "shared", Status: "ACTIVE"},
+		},
+		"ocid1.compartment.oc1..ops": {
+			{ID: "ocid1.compartment.oc1..shared-ops", Name: "shared", Status: "ACTIVE"},
+		},
+	}
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(ctx context.Context, profileConfigPath, profile, region, parentID string) ([]oci.Compartment, error) {
+		return tree[parentID], nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     tenancy,
+			CompartmentOCID: tenancy,
+			Region:          "us-phoenix-1",
+		}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := newPickCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append([]string{"--config", cfgPath, "--context", "dev"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	got, err := run("--compartment-name", "Platform")
+	if err != nil {
+		t.Fatalf("pick Platform: %v", err)
+	}
+	if got != "ocid1.compartment.oc1..platform\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	if _, err := run("--compartment-name", "shared"); err == nil || !strings.Contains(err.Error(), "ambiguous") || !strings.Contains(err.Error(), "Eng/shared, Ops/shared") {
+		t.Fatalf("expected ambiguity error listing paths, got %v", err)
+	}
+	if _, err := run("--compartment-name", "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	// --max-depth 1 lists only the tenancy's children, so Eng/Platform is out of reach.
+	if _, err := run("--compartment-name", "Platform", "--max-depth", "1"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error with --max-depth 1, got %v", err)
+	}
+
+	got, err = run("--compartment-name", "Ops/shared", "--save", "-o", "json")
+	if err != nil {
+		t.Fatalf("pick Ops/shared: %v", err)
+	}
+	var res pickResult
+	if err := json.Unmarshal([]byte(got), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, got)
+	}
+	if res.CompartmentID != "ocid1.compartment.oc1..shared-ops" || res.Path != "Ops/shared" || !res.Saved {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if ctx, _ := loaded.GetContext("dev"); ctx.CompartmentOCID != "ocid1.compartment.oc1..shared-ops" {
+		t.Fatalf("expected compartment saved, got %+v", ctx)
+	}
+}
+
+func TestPickFailsOnSelfReferencingCompartment(t *testing.T) {
+	tenancy := "ocid1.tenancy.oc1..ten"
+	loop := oci.Compartment{ID: "ocid1.compartment.oc1..loop", Name: "loop", Status: "ACTIVE"}
+	tree := map[string][]oci.Compartment{
+		tenancy: {loop},
+		loop.ID: {loop},
+	}
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(ctx context.Context, profileConfigPath, profile, region, parentID string) ([]oci.Compartment, error) {
+		return tree[parentID], nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{Name: "dev", Profile: "DEFAULT", TenancyOCID: tenancy, CompartmentOCID: tenancy}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newPickCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--config", cfgPath, "--context", "dev", "--compartment-name", "missing", "--timeout", "5s"})
+	if err := cmd.Execute(); !errors.Is(err, oci.ErrCompartmentCycle) {
+		t.Fatalf("expected ErrCompartmentCycle, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+const defaultPromptTemplate = "[{context}@{region}]"
+
+func newPromptCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var template string
+	var resolve bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a compact current-context string for shell prompts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			if _, unknown := expandTemplate(template, promptValues(config.Context{})); len(unknown) > 0 {
+				return fmt.Errorf("unknown --template token %s (use {context}, {profile}, {compartment}, {region}, {tenancy})", strings.Join(unknown, ", "))
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				// No config yet: an empty prompt segment, not an error.
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if cfg.CurrentContext == "" {
+				return nil
+			}
+			ctx, err := cfg.GetContext(cfg.CurrentContext)
+			if err != nil {
+				return err
+			}
+			values := promptValues(ctx)
+			if resolve {
+				resolvePromptNames(cmd.Context(), cfg, ctx, timeout, values)
+			}
+			out, _ := expandTemplate(template, values)
+			fmt.Fprintln(cmd.OutOrStdout(), out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&template, "template", "t", defaultPromptTemplate, "Output template using {context}, {profile}, {compartment}, {region}, {tenancy}")
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "Resolve tenancy/compartment names through OCI identity (slower)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Second, "Timeout for --resolve lookups")
+	return cmd
+}
+
+// promptValues fills template values from stored config only; OCIDs are
+// abbreviated to keep the prompt short.
+func promptValues(ctx config.Context) map[string]string {
+	return map[string]string{
+		"context":     ctx.Name,
+		"profile":     ctx.Profile,
+		"region":      ctx.Region,
+		"tenancy":     abbrevOCID(ctx.TenancyOCID),
+		"compartment": abbrevOCID(ctx.CompartmentOCID),
+	}
+}
+
+// resolvePromptNames replaces OCIDs with friendly names when the lookup
+// succeeds in time. Failures keep the OCIDs so the prompt still renders.
+func resolvePromptNames(parent context.Context, cfg config.Config, ctx config.Context, timeout time.Duration, values map[string]string) {
+	ociCfgPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return
+	}
+	c, cancel := context.WithTimeout(ociContext(parent, cfg), timeout)
+	defer cancel()
+	details, err := identityFetcher(cfg, false)(c, ociCfgPath, ctx.Profile, ctx.Region, ctx.TenancyOCID, ctx.CompartmentOCID, ctx.User)
+	if err != nil {
+		return
+	}
+	if details.TenancyName != "" {
+		values["tenancy"] = details.TenancyName
+	}
+	if details.CompartmentName != "" {
+		values["compartment"] = details.CompartmentName
+	}
+}
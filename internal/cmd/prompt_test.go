@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestPromptRendersTemplate(t *testing.T) {
+	restore := stubIdentityUnexpected(t)
+	defer restore()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaaaaaatenancy",
+			CompartmentOCID: "ocid1.compartment.oc1..aaaaaaaateam-a",
+			Region:          "us-phoenix-1",
+		}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(path string, args ...string) (string, error) {
+		cmd := newPromptCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(args, "--config", path))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	// No current context and no config file both render nothing.
+	if out, err := run(cfgPath); err != nil || out != "" {
+		t.Fatalf("expected empty prompt without current context, got %q %v", out, err)
+	}
+	if out, err := run(filepath.Join(dir, "missing.yml")); err != nil || out != "" {
+		t.Fatalf("expected empty prompt without config, got %q %v", out, err)
+	}
+
+	cfg.CurrentContext = "dev"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if out, err := run(cfgPath); err != nil || out != "[dev@us-phoenix-1]\n" {
+		t.Fatalf("unexpected default prompt %q %v", out, err)
+	}
+	out, err := run(cfgPath, "--template", "{context}:{compartment}")
+	if err != nil || out != "dev:"+abbrevOCID(cfg.Contexts[0].CompartmentOCID)+"\n" {
+		t.Fatalf("unexpected cached prompt %q %v", out, err)
+	}
+	if _, err := run(cfgPath, "--template", "{realm}"); err == nil || !strings.Contains(err.Error(), "{realm}") {
+		t.Fatalf("expected unknown token error, got %v", err)
+	}
+
+	restoreOK := stubIdentity()
+	out, err = run(cfgPath, "--resolve", "--template", "[{context}:{compartment}@{region}]")
+	restoreOK()
+	if err != nil || out != "[dev:Compartment Friendly@us-phoenix-1]\n" {
+		t.Fatalf("unexpected resolved prompt %q %v", out, err)
+	}
+
+	original := fetchIdentity
+	fetchIdentity = func(context.Context, string, string, string, string, string, string) (oci.IdentityDetails, error) {
+		return oci.IdentityDetails{}, errors.New("offline")
+	}
+	out, err = run(cfgPath, "--resolve", "--template", "{compartment}")
+	fetchIdentity = original
+	if err != nil || out != abbrevOCID(cfg.Contexts[0].CompartmentOCID)+"\n" {
+		t.Fatalf("expected fallback to OCID on lookup failure, got %q %v", out, err)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// regionsView is the `regions -o json` shape.
+type regionsView struct {
+	Context string   `json:"context"`
+	Tenancy string   `json:"tenancy_id"`
+	Region  string   `json:"region"`
+	Regions []string `json:"regions"`
+}
+
+func newRegionsCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var contextName string
+	var output string
+	var noCache bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:         "regions [--context <name>] [-o json|plain]",
+		Short:       "List the regions a context's tenancy subscribes to (cached per tenancy)",
+		Annotations: mergeAnnotations,
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			format := strings.ToLower(output)
+			switch format {
+			case "", "json", "plain":
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+			cfg, _, err := loadReadConfig(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			ctx, err := targetContext(cfg, contextName)
+			if err != nil {
+				return err
+			}
+			d, err := networkTimeout(cmd, cfg, statusLookupTimeout)
+			if err != nil {
+				return err
+			}
+			ociCfgPath, err := resolveOCIConfigPath(cfg)
+			if err != nil {
+				return err
+			}
+			c, cancel := context.WithTimeout(ociContext(cmd.Context(), cfg), d)
+			defer cancel()
+			regions, err := subscribedRegions(c, cfg, ociCfgPath, ctx, noCache)
+			if err != nil {
+				return fmt.Errorf("list regions: %w", withOCIHint(err))
+			}
+
+			switch format {
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if regions == nil {
+					regions = []string{}
+				}
+				return enc.Encode(regionsView{Context: ctx.Name, Tenancy: ctx.TenancyOCID, Region: ctx.Region, Regions: regions})
+			case "plain":
+				for _, r := range regions {
+					fmt.Fprintln(cmd.OutOrStdout(), r)
+				}
+			default:
+				// Mark the context's configured region, as list marks the current context.
+				for _, r := range regions {
+					marker := " "
+					if r == ctx.Region {
+						marker = "*"
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, r)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&contextName, "context", "x", "", "List this context's regions instead of the current one's")
+	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|plain (default: one region per line, * marks the context's region)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the on-disk region cache")
+	cmd.Flags().DurationVar(&timeout, "timeout", statusLookupTimeout, "Timeout for the OCI call (overrides options.network_timeout)")
+	return cmd
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestRegionsListsSubscriptionsAndSharesCacheWithTUI(t *testing.T) {
+	t.Setenv(oci.EnvRegionCacheTTL, "1h")
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci", SocketPath: filepath.Join(tmp, "daemon.sock")},
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev", CompartmentOCID: "ocid1.tenancy.oc1..dev", Region: "us-ashburn-1"},
+			{Name: "dev-phx", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev", CompartmentOCID: "ocid1.tenancy.oc1..dev", Region: "us-phoenix-1"},
+		},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	calls := 0
+	prev := listRegionSubscriptions
+	t.Cleanup(func() { listRegionSubscriptions = prev })
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) {
+		calls++
+		return []string{"us-ashburn-1", "us-phoenix-1"}, nil
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newRegionsCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("regions %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	if out := run(); out != "* us-ashburn-1\n  us-phoenix-1\n" {
+		t.Fatalf("unexpected default output %q", out)
+	}
+	if out := run("--context", "dev-phx", "-o", "plain"); out != "us-ashburn-1\nus-phoenix-1\n" {
+		t.Fatalf("unexpected plain output %q", out)
+	}
+	var view regionsView
+	if err := json.Unmarshal([]byte(run("-o", "json")), &view); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if view.Context != "dev" || view.Tenancy != "ocid1.tenancy.oc1..dev" || !reflect.DeepEqual(view.Regions, []string{"us-ashburn-1", "us-phoenix-1"}) {
+		t.Fatalf("unexpected json view %+v", view)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one OCI call for the shared tenancy, got %d", calls)
+	}
+	run("--no-cache", "-o", "plain")
+	if calls != 2 {
+		t.Fatalf("expected --no-cache to call OCI, got %d calls", calls)
+	}
+
+	// The TUI region picker reads the same on-disk cache.
+	ci := contextItem{Context: cfg.Contexts[0]}
+	m := newTuiModel(cfg, cfgPath, []list.Item{ci}, nil, "")
+	msg := m.loadRegionsCmd(context.Background(), 1, ci)().(regionResultMsg)
+	if msg.err != nil || !reflect.DeepEqual(msg.items, []string{"us-ashburn-1", "us-phoenix-1"}) || calls != 2 {
+		t.Fatalf("expected TUI to reuse cached regions: %+v calls=%d", msg, calls)
+	}
+}
@@ -14,8 +14,16 @@ var (
 	date    = "unknown"
 
 	cliNoInteractive bool
+	cliQuiet         bool
+	cliMerge         bool
+	cliOCIConfig     string
 )
 
+// mergeAnnotations marks a read command that honors --merge by loading its
+// config through loadReadConfig. Other commands reject --merge, since they
+// read and write the resolved config file alone.
+var mergeAnnotations = map[string]string{"merge": "true"}
+
 func buildVersionString() string {
 	parts := []string{version}
 	if commit != "" && commit != "none" {
@@ -36,6 +44,12 @@ func newRootCmd() *cobra.Command {
 		Short:         "Manage OCI contexts (profile, tenancy, compartment, region)",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cliMerge && cmd.Annotations["merge"] == "" {
+				return fmt.Errorf("--merge is not supported by %s: it reads and writes the resolved config file only", cmd.CommandPath())
+			}
+			return configureLogging(cmd.ErrOrStderr())
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if verboseVersion || versionCount >= 2 {
 				_, err := fmt.Fprintln(cmd.OutOrStdout(), buildVersionString())
@@ -61,6 +75,11 @@ func newRootCmd() *cobra.Command {
 	pf.String("config", "", "Path to config file (default project .oci-context.yml else $HOME/.oci-context/config.yml)")
 	pf.BoolP("global", "g", false, "Force use of global config (~/.oci-context/config.yml)")
 	pf.BoolVar(&cliNoInteractive, "no-interactive", false, "Disable interactive login/setup flows")
+	pf.BoolVarP(&cliQuiet, "quiet", "q", false, "Suppress informational progress output (errors and warnings still print)")
+	pf.BoolVar(&cliMerge, "merge", false, "Read commands layer a discovered project config over the global config (other commands reject it)")
+	pf.StringVar(&cliOCIConfig, "oci-config", "", "OCI CLI config file for this run (overrides options.oci_config_path)")
+	pf.StringVar(&cliLogLevel, "log-level", "", "Log to stderr at this level: debug|info|warn|error (default off)")
+	pf.BoolVarP(&cliDebug, "debug", "V", false, "Shorthand for --log-level debug")
 
 	// Subcommands
 	cmd.AddCommand(
@@ -69,19 +88,33 @@ func newRootCmd() *cobra.Command {
 		newPathsCmd(),
 		newListCmd(),
 		newCurrentCmd(),
+		newContextOfCmd(),
 		newAuthCmd(),
 		newServiceCmd(),
 		newOCICmd(),
 		newUseCmd(),
+		newUnsetCmd(),
 		newAddCmd(),
 		newSetCmd(),
+		newCopyCmd(),
+		newEditCmd(),
+		newDiffCmd(),
+		newOrderCmd(),
 		newDeleteCmd(),
 		newStatusCmd(),
+		newPromptCmd(),
+		newPickCmd(),
+		newTreeCmd(),
+		newValidateCmd(),
+		newRegionsCmd(),
 		newSetupCmd(),
 		newToolCmd(),
 		newExportCmd(),
 		newImportCmd(),
+		newExportConfigCmd(),
+		newImportConfigCmd(),
 		newDaemonCmd(),
+		newCacheCmd(),
 		newDoctorCmd(),
 		newTuiCmd(),
 	)
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"bytes"
+	"log/slog"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
 )
 
 func TestRootVersionFlagLong(t *testing.T) {
@@ -138,3 +142,47 @@ func TestRootNoArgsShowsHelp(t *testing.T) {
 		t.Fatalf("expected help output, got %q", got)
 	}
 }
+
+func TestRootVerboseLogsDebugLinesToStderr(t *testing.T) {
+	orig := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(orig) })
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := config.Save(cfgPath, config.Config{}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, string, error) {
+		cmd := newRootCmd()
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs(append(args, "list", "--config", cfgPath))
+		err := cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	_, stderr, err := run()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected no logging by default, got %q", stderr)
+	}
+
+	_, stderr, err = run("-V")
+	if err != nil {
+		t.Fatalf("list -V: %v", err)
+	}
+	for _, want := range []string{"level=DEBUG", `msg="config path resolved"`, "source=explicit", `msg="config lock acquired"`} {
+		if !strings.Contains(stderr, want) {
+			t.Fatalf("expected %q in debug log:\n%s", want, stderr)
+		}
+	}
+
+	slog.SetDefault(orig)
+	if _, stderr, _ = run("--log-level", "warn"); stderr != "" {
+		t.Fatalf("expected warn level to hide debug lines, got %q", stderr)
+	}
+	if _, _, err = run("--log-level", "loud"); err == nil || !strings.Contains(err.Error(), "--log-level") {
+		t.Fatalf("expected invalid level error, got %v", err)
+	}
+}
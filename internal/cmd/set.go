@@ -1,16 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/ocidutil"
 	"github.com/spf13/cobra"
 )
 
 func newSetCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
-	var region, profile, authMethod, tenancy, compartment, user, notes string
+	var overrides contextOverrides
+	var compartmentName string
+	var maxDepth int
 
 	cmd := &cobra.Command{
 		Use:   "set <name>",
@@ -22,6 +27,9 @@ func newSetCmd() *cobra.Command {
 				return err
 			}
 			name := args[0]
+			if cmd.Flags().Changed("max-depth") && strings.TrimSpace(compartmentName) == "" {
+				return fmt.Errorf("--max-depth only applies with --compartment-name")
+			}
 			path, err := resolveConfigPath(cfgPath, useGlobal)
 			if err != nil {
 				return err
@@ -34,27 +42,27 @@ func newSetCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if region != "" {
-				ctx.Region = region
-			}
-			if profile != "" {
-				ctx.Profile = profile
-			}
-			if authMethod != "" {
-				ctx.AuthMethod = authMethod
-			}
-			if tenancy != "" {
-				ctx.TenancyOCID = tenancy
-			}
-			if compartment != "" {
-				ctx.CompartmentOCID = compartment
+			if err := overrides.apply(&ctx); err != nil {
+				return err
 			}
-			if user != "" {
-				ctx.User = user
+			if strings.TrimSpace(compartmentName) != "" {
+				ociCfgPath, err := resolveOCIConfigPath(cfg)
+				if err != nil {
+					return err
+				}
+				c, cancel := context.WithTimeout(ociContext(cmd.Context(), cfg), compartmentLookupTimeout)
+				defer cancel()
+				match, err := resolveCompartmentByName(c, ociCfgPath, ctx, compartmentName, maxDepth, cfg.Options.IdentityConcurrencyLimit())
+				if err != nil {
+					return err
+				}
+				ctx.SetCompartment(match.comp.ID, match.comp.Status)
 			}
-			if notes != "" {
-				ctx.Notes = notes
+			if err := ctx.Validate(); err != nil {
+				return err
 			}
+			// Hand edits take ownership of the context so import --prune leaves it alone.
+			ctx.Source = config.SourceManual
 			if err := cfg.UpsertContext(ctx); err != nil {
 				return err
 			}
@@ -73,13 +81,84 @@ func newSetCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
-	cmd.Flags().StringVarP(&region, "region", "r", "", "OCI region")
-	cmd.Flags().StringVarP(&profile, "profile", "p", "", "OCI CLI profile")
-	cmd.Flags().StringVarP(&authMethod, "auth-method", "a", "", "OCI auth method (api_key|security_token|instance_principal|resource_principal|instance_obo_user|oke_workload_identity)")
-	cmd.Flags().StringVarP(&tenancy, "tenancy", "t", "", "Tenancy OCID")
-	cmd.Flags().StringVarP(&compartment, "compartment", "m", "", "Compartment OCID")
-	cmd.Flags().StringVarP(&user, "user", "u", "", "User hint")
-	cmd.Flags().StringVarP(&notes, "notes", "N", "", "Notes")
+	overrides.addFlags(cmd)
+	cmd.Flags().StringVar(&compartmentName, "compartment-name", "", "Resolve the compartment by name or trailing path (e.g. Team-A/Platform)")
+	cmd.MarkFlagsMutuallyExclusive("compartment", "compartment-name")
+	addMaxDepthFlag(cmd, &maxDepth)
 
 	return cmd
 }
+
+// contextOverrides holds the field flags shared by set and copy. Only flags
+// passed on the command line are applied, so an explicit empty value (for
+// example --notes "") clears that field; the caller validates the result.
+type contextOverrides struct {
+	region, profile, authMethod, tenancy, compartment, user, notes string
+	clearNotes                                                     bool
+	tags                                                           []string
+	cmd                                                            *cobra.Command
+}
+
+func (o *contextOverrides) addFlags(cmd *cobra.Command) {
+	o.cmd = cmd
+	cmd.Flags().StringVarP(&o.region, "region", "r", "", "OCI region")
+	cmd.Flags().StringVarP(&o.profile, "profile", "p", "", "OCI CLI profile")
+	cmd.Flags().StringVarP(&o.authMethod, "auth-method", "a", "", "OCI auth method (api_key|security_token|instance_principal|resource_principal|instance_obo_user|oke_workload_identity)")
+	cmd.Flags().StringVarP(&o.tenancy, "tenancy", "t", "", "Tenancy OCID")
+	cmd.Flags().StringVarP(&o.compartment, "compartment", "m", "", "Compartment OCID")
+	cmd.Flags().StringVarP(&o.user, "user", "u", "", "User hint")
+	cmd.Flags().StringVarP(&o.notes, "notes", "N", "", "Notes")
+	cmd.Flags().BoolVar(&o.clearNotes, "clear-notes", false, "Clear notes (same as --notes \"\")")
+	cmd.Flags().StringArrayVar(&o.tags, "tag", nil, "Set tag key=value (repeatable; key= removes it)")
+	cmd.MarkFlagsMutuallyExclusive("notes", "clear-notes")
+}
+
+// passed reports whether flag was given on the command line, even as "".
+func (o contextOverrides) passed(flag string) bool {
+	return o.cmd != nil && o.cmd.Flags().Changed(flag)
+}
+
+func (o contextOverrides) apply(ctx *config.Context) error {
+	if o.passed("region") {
+		ctx.Region = o.region
+	}
+	if o.passed("profile") {
+		ctx.Profile = o.profile
+	}
+	if o.passed("auth-method") {
+		ctx.AuthMethod = o.authMethod
+	}
+	if o.passed("tenancy") {
+		if o.tenancy != "" {
+			if err := ocidutil.Validate("tenancy", o.tenancy, ocidutil.TypeTenancy); err != nil {
+				return err
+			}
+		}
+		ctx.TenancyOCID = o.tenancy
+	}
+	if o.passed("compartment") {
+		if o.compartment != "" {
+			if err := ocidutil.Validate("compartment", o.compartment, ocidutil.TypeCompartment, ocidutil.TypeTenancy); err != nil {
+				return err
+			}
+		}
+		ctx.SetCompartment(o.compartment, "")
+	}
+	if o.passed("user") {
+		ctx.User = o.user
+	}
+	if o.passed("notes") {
+		ctx.Notes = o.notes
+	}
+	if o.clearNotes {
+		ctx.Notes = ""
+	}
+	if len(o.tags) > 0 {
+		updates, err := config.ParseTags(o.tags)
+		if err != nil {
+			return err
+		}
+		ctx.Tags = config.MergeTags(ctx.Tags, updates)
+	}
+	return nil
+}
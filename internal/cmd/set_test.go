@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestSetCompartmentName(t *testing.T) {
+	tenancy := "ocid1.tenancy.oc1..ten"
+	tree := map[string][]oci.Compartment{
+		tenancy: {
+			{ID: "ocid1.compartment.oc1..team-a", Name: "Team-A", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..team-b", Name: "Team-B", Status: "ACTIVE"},
+		},
+		"ocid1.compartment.oc1..team-a": {{ID: "ocid1.compartment.oc1..platform-a", Name: "Platform", Status: "ACTIVE"}},
+		"ocid1.compartment.oc1..team-b": {{ID: "ocid1.compartment.oc1..platform-b", Name: "Platform", Status: "ACTIVE"}},
+	}
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(ctx context.Context, profileConfigPath, profile, region, parentID string) ([]oci.Compartment, error) {
+		return tree[parentID], nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     tenancy,
+			CompartmentOCID: tenancy,
+			Region:          "us-phoenix-1",
+		}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newSetCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(append([]string{"dev"}, args...), "--config", cfgPath))
+		return cmd.Execute()
+	}
+
+	if err := run("--compartment-name", "Platform"); err == nil || !strings.Contains(err.Error(), "Team-A/Platform, Team-B/Platform") {
+		t.Fatalf("expected ambiguity error listing candidates, got %v", err)
+	}
+	if err := run("--compartment-name", "Nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if err := run("--compartment-name", "Platform", "--compartment", tenancy); err == nil {
+		t.Fatalf("expected --compartment and --compartment-name to conflict")
+	}
+	if err := run("--compartment-name", "Team-A/Platform"); err != nil {
+		t.Fatalf("set --compartment-name: %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := loaded.Contexts[0].CompartmentOCID; got != "ocid1.compartment.oc1..platform-a" {
+		t.Fatalf("unexpected compartment %q", got)
+	}
+}
+
+func TestSetExplicitEmptyValuesClearOptionalFields(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..ten",
+			CompartmentOCID: "ocid1.compartment.oc1..app",
+			Region:          "us-phoenix-1",
+			User:            "ops",
+			Notes:           "old notes",
+		}},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newSetCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(append([]string{"dev"}, args...), "--config", cfgPath))
+		return cmd.Execute()
+	}
+	load := func() config.Context {
+		t.Helper()
+		loaded, err := config.Load(cfgPath)
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		ctx, err := loaded.GetContext("dev")
+		if err != nil {
+			t.Fatalf("get context: %v", err)
+		}
+		return ctx
+	}
+
+	if err := run("--region", "us-ashburn-1"); err != nil {
+		t.Fatalf("set --region: %v", err)
+	}
+	if ctx := load(); ctx.Notes != "old notes" || ctx.User != "ops" {
+		t.Fatalf("fields not passed must be kept: %+v", ctx)
+	}
+	if err := run("--notes", "", "--user", ""); err != nil {
+		t.Fatalf("set --notes \"\": %v", err)
+	}
+	if ctx := load(); ctx.Notes != "" || ctx.User != "" || ctx.Region != "us-ashburn-1" {
+		t.Fatalf("expected notes and user cleared: %+v", ctx)
+	}
+	if err := run("--notes", "again"); err != nil {
+		t.Fatalf("set --notes: %v", err)
+	}
+	if err := run("--clear-notes"); err != nil {
+		t.Fatalf("set --clear-notes: %v", err)
+	}
+	if ctx := load(); ctx.Notes != "" {
+		t.Fatalf("expected --clear-notes to clear notes: %+v", ctx)
+	}
+	if err := run("--clear-notes", "--notes", "x"); err == nil {
+		t.Fatalf("expected --clear-notes and --notes to conflict")
+	}
+	for _, flag := range []string{"--profile", "--tenancy", "--compartment"} {
+		if err := run(flag, ""); err == nil || !strings.Contains(err.Error(), "is required") {
+			t.Fatalf("%s \"\": expected a required-field error, got %v", flag, err)
+		}
+	}
+	if ctx := load(); ctx.Profile != "DEFAULT" || ctx.TenancyOCID == "" || ctx.CompartmentOCID == "" {
+		t.Fatalf("failed validation must not save: %+v", ctx)
+	}
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -16,130 +20,472 @@ import (
 // fetchIdentity is a seam to allow testing without hitting the network.
 var fetchIdentity = oci.FetchIdentityDetails
 
+// statusLookupTimeout is the default for status identity lookups.
+const statusLookupTimeout = 15 * time.Second
+
 func newStatusCmd() *cobra.Command {
 	var useGlobal bool
+	var fromEnv bool
 	var cfgPath string
 	var output string
+	var outputFile string
+	var outputOnly bool
 	var plain bool
 	var noLookup bool
+	var watch bool
+	var interval time.Duration
+	var fieldList string
+	var noCache bool
+	var timeout time.Duration
+	var contextName string
+	var showPath bool
+	var templateText string
+	var compareLive bool
+	var verbose bool
 
 	cmd := &cobra.Command{
-		Use:   "status",
-		Short: "Show current context details (friendly names)",
+		Use:         "status",
+		Short:       "Show current context details (friendly names)",
+		Annotations: mergeAnnotations,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			useGlobal, err := cmd.Flags().GetBool("global")
+			fields, err := parseStatusFields(fieldList)
 			if err != nil {
 				return err
 			}
-			path, err := resolveConfigPath(cfgPath, useGlobal)
+			tmpl, err := parseOutputTemplate(output, templateText)
 			if err != nil {
 				return err
 			}
-			cfg, err := config.Load(path)
-			if err != nil {
-				return err
+			if tmpl != nil && (len(fields) > 0 || plain) {
+				return fmt.Errorf("-o template cannot be combined with --fields or --plain")
 			}
-			if cfg.CurrentContext == "" {
-				return fmt.Errorf("no current context set")
+			if o := strings.ToLower(output); plain && o != "" && o != "plain" {
+				return fmt.Errorf("--plain cannot be combined with -o %s", output)
 			}
-			ctx, err := cfg.GetContext(cfg.CurrentContext)
-			if err != nil {
-				return err
+			var paths *compartmentPaths
+			if showPath {
+				if noLookup {
+					return fmt.Errorf("--path needs OCI identity lookups; drop --cached/--no-lookup")
+				}
+				paths = newCompartmentPaths()
+			}
+			if compareLive {
+				if noLookup {
+					return fmt.Errorf("--compare-live needs OCI identity lookups; drop --cached/--no-lookup")
+				}
+				if watch {
+					return fmt.Errorf("--compare-live cannot be combined with --watch")
+				}
+				noCache = true
+			}
+			if watch {
+				// Each refresh should show what OCI says now, including expired auth.
+				noCache = true
 			}
-			resp := map[string]string{
-				"context":        ctx.Name,
-				"profile":        ctx.Profile,
-				"auth_method":    config.NormalizeAuthMethod(ctx.AuthMethod),
-				"tenancy":        "",
-				"tenancy_id":     ctx.TenancyOCID,
-				"compartment":    "",
-				"compartment_id": ctx.CompartmentOCID,
-				"user":           "",
-				"user_id":        ctx.User,
-				"region":         ctx.Region,
-			}
-			if !noLookup {
-				ctxTimeout, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
-				defer cancel()
-				details, err := fetchIdentity(ctxTimeout, cfg.Options.OCIConfigPath, ctx.Profile, ctx.Region, ctx.TenancyOCID, ctx.CompartmentOCID, ctx.User)
+			// currentContext is the config's current context as of the last load,
+			// so the table marks a --context target only when it is current.
+			var currentContext string
+			load := func(ctx context.Context) (map[string]string, error) {
+				resp, current, err := loadStatus(ctx, cmd, cfgPath, contextName, fromEnv, noLookup, noCache, paths)
+				currentContext = current
+				return resp, err
+			}
+			if watch {
+				if plain || output != "" || outputFile != "" {
+					return fmt.Errorf("--watch only supports the default human-readable output")
+				}
+				if interval <= 0 {
+					return fmt.Errorf("--interval must be positive")
+				}
+				return watchStatus(cmd, interval, fields, load)
+			}
+			var live map[string]string
+			err = runWithOutputFile(cmd, outputFile, outputOnly, true, func() error {
+				resp, err := load(cmd.Context())
 				if err != nil {
 					return err
 				}
-				resp["tenancy"] = details.TenancyName
-				resp["tenancy_id"] = details.TenancyOCID
-				resp["compartment"] = details.CompartmentName
-				resp["compartment_id"] = details.CompartmentOCID
-				resp["user"] = details.UserName
-				resp["user_id"] = details.UserOCID
-				resp["region"] = details.Region
-			}
-			if plain {
-				line := fmt.Sprintf(
-					"context=%s profile=%s auth=%s tenancy=%s compartment=%s user=%s region=%s",
-					resp["context"], resp["profile"], resp["auth_method"], resp["tenancy_id"], resp["compartment_id"], resp["user_id"], resp["region"],
-				)
-				fmt.Fprintln(cmd.OutOrStdout(), line)
-				return nil
-			}
-			switch strings.ToLower(output) {
-			case "":
-				// default human-friendly multiline
-				fmt.Fprintf(cmd.OutOrStdout(), "context: %s\n", resp["context"])
-				if resp["context"] != resp["profile"] {
-					fmt.Fprintf(cmd.OutOrStdout(), "profile: %s\n", resp["profile"])
+				live = resp
+				warnInactiveCompartment(cmd.ErrOrStderr(), config.Context{Name: resp["context"], CompartmentOCID: resp["compartment_id"], CompartmentState: resp["compartment_state"]})
+				if len(fields) > 0 {
+					return printStatusFields(cmd.OutOrStdout(), resp, fields, output, plain)
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "auth: %s\n", resp["auth_method"])
-				printNameAndID := func(label, name, id string) {
-					if name == "" {
-						fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", label, id)
-						return
-					}
-					fmt.Fprintf(cmd.OutOrStdout(), "%s: %s (%s)\n", label, name, id)
+				if plain {
+					line := fmt.Sprintf(
+						"context=%s profile=%s auth=%s tenancy=%s compartment=%s user=%s region=%s",
+						resp["context"], resp["profile"], resp["auth_method"], resp["tenancy_id"], resp["compartment_id"], resp["user_id"], resp["region"],
+					)
+					fmt.Fprintln(cmd.OutOrStdout(), line)
+					return nil
 				}
-				printNameAndID("tenancy", resp["tenancy"], resp["tenancy_id"])
-				printNameAndID("compartment", resp["compartment"], resp["compartment_id"])
-				printNameAndID("user", resp["user"], resp["user_id"])
-				fmt.Fprintf(cmd.OutOrStdout(), "region: %s\n", resp["region"])
-				return nil
-			case "json":
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(resp)
-			case "yaml", "yml":
-				enc := yaml.NewEncoder(cmd.OutOrStdout())
-				defer enc.Close()
-				return enc.Encode(resp)
-			case "plain":
-				profilePart := ""
-				if resp["context"] != resp["profile"] {
-					profilePart = fmt.Sprintf(" profile=%s", resp["profile"])
+				switch strings.ToLower(output) {
+				case "":
+					// default human-friendly multiline
+					printStatusHuman(cmd.OutOrStdout(), resp, nil)
+					return nil
+				case "template":
+					return writeTemplate(cmd.OutOrStdout(), tmpl, resp)
+				case "json":
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					return enc.Encode(resp)
+				case "yaml", "yml":
+					enc := yaml.NewEncoder(cmd.OutOrStdout())
+					defer enc.Close()
+					return enc.Encode(resp)
+				case "plain":
+					profilePart := ""
+					if resp["context"] != resp["profile"] {
+						profilePart = fmt.Sprintf(" profile=%s", resp["profile"])
+					}
+					line := fmt.Sprintf(
+						"context=%s%s auth=%s tenancy=%s compartment=%s user=%s region=%s",
+						resp["context"], profilePart,
+						resp["auth_method"],
+						formatStatusPlainValue(resp["tenancy"], resp["tenancy_id"]),
+						formatStatusPlainValue(resp["compartment"], resp["compartment_id"]),
+						formatStatusPlainValue(resp["user"], resp["user_id"]),
+						resp["region"],
+					)
+					fmt.Fprintln(cmd.OutOrStdout(), line)
+					return nil
+				case "table":
+					marker := ""
+					if resp["context"] == currentContext {
+						marker = "*"
+					}
+					header := []string{" ", "NAME", "PROFILE", "REGION", "COMPARTMENT"}
+					row := []string{marker, resp["context"], resp["profile"], resp["region"], formatStatusPlainValue(resp["compartment"], resp["compartment_id"])}
+					if verbose {
+						header = append(header, "TENANCY", "USER")
+						row = append(row, formatStatusPlainValue(resp["tenancy"], resp["tenancy_id"]), formatStatusPlainValue(resp["user"], resp["user_id"]))
+					}
+					return printTable(cmd.OutOrStdout(), header, [][]string{row})
+				default:
+					return fmt.Errorf("unsupported output format: %s", output)
 				}
-				line := fmt.Sprintf(
-					"context=%s%s auth=%s tenancy=%s compartment=%s user=%s region=%s",
-					resp["context"], profilePart,
-					resp["auth_method"],
-					formatStatusPlainValue(resp["tenancy"], resp["tenancy_id"]),
-					formatStatusPlainValue(resp["compartment"], resp["compartment_id"]),
-					formatStatusPlainValue(resp["user"], resp["user_id"]),
-					resp["region"],
-				)
-				fmt.Fprintln(cmd.OutOrStdout(), line)
-				return nil
-			default:
-				return fmt.Errorf("unsupported output format: %s", output)
+			})
+			if err != nil || !compareLive {
+				return err
 			}
+			return compareStatusLive(cmd, cmd.ErrOrStderr(), cfgPath, contextName, fromEnv, live)
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
-	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|yaml|plain (default: human-readable)")
+	cmd.Flags().BoolVar(&fromEnv, "from-env", false, "Use an ephemeral context from OCI_CONTEXT_* environment variables instead of the config file")
+	cmd.Flags().StringVarP(&contextName, "context", "x", "", "Show this context instead of the current one (does not switch)")
+	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json|yaml|plain|table|template (default: human-readable)")
+	cmd.Flags().StringVar(&templateText, "template", "", "Go text/template for -o template, over the status fields (e.g. '{{.context}} {{.region}}')")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Also write formatted output to this file, creating missing parent directories")
+	cmd.Flags().BoolVar(&outputOnly, "output-only", false, "With --output-file, write only the file and suppress stdout")
 	cmd.Flags().BoolVarP(&plain, "plain", "p", false, "Plain IDs only (OCIDs, no names)")
 	cmd.Flags().BoolVar(&noLookup, "cached", false, "Read config/current context only; do not query OCI identity")
 	cmd.Flags().BoolVar(&noLookup, "no-lookup", false, "Read config/current context only; do not query OCI identity")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the identity name cache and query OCI identity")
+	cmd.Flags().DurationVar(&timeout, "timeout", statusLookupTimeout, "Timeout for OCI identity lookups (overrides options.network_timeout)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Clear the screen and refresh status every --interval until Ctrl+C")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Refresh interval for --watch")
+	cmd.Flags().BoolVar(&showPath, "path", false, "Also show the compartment's full path from the tenancy root (compartment_path in json/yaml)")
+	cmd.Flags().BoolVar(&compareLive, "compare-live", false, "Query OCI (uncached) and the OCI CLI profile, print drift: lines for mismatches, and exit non-zero if any")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Add tenancy and user columns to -o table")
+	cmd.Flags().StringVar(&fieldList, "fields", "", "Comma-separated fields to print, in order ("+strings.Join(statusFieldNames, ",")+")")
 	return cmd
 }
 
+// loadStatus reads contextName (default current) and, unless noLookup is set,
+// resolves friendly names through OCI identity (cached unless noCache is set).
+// A non-nil paths also resolves the compartment's ancestry, recording a
+// failure as compartment_path_error rather than returning it. The config's
+// current context name is returned alongside.
+func loadStatus(parent context.Context, cmd *cobra.Command, cfgPath, contextName string, fromEnv, noLookup, noCache bool, paths *compartmentPaths) (map[string]string, string, error) {
+	cfg, _, err := loadConfigForCommand(cmd, cfgPath, fromEnv)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx, err := targetContext(cfg, contextName)
+	if err != nil {
+		return nil, "", err
+	}
+	resp := map[string]string{
+		"context":        ctx.Name,
+		"profile":        ctx.Profile,
+		"auth_method":    config.NormalizeAuthMethod(ctx.AuthMethod),
+		"tenancy":        "",
+		"tenancy_id":     ctx.TenancyOCID,
+		"compartment":    "",
+		"compartment_id": ctx.CompartmentOCID,
+		"user":           "",
+		"user_id":        ctx.User,
+		"region":         ctx.Region,
+	}
+	if ctx.CompartmentState != "" {
+		resp["compartment_state"] = ctx.CompartmentState
+	}
+	if noLookup {
+		return resp, cfg.CurrentContext, nil
+	}
+	timeout, err := networkTimeout(cmd, cfg, statusLookupTimeout)
+	if err != nil {
+		return nil, "", err
+	}
+	ctxTimeout, cancel := context.WithTimeout(ociContext(parent, cfg), timeout)
+	defer cancel()
+	ociCfgPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	details, err := identityFetcher(cfg, noCache)(ctxTimeout, ociCfgPath, ctx.Profile, ctx.Region, ctx.TenancyOCID, ctx.CompartmentOCID, ctx.User)
+	if err != nil {
+		return nil, "", withOCIHint(err)
+	}
+	resp["tenancy"] = details.TenancyName
+	resp["tenancy_id"] = details.TenancyOCID
+	resp["compartment"] = details.CompartmentName
+	resp["compartment_id"] = details.CompartmentOCID
+	resp["user"] = details.UserName
+	resp["user_id"] = details.UserOCID
+	resp["region"] = details.Region
+	if paths != nil {
+		// The path is extra detail; a failed lookup is shown in its place
+		// instead of hiding the status that did resolve.
+		if path, err := paths.resolve(ctxTimeout, ociCfgPath, ctx, ctx.CompartmentOCID); err != nil {
+			resp["compartment_path_error"] = withOCIHint(err).Error()
+		} else {
+			resp["compartment_path"] = path
+		}
+	}
+	return resp, cfg.CurrentContext, nil
+}
+
+// compartmentPaths resolves compartment ancestry for status --path, caching
+// GetCompartment results for the life of one invocation (including --watch).
+type compartmentPaths struct {
+	byID map[string]oci.Compartment
+}
+
+func newCompartmentPaths() *compartmentPaths {
+	return &compartmentPaths{byID: map[string]oci.Compartment{}}
+}
+
+// resolve walks from compartmentID up to the tenancy root and returns the
+// path as "root / Eng / Platform".
+func (p *compartmentPaths) resolve(parent context.Context, ociCfgPath string, c config.Context, compartmentID string) (string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for id := compartmentID; id != "" && id != c.TenancyOCID; {
+		if seen[id] {
+			return "", fmt.Errorf("compartment path: cycle at %s", id)
+		}
+		seen[id] = true
+		comp, ok := p.byID[id]
+		if !ok {
+			var err error
+			comp, err = getCompartment(parent, ociCfgPath, c.Profile, c.Region, id)
+			if err != nil {
+				return "", err
+			}
+			p.byID[id] = comp
+		}
+		names = append([]string{comp.Name}, names...)
+		id = comp.Parent
+	}
+	return strings.Join(append([]string{"root"}, names...), " / "), nil
+}
+
+// statusFieldNames lists the --fields values in default display order.
+var statusFieldNames = []string{"context", "profile", "auth", "tenancy", "compartment", "user", "region"}
+
+// statusFieldKeys maps a --fields value to its status keys: a display name
+// and, for identity resources, the OCID.
+var statusFieldKeys = map[string][2]string{
+	"context":     {"context"},
+	"profile":     {"profile"},
+	"auth":        {"auth_method"},
+	"tenancy":     {"tenancy", "tenancy_id"},
+	"compartment": {"compartment", "compartment_id"},
+	"user":        {"user", "user_id"},
+	"region":      {"region"},
+}
+
+func parseStatusFields(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := statusFieldKeys[f]; !ok {
+			return nil, fmt.Errorf("unknown --fields value %q (valid: %s)", f, strings.Join(statusFieldNames, ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// printStatusFields renders only the requested fields, in order. ids selects
+// the OCID-only values used by --plain.
+func printStatusFields(w io.Writer, resp map[string]string, fields []string, output string, ids bool) error {
+	switch strings.ToLower(output) {
+	case "":
+		if ids {
+			return printStatusFields(w, resp, fields, "plain", true)
+		}
+		printStatusHuman(w, resp, fields)
+		return nil
+	case "plain":
+		parts := make([]string, 0, len(fields))
+		for _, f := range fields {
+			keys := statusFieldKeys[f]
+			v := resp[keys[0]]
+			if keys[1] != "" {
+				if ids {
+					v = resp[keys[1]]
+				} else {
+					v = formatStatusPlainValue(resp[keys[0]], resp[keys[1]])
+				}
+			}
+			parts = append(parts, f+"="+v)
+		}
+		fmt.Fprintln(w, strings.Join(parts, " "))
+		return nil
+	case "table":
+		header := make([]string, 0, len(fields))
+		row := make([]string, 0, len(fields))
+		for _, f := range fields {
+			keys := statusFieldKeys[f]
+			v := resp[keys[0]]
+			if keys[1] != "" {
+				v = formatStatusPlainValue(resp[keys[0]], resp[keys[1]])
+			}
+			header = append(header, strings.ToUpper(f))
+			row = append(row, v)
+		}
+		return printTable(w, header, [][]string{row})
+	case "json", "yaml", "yml":
+		subset := make(orderedStatusFields, 0, len(fields)*2)
+		for _, f := range fields {
+			for _, k := range statusFieldKeys[f] {
+				if k != "" {
+					subset = append(subset, [2]string{k, resp[k]})
+				}
+			}
+		}
+		if strings.ToLower(output) == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(subset)
+		}
+		enc := yaml.NewEncoder(w)
+		defer enc.Close()
+		return enc.Encode(subset)
+	default:
+		return fmt.Errorf("unsupported output format: %s", output)
+	}
+}
+
+// orderedStatusFields is a key/value list that encodes as a JSON or YAML
+// object with its keys in list order, so --fields order survives.
+type orderedStatusFields [][2]string
+
+func (o orderedStatusFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, kv := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		for j, s := range kv {
+			b, err := json.Marshal(s)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(b)
+			if j == 0 {
+				buf.WriteByte(':')
+			}
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o orderedStatusFields) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	for _, kv := range o {
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kv[0]},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kv[1]})
+	}
+	return n, nil
+}
+
+// printStatusHuman prints the multiline status. With fields set, only those
+// lines are printed, in that order.
+func printStatusHuman(w io.Writer, resp map[string]string, fields []string) {
+	printNameAndID := func(label, name, id string) {
+		if name == "" {
+			fmt.Fprintf(w, "%s: %s\n", label, id)
+			return
+		}
+		fmt.Fprintf(w, "%s: %s (%s)\n", label, name, id)
+	}
+	if len(fields) == 0 {
+		fields = statusFieldNames
+		if resp["context"] == resp["profile"] {
+			fields = append([]string{"context"}, statusFieldNames[2:]...)
+		}
+	}
+	for _, f := range fields {
+		keys := statusFieldKeys[f]
+		if keys[1] != "" {
+			printNameAndID(f, resp[keys[0]], resp[keys[1]])
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", f, resp[keys[0]])
+	}
+	if path := resp["compartment_path"]; path != "" {
+		fmt.Fprintf(w, "path: %s\n", path)
+	} else if msg := resp["compartment_path_error"]; msg != "" {
+		fmt.Fprintf(w, "path: lookup failed: %s\n", msg)
+	}
+}
+
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus redraws human status every interval until cmd.Context() is
+// cancelled or Ctrl+C is pressed. Fetch errors are shown inline so a transient
+// failure does not end the watch.
+func watchStatus(cmd *cobra.Command, interval time.Duration, fields []string, load func(context.Context) (map[string]string, error)) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	out := cmd.OutOrStdout()
+	for {
+		resp, err := load(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Fprint(out, clearScreen)
+		fmt.Fprintf(out, "Every %s: oci-context status  %s  (Ctrl+C to stop)\n\n", interval, time.Now().Format("15:04:05"))
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		} else {
+			printStatusHuman(out, resp, fields)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 func formatStatusPlainValue(name, id string) string {
 	if name == "" {
 		return id
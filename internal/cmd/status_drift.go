@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+	"github.com/spf13/cobra"
+)
+
+// compareStatusLive reloads contextName (default current) and checks it
+// against its OCI CLI profile and the live status in resp. Each mismatch is
+// written to w as a "drift:" line; an error reports how many there were.
+func compareStatusLive(cmd *cobra.Command, w io.Writer, cfgPath, contextName string, fromEnv bool, resp map[string]string) error {
+	cfg, _, err := loadConfigForCommand(cmd, cfgPath, fromEnv)
+	if err != nil {
+		return err
+	}
+	ctx, err := targetContext(cfg, contextName)
+	if err != nil {
+		return err
+	}
+	ociCfgPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return err
+	}
+	drift := statusDrift(cfg, ctx, ociCfgPath, resp)
+	for _, d := range drift {
+		fmt.Fprintf(w, "drift: %s\n", d)
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("context %s has drifted from OCI (%d mismatches)", ctx.Name, len(drift))
+	}
+	return nil
+}
+
+// statusDrift lists differences between the saved ctx and what OCI reports:
+// the profile's tenancy and region in the OCI CLI config at ociCfgPath, and
+// the live compartment name in resp against the name saved with a favorite.
+// Contexts store only the compartment OCID, so an unpinned compartment has no
+// saved name to compare. Other profiles in the file are not validated.
+func statusDrift(cfg config.Config, ctx config.Context, ociCfgPath string, resp map[string]string) []string {
+	var drift []string
+	p, ok, err := ocicfg.LoadProfile(ociCfgPath, ctx.Profile)
+	if err != nil {
+		drift = append(drift, fmt.Sprintf("cannot read profile %s from %s: %v", ctx.Profile, ociCfgPath, err))
+	} else if !ok {
+		drift = append(drift, fmt.Sprintf("profile %s is not in %s", ctx.Profile, ociCfgPath))
+	} else {
+		if p.Region != ctx.Region {
+			drift = append(drift, fmt.Sprintf("region %s is saved but profile %s uses %s", ctx.Region, ctx.Profile, p.Region))
+		}
+		if p.Tenancy != ctx.TenancyOCID {
+			drift = append(drift, fmt.Sprintf("tenancy %s is saved but profile %s uses %s", ctx.TenancyOCID, ctx.Profile, p.Tenancy))
+		}
+	}
+	live := resp["compartment"]
+	for _, f := range cfg.Options.FavoriteCompartmentsFor(ctx.TenancyOCID) {
+		if f.ID == ctx.CompartmentOCID && live != "" && f.Name != "" && f.Name != live {
+			drift = append(drift, fmt.Sprintf("compartment %s is saved as %s but is named %s in OCI", ctx.CompartmentOCID, f.Name, live))
+		}
+	}
+	return drift
+}
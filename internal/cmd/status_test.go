@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/adrianmross/oci-context/pkg/config"
 	"github.com/adrianmross/oci-context/pkg/oci"
@@ -148,6 +150,68 @@ func TestStatusOutputs(t *testing.T) {
 				"",
 			}, "\n"),
 		},
+		{
+			name:      "table output",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-o", "table"},
+			want: strings.Join([]string{
+				"   NAME  PROFILE  REGION        COMPARTMENT",
+				"*  dev   DEFAULT  us-phoenix-1  Compartment Friendly (" + abbrevOCID("ocid1.compartment.oc1..bbbb") + ")",
+				"",
+			}, "\n"),
+		},
+		{
+			name:      "verbose table output",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-o", "table", "-v"},
+			want: strings.Join([]string{
+				"   NAME  PROFILE  REGION        COMPARTMENT                           TENANCY                           USER",
+				"*  dev   DEFAULT  us-phoenix-1  Compartment Friendly (" + abbrevOCID("ocid1.compartment.oc1..bbbb") + ")  Tenancy Friendly (" + abbrevOCID("ocid1.tenancy.oc1..aaaa") + ")  User Friendly (" + abbrevOCID("ocid1.user.oc1..cccc") + ")",
+				"",
+			}, "\n"),
+		},
+		{
+			name:      "plain rejects table output",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-p", "-o", "table"},
+			wantErr:   "--plain cannot be combined with -o table",
+		},
+		{
+			name:      "fields table",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-o", "table", "--fields", "context,region"},
+			want:      "CONTEXT  REGION\ndev      us-phoenix-1\n",
+		},
+		{
+			name:      "fields human in requested order",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "--fields", "region,compartment"},
+			want:      "region: us-phoenix-1\ncompartment: Compartment Friendly (ocid1.compartment.oc1..bbbb)\n",
+		},
+		{
+			name:      "fields plain OCIDs",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-p", "--fields", "compartment,context"},
+			want:      "compartment=ocid1.compartment.oc1..bbbb context=dev\n",
+		},
+		{
+			name:      "fields json subset",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-o", "json", "--fields", "tenancy,auth"},
+			want:      "{\n  \"tenancy\": \"Tenancy Friendly\",\n  \"tenancy_id\": \"ocid1.tenancy.oc1..aaaa\",\n  \"auth_method\": \"api_key\"\n}\n",
+		},
+		{
+			name:      "fields yaml in requested order",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "-o", "yaml", "--fields", "region,context,user"},
+			want:      "region: us-phoenix-1\ncontext: dev\nuser: User Friendly\nuser_id: ocid1.user.oc1..cccc\n",
+		},
+		{
+			name:      "fields unknown",
+			mutateCfg: func(c config.Config) config.Config { return c },
+			args:      []string{"status", "--fields", "region,realm"},
+			wantErr:   "unknown --fields value \"realm\" (valid: context, profile, auth, tenancy, compartment, user, region)",
+		},
 		{
 			name:      "unsupported output format",
 			mutateCfg: func(c config.Config) config.Config { return c },
@@ -251,6 +315,11 @@ func TestStatusCachedOutputsWithoutIdentityLookup(t *testing.T) {
 			args: []string{"status", "--no-lookup", "-o", "plain"},
 			want: "context=dev profile=DEFAULT auth=security_token tenancy=ocid1.tenancy.oc1..aaaa compartment=ocid1.compartment.oc1..bbbb user=ocid1.user.oc1..cccc region=us-phoenix-1\n",
 		},
+		{
+			name: "template cached",
+			args: []string{"status", "--cached", "-o", "template", "--template", "{{.context}} {{.region}} {{.missing}}|"},
+			want: "dev us-phoenix-1 |\n",
+		},
 		{
 			name: "plain ids cached",
 			args: []string{"status", "--no-lookup", "-p"},
@@ -307,3 +376,491 @@ func TestStatusIdentityError(t *testing.T) {
 		t.Fatalf("expected identity error, got %v", err)
 	}
 }
+
+func TestStatusIdentityErrorHint(t *testing.T) {
+	apiErr := &oci.APIError{Op: "get tenancy", Kind: oci.ErrAuth, Err: errors.New("401 NotAuthenticated")}
+	restore := stubIdentityError(apiErr)
+	defer restore()
+
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	cfgPath := t.TempDir() + "/config.yml"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath})
+	err := cmd.Execute()
+	if !errors.Is(err, oci.ErrAuth) {
+		t.Fatalf("expected errors.Is(err, oci.ErrAuth), got %v", err)
+	}
+	if !strings.Contains(err.Error(), "check your credentials") {
+		t.Fatalf("expected credentials hint, got %v", err)
+	}
+}
+
+func setOCIContextEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.EnvContextName, "ci")
+	t.Setenv(config.EnvContextProfile, "CI")
+	t.Setenv(config.EnvContextTenancy, "ocid1.tenancy.oc1..envten")
+	t.Setenv(config.EnvContextCompartment, "ocid1.compartment.oc1..envcomp")
+	t.Setenv(config.EnvContextRegion, "us-ashburn-1")
+	t.Setenv(config.EnvContextOCIConfig, "/tmp/oci-env")
+}
+
+func TestStatusFromEnvUsesEnvContext(t *testing.T) {
+	restore := stubIdentityUnexpected(t)
+	defer restore()
+	setOCIContextEnv(t)
+
+	cmd := newStatusCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--from-env", "--cached", "-o", "plain", "--config", "/nonexistent/config.yml"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := "context=ci profile=CI auth=api_key tenancy=ocid1.tenancy.oc1..envten compartment=ocid1.compartment.oc1..envcomp user= region=us-ashburn-1\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestExportFromEnvUsesEnvContext(t *testing.T) {
+	setOCIContextEnv(t)
+
+	cmd := newExportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--from-env"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"export OCI_CLI_PROFILE=CI",
+		"export OCI_CLI_CONFIG_FILE=/tmp/oci-env",
+		"export OCI_TENANCY_OCID=ocid1.tenancy.oc1..envten",
+		"export OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..envcomp",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in export output:\n%s", want, got)
+		}
+	}
+}
+
+func TestStatusContextFlagWithoutCurrentContext(t *testing.T) {
+	restore := stubIdentityUnexpected(t)
+	defer restore()
+	cfgPath := t.TempDir() + "/config.yml"
+	cfg := config.Config{
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..dev", Region: "us-phoenix-1"},
+			{Name: "prod", Profile: "PROD", TenancyOCID: "ocid1.tenancy.oc1..prod", Region: "us-ashburn-1"},
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--config", cfgPath, "--context", "prod", "--cached", "--fields", "context,region", "-o", "plain"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got, want := buf.String(), "context=prod region=us-ashburn-1\n"; got != want {
+		t.Fatalf("output mismatch\nwant: %q\ngot:  %q", want, got)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.CurrentContext != "" {
+		t.Fatalf("status --context switched current context to %q", loaded.CurrentContext)
+	}
+
+	table := func(name string) string {
+		cmd := newStatusCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--config", cfgPath, "--context", name, "--cached", "-o", "table"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute table for %s: %v", name, err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		return lines[len(lines)-1]
+	}
+	if row := table("prod"); strings.HasPrefix(row, "*") {
+		t.Fatalf("status --context prod marked a non-current context as current: %q", row)
+	}
+	cfg.CurrentContext = "prod"
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if row := table("prod"); !strings.HasPrefix(row, "*") {
+		t.Fatalf("expected the current context to be marked: %q", row)
+	}
+}
+
+func TestStatusSurfacesInactiveCompartmentState(t *testing.T) {
+	restore := stubIdentityUnexpected(t)
+	defer restore()
+	cfgPath := t.TempDir() + "/config.yml"
+	cfg := config.Config{
+		Contexts: []config.Context{{
+			Name:             "dev",
+			Profile:          "DEV",
+			TenancyOCID:      "ocid1.tenancy.oc1..dev",
+			CompartmentOCID:  "ocid1.compartment.oc1..gone",
+			CompartmentState: "DELETED",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"--config", cfgPath, "--cached", "-o", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(out.String(), `"compartment_state": "DELETED"`) {
+		t.Fatalf("expected compartment_state in json:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "warn: context dev: compartment ocid1.compartment.oc1..gone was DELETED") {
+		t.Fatalf("expected inactive compartment warning, got %q", errOut.String())
+	}
+}
+
+func TestStatusPathWalksCompartmentAncestry(t *testing.T) {
+	restore := stubIdentity()
+	defer restore()
+	tree := map[string]oci.Compartment{
+		"ocid1.compartment.oc1..team":     {ID: "ocid1.compartment.oc1..team", Name: "Team-A", Parent: "ocid1.compartment.oc1..platform"},
+		"ocid1.compartment.oc1..platform": {ID: "ocid1.compartment.oc1..platform", Name: "Platform", Parent: "ocid1.compartment.oc1..eng"},
+		"ocid1.compartment.oc1..eng":      {ID: "ocid1.compartment.oc1..eng", Name: "Eng", Parent: "ocid1.tenancy.oc1..ten"},
+	}
+	calls := 0
+	origComp := getCompartment
+	getCompartment = func(_ context.Context, _path, _profile, _region, compartmentID string) (oci.Compartment, error) {
+		calls++
+		return tree[compartmentID], nil
+	}
+	defer func() { getCompartment = origComp }()
+	cfgPath := t.TempDir() + "/config.yml"
+	dev := config.Context{Name: "dev", Profile: "DEV", TenancyOCID: "ocid1.tenancy.oc1..ten", CompartmentOCID: "ocid1.compartment.oc1..team"}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{dev}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath, "--path", "-o", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(out.String(), `"compartment_path": "root / Eng / Platform / Team-A"`) {
+		t.Fatalf("expected compartment_path in json:\n%s", out.String())
+	}
+
+	paths := newCompartmentPaths()
+	calls = 0
+	for range 2 {
+		got, err := paths.resolve(context.Background(), "/tmp/oci", dev, dev.CompartmentOCID)
+		if err != nil || got != "root / Eng / Platform / Team-A" {
+			t.Fatalf("resolve = %q, %v", got, err)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("expected each ancestor fetched once, got %d calls", calls)
+	}
+	if got, _ := paths.resolve(context.Background(), "/tmp/oci", dev, dev.TenancyOCID); got != "root" {
+		t.Fatalf("tenancy root path = %q", got)
+	}
+
+	// A failed ancestor lookup is shown on the path row; the rest still prints.
+	getCompartment = func(context.Context, string, string, string, string) (oci.Compartment, error) {
+		return oci.Compartment{}, errors.New("not authorized")
+	}
+	cmd = newStatusCmd()
+	out.Reset()
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", cfgPath, "--path"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected status to succeed without the path, got %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "context: dev\n") || !strings.HasSuffix(got, "path: lookup failed: not authorized\n") {
+		t.Fatalf("expected the lookup error on the path row:\n%s", got)
+	}
+
+	cmd = newStatusCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs([]string{"--config", cfgPath, "--path", "--cached"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected --path with --cached to fail")
+	}
+}
+
+func TestStatusFromEnvRequiresTenancy(t *testing.T) {
+	t.Setenv(config.EnvContextProfile, "CI")
+	t.Setenv(config.EnvContextTenancy, "")
+
+	cmd := newStatusCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--from-env", "--cached"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--from-env: context tenancy_ocid is required") {
+		t.Fatalf("expected env validation error, got %v", err)
+	}
+}
+
+func TestStatusWatchShowsErrorsInlineAndStopsOnCancel(t *testing.T) {
+	setOCIContextEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	original := fetchIdentity
+	defer func() { fetchIdentity = original }()
+	fetchIdentity = func(_ context.Context, _path, _profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		calls++
+		switch calls {
+		case 1:
+			return oci.IdentityDetails{}, errors.New("token expired")
+		case 2:
+			return oci.IdentityDetails{TenancyName: "Rotated", TenancyOCID: tenancyOCID, CompartmentOCID: compartmentOCID, Region: region}, nil
+		default:
+			cancel()
+			return oci.IdentityDetails{}, context.Canceled
+		}
+	}
+
+	cmd := newStatusCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--from-env", "--watch", "--interval", "5ms"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("watch should exit cleanly on cancel, got %v", err)
+	}
+	got := buf.String()
+	if strings.Count(got, clearScreen) != 2 {
+		t.Fatalf("expected two redraws, got %q", got)
+	}
+	if !strings.Contains(got, "error: token expired") || !strings.Contains(got, "tenancy: Rotated (ocid1.tenancy.oc1..envten)") {
+		t.Fatalf("expected inline error then refreshed status, got %q", got)
+	}
+}
+
+func TestStatusWatchRejectsStructuredOutput(t *testing.T) {
+	restore := stubIdentityUnexpected(t)
+	defer restore()
+	setOCIContextEnv(t)
+
+	cmd := newStatusCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--from-env", "--watch", "-o", "json"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--watch only supports") {
+		t.Fatalf("expected watch/output error, got %v", err)
+	}
+}
+
+func TestStatusUsesIdentityCacheUnlessNoCache(t *testing.T) {
+	t.Setenv(oci.EnvIdentityCacheTTL, "10m")
+	dir := t.TempDir()
+	cfgPath := dir + "/config.yml"
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci", SocketPath: dir + "/daemon.sock"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	calls := 0
+	original := fetchIdentity
+	defer func() { fetchIdentity = original }()
+	fetchIdentity = func(_ context.Context, _path, _profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		calls++
+		return oci.IdentityDetails{CompartmentName: "Team-A", CompartmentOCID: compartmentOCID, TenancyOCID: tenancyOCID, Region: region}, nil
+	}
+	run := func(args ...string) string {
+		cmd := newStatusCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs(append(args, "--config", cfgPath, "--fields", "compartment"))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		return buf.String()
+	}
+	for i := 0; i < 2; i++ {
+		if out := run(); out != "compartment: Team-A (ocid1.compartment.oc1..bbbb)\n" {
+			t.Fatalf("unexpected output %q", out)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one identity lookup with cache, got %d", calls)
+	}
+	run("--no-cache")
+	if calls != 2 {
+		t.Fatalf("expected --no-cache to query identity, got %d calls", calls)
+	}
+}
+
+func TestStatusTimeoutPrecedence(t *testing.T) {
+	var got time.Duration
+	original := fetchIdentity
+	defer func() { fetchIdentity = original }()
+	fetchIdentity = func(ctx context.Context, _path, _profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		deadline, _ := ctx.Deadline()
+		got = time.Until(deadline)
+		return oci.IdentityDetails{TenancyOCID: tenancyOCID, CompartmentOCID: compartmentOCID, Region: region}, nil
+	}
+	run := func(networkTimeout string, args ...string) error {
+		cfg := config.Config{
+			Options: config.Options{OCIConfigPath: "/tmp/oci", NetworkTimeout: networkTimeout},
+			Contexts: []config.Context{{
+				Name:            "dev",
+				Profile:         "DEFAULT",
+				TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+				CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+				Region:          "us-phoenix-1",
+			}},
+			CurrentContext: "dev",
+		}
+		cfgPath := t.TempDir() + "/config.yml"
+		if err := config.Save(cfgPath, cfg); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cmd := newStatusCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		return cmd.Execute()
+	}
+	within := func(want time.Duration) bool { return got > want-time.Second && got <= want }
+
+	if err := run(""); err != nil || !within(statusLookupTimeout) {
+		t.Fatalf("default: got %s (%v)", got, err)
+	}
+	if err := run("3s"); err != nil || !within(3*time.Second) {
+		t.Fatalf("config: got %s (%v)", got, err)
+	}
+	if err := run("3s", "--timeout", "1m"); err != nil || !within(time.Minute) {
+		t.Fatalf("flag over config: got %s (%v)", got, err)
+	}
+	if err := run("", "--timeout", "0s"); err == nil || !strings.Contains(err.Error(), "--timeout must be positive") {
+		t.Fatalf("expected positive timeout error, got %v", err)
+	}
+}
+
+func TestStatusCompareLiveReportsDrift(t *testing.T) {
+	restore := stubIdentity()
+	defer restore()
+	t.Setenv(oci.EnvIdentityCacheTTL, "10m")
+	dir := t.TempDir()
+	ociPath := dir + "/oci-config"
+	// BROKEN lacks a tenancy; only the context's own profile is checked.
+	if err := os.WriteFile(ociPath, []byte("[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n[BROKEN]\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := dir + "/config.yml"
+	ctx := config.Context{
+		Name:            "dev",
+		Profile:         "DEV",
+		TenancyOCID:     "ocid1.tenancy.oc1..dev",
+		CompartmentOCID: "ocid1.compartment.oc1..app",
+		Region:          "us-phoenix-1",
+	}
+	save := func(region, favorite string) {
+		t.Helper()
+		c := ctx
+		c.Region = region
+		cfg := config.Config{
+			Options:        config.Options{OCIConfigPath: ociPath, SocketPath: dir + "/daemon.sock"},
+			Contexts:       []config.Context{c},
+			CurrentContext: "dev",
+		}
+		if favorite != "" {
+			cfg.Options.FavoriteCompartments = []config.FavoriteCompartment{{Tenancy: ctx.TenancyOCID, ID: ctx.CompartmentOCID, Name: favorite}}
+		}
+		if err := config.Save(cfgPath, cfg); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+	}
+	run := func() (string, error) {
+		cmd := newStatusCmd()
+		errOut := &bytes.Buffer{}
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(errOut)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"--config", cfgPath, "--compare-live", "-o", "json"})
+		err := cmd.Execute()
+		return errOut.String(), err
+	}
+
+	save("us-phoenix-1", "Compartment Friendly")
+	if errOut, err := run(); err != nil || strings.Contains(errOut, "drift:") {
+		t.Fatalf("expected no drift, got %q (%v)", errOut, err)
+	}
+
+	save("us-ashburn-1", "old-name")
+	errOut, err := run()
+	if err == nil || err.Error() != "context dev has drifted from OCI (2 mismatches)" {
+		t.Fatalf("expected a drift error, got %v", err)
+	}
+	for _, want := range []string{
+		"drift: region us-ashburn-1 is saved but profile DEV uses us-phoenix-1\n",
+		"drift: compartment ocid1.compartment.oc1..app is saved as old-name but is named Compartment Friendly in OCI\n",
+	} {
+		if !strings.Contains(errOut, want) {
+			t.Fatalf("expected %q in %q", want, errOut)
+		}
+	}
+	// --compare-live always asks OCI, so nothing is cached.
+	path, _ := identityCachePath(config.Config{Options: config.Options{SocketPath: dir + "/daemon.sock"}})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected --compare-live to bypass the identity cache")
+	}
+}
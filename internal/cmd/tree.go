@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocidutil"
+	"github.com/spf13/cobra"
+)
+
+// compartmentNode is one compartment in `tree` output; the root has no name.
+type compartmentNode struct {
+	Name     string             `json:"name,omitempty"`
+	ID       string             `json:"id"`
+	State    string             `json:"state,omitempty"`
+	Children []*compartmentNode `json:"children,omitempty"`
+}
+
+func newTreeCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var contextName string
+	var rootID string
+	var depth int
+	var showOCIDs bool
+	var output string
+	var activeOnly, all bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:         "tree [--context <name>] [--root <ocid>] [--depth N]",
+		Short:       "Print the compartment hierarchy under a context's tenancy",
+		Annotations: mergeAnnotations,
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			format := strings.ToLower(output)
+			switch format {
+			case "", "json":
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+			if depth < 0 {
+				return fmt.Errorf("--depth must be 0 (the default limit) or more")
+			}
+			cfg, _, err := loadReadConfig(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			ctx, err := targetContext(cfg, contextName)
+			if err != nil {
+				return err
+			}
+			root := ctx.TenancyOCID
+			if rootID = strings.TrimSpace(rootID); rootID != "" {
+				if err := ocidutil.Validate("--root", rootID, ocidutil.TypeCompartment, ocidutil.TypeTenancy); err != nil {
+					return err
+				}
+				root = rootID
+			}
+			d, err := networkTimeout(cmd, cfg, compartmentLookupTimeout)
+			if err != nil {
+				return err
+			}
+			ociCfgPath, err := resolveOCIConfigPath(cfg)
+			if err != nil {
+				return err
+			}
+			c, cancel := context.WithTimeout(ociContext(cmd.Context(), cfg), d)
+			defer cancel()
+			tree := &compartmentNode{ID: root}
+			if err := walkCompartmentTree(c, ociCfgPath, ctx, tree, depth, cfg.Options.IdentityConcurrencyLimit(), activeOnly && !all); err != nil {
+				return fmt.Errorf("list compartments: %w", withOCIHint(err))
+			}
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(tree)
+			}
+			printCompartmentTree(cmd.OutOrStdout(), tree, showOCIDs)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVarP(&contextName, "context", "x", "", "Context whose tenancy is walked (default current)")
+	cmd.Flags().StringVar(&rootID, "root", "", "Start at this compartment OCID instead of the tenancy root")
+	cmd.Flags().IntVar(&depth, "depth", 0, fmt.Sprintf("Levels below the root to show (0 = up to %d)", oci.DefaultWalkDepth))
+	cmd.Flags().IntVar(&depth, "max-depth", 0, "Same as --depth")
+	cmd.MarkFlagsMutuallyExclusive("depth", "max-depth")
+	cmd.Flags().BoolVar(&showOCIDs, "ocids", false, "Show each compartment's OCID after its name")
+	cmd.Flags().StringVarP(&output, "out", "o", "", "Output format: json (default: indented tree)")
+	addActiveOnlyFlags(cmd, &activeOnly, &all)
+	cmd.Flags().DurationVar(&timeout, "timeout", compartmentLookupTimeout, "Overall timeout for the walk (overrides options.network_timeout)")
+	return cmd
+}
+
+// walkCompartmentTree fills root's descendants down to maxDepth levels, with
+// at most workers list calls in flight, sorting children by name.
+func walkCompartmentTree(ctx context.Context, ociCfgPath string, c config.Context, root *compartmentNode, maxDepth, workers int, activeOnly bool) error {
+	list := func(ctx context.Context, parentID string) ([]oci.Compartment, error) {
+		return fetchCompartments(ctx, ociCfgPath, c.Profile, c.Region, parentID)
+	}
+	children, err := oci.WalkCompartments(ctx, root.ID, list, oci.WalkOptions{MaxDepth: maxDepth, Workers: workers, ActiveOnly: activeOnly})
+	if err != nil {
+		return err
+	}
+	var build func(n *compartmentNode)
+	build = func(n *compartmentNode) {
+		for _, child := range children[n.ID] {
+			node := &compartmentNode{Name: child.Name, ID: child.ID, State: child.Status}
+			build(node)
+			n.Children = append(n.Children, node)
+		}
+		sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	}
+	build(root)
+	return nil
+}
+
+// printCompartmentTree writes root's OCID, then its descendants with box
+// drawing indentation. Non-ACTIVE compartments are marked with their state.
+func printCompartmentTree(w io.Writer, root *compartmentNode, showOCIDs bool) {
+	fmt.Fprintln(w, root.ID)
+	var walk func(nodes []*compartmentNode, prefix string)
+	walk = func(nodes []*compartmentNode, prefix string) {
+		for i, n := range nodes {
+			branch, next := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, next = "└── ", "    "
+			}
+			line := n.Name
+			if n.State != "" && n.State != "ACTIVE" {
+				line += " [" + n.State + "]"
+			}
+			if showOCIDs {
+				line += " (" + n.ID + ")"
+			}
+			fmt.Fprintln(w, prefix+branch+line)
+			walk(n.Children, prefix+next)
+		}
+	}
+	walk(root.Children, "")
+}
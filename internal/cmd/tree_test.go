@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestTreePrintsHierarchyWithBoundedDepthAndConcurrency(t *testing.T) {
+	tenancy := "ocid1.tenancy.oc1..ten"
+	tree := map[string][]oci.Compartment{
+		tenancy: {
+			{ID: "ocid1.compartment.oc1..b", Name: "Team-B", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..a", Name: "Team-A", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..old", Name: "Old", Status: "DELETED"},
+		},
+		"ocid1.compartment.oc1..a":  {{ID: "ocid1.compartment.oc1..pa", Name: "Platform", Status: "ACTIVE"}},
+		"ocid1.compartment.oc1..b":  {{ID: "ocid1.compartment.oc1..pb", Name: "Apps", Status: "ACTIVE"}},
+		"ocid1.compartment.oc1..pa": {{ID: "ocid1.compartment.oc1..deep", Name: "Deep", Status: "ACTIVE"}},
+	}
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	orig := fetchCompartments
+	t.Cleanup(func() { fetchCompartments = orig })
+	fetchCompartments = func(_ context.Context, _, _, _, parentID string) ([]oci.Compartment, error) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return tree[parentID], nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options:        config.Options{OCIConfigPath: "/tmp/oci", IdentityConcurrency: 1},
+		Contexts:       []config.Context{{Name: "dev", Profile: "DEV", TenancyOCID: tenancy, CompartmentOCID: tenancy, Region: "us-phoenix-1"}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newTreeCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("tree %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	want := strings.Join([]string{
+		tenancy,
+		"├── Team-A",
+		"│   └── Platform",
+		"│       └── Deep",
+		"└── Team-B",
+		"    └── Apps",
+		"",
+	}, "\n")
+	if got := run(); got != want {
+		t.Fatalf("unexpected tree\nwant:\n%s\ngot:\n%s", want, got)
+	}
+	if peak != 1 {
+		t.Fatalf("expected options.identity_concurrency to cap list calls at 1, saw %d", peak)
+	}
+
+	want = strings.Join([]string{
+		tenancy,
+		"├── Old [DELETED] (ocid1.compartment.oc1..old)",
+		"├── Team-A (ocid1.compartment.oc1..a)",
+		"└── Team-B (ocid1.compartment.oc1..b)",
+		"",
+	}, "\n")
+	if got := run("--depth", "1", "--all", "--ocids"); got != want {
+		t.Fatalf("unexpected depth-1 tree\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	var root compartmentNode
+	if err := json.Unmarshal([]byte(run("-o", "json", "--root", "ocid1.compartment.oc1..a")), &root); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if root.ID != "ocid1.compartment.oc1..a" || len(root.Children) != 1 || root.Children[0].Name != "Platform" || root.Children[0].Children[0].Name != "Deep" {
+		t.Fatalf("unexpected json tree: %+v", root)
+	}
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,7 +17,9 @@ import (
 	"github.com/adrianmross/oci-context/pkg/config"
 	"github.com/adrianmross/oci-context/pkg/oci"
 	"github.com/adrianmross/oci-context/pkg/ocicfg"
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -117,59 +121,82 @@ var (
 	tenancyNames         = make(map[string]string)
 	tenancyNamesMu       sync.RWMutex
 	fetchIdentityDetails = oci.FetchIdentityDetails
+	// fetchCompartmentSubtree is a seam so subtree search can be tested offline.
+	fetchCompartmentSubtree = oci.FetchCompartmentSubtree
+	// probeRegionLatency is a seam so latency sorting can be tested offline.
+	probeRegionLatency = oci.ProbeRegionLatency
+	// countChildCompartments is a seam so subcompartment counts can be tested offline.
+	countChildCompartments = oci.CountChildCompartments
+	// copyToClipboard is a seam so the copy key can be tested without a
+	// system clipboard.
+	copyToClipboard = func(text string) error {
+		if clipboard.Unsupported {
+			return fmt.Errorf("no clipboard utility found (install xclip, xsel, or wl-clipboard)")
+		}
+		return clipboard.WriteAll(text)
+	}
 )
 
-// primeTenancyNames fetches friendly tenancy names for the given profiles and caches them.
-// It runs best-effort: errors are ignored and missing names fall back to profile/OCID display.
-func primeTenancyNames(ctx context.Context, profiles map[string]ocicfg.Profile, ociCfgPath string) {
+// tuiNetworkTimeout is the default per-call timeout for TUI OCI requests.
+const tuiNetworkTimeout = 15 * time.Second
+
+// childCountLimit caps the one-page subcompartment count behind each row's
+// "(N subcompartments)" label; larger counts show as "100+".
+const childCountLimit = 100
+
+// primeTenancyNames returns a batch of commands, one per tenancy without a
+// cached name, that each resolve and cache the friendly name and post a
+// tenancyNameMsg. Lookups are best-effort: failures post nothing and the
+// OCID stays on screen. concurrency bounds simultaneous identity calls;
+// non-positive values use the config default. timeout applies per lookup,
+// under parent.
+func primeTenancyNames(parent context.Context, profiles map[string]ocicfg.Profile, ociCfgPath string, concurrency int, timeout time.Duration) tea.Cmd {
 	if len(profiles) == 0 || ociCfgPath == "" {
-		return
+		return nil
 	}
-	// avoid refetching already cached tenancies
-	needed := make(map[string]ocicfg.Profile) // tenancyOCID -> representative profile (with region)
-	for _, p := range profiles {
-		if lookupTenancyName(p.Tenancy) != "" {
+	// Pick one profile per uncached tenancy; the SDK only needs credentials
+	// from the config file, which is keyed by profile name. Sorting keeps the
+	// choice stable.
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	needed := make(map[string]string) // tenancyOCID -> profile name
+	var order []string
+	for _, name := range names {
+		tid := profiles[name].Tenancy
+		if _, ok := needed[tid]; ok || lookupTenancyName(tid) != "" {
 			continue
 		}
-		needed[p.Tenancy] = p
+		needed[tid] = name
+		order = append(order, tid)
 	}
-	if len(needed) == 0 {
-		return
+	if len(order) == 0 {
+		return nil
 	}
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, 4) // limit concurrency to 4
-	for tenancyOCID, profile := range needed {
-		wg.Add(1)
-		go func(tid string, prof ocicfg.Profile) {
-			defer wg.Done()
+	if concurrency <= 0 {
+		concurrency = config.DefaultIdentityConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	cmds := make([]tea.Cmd, 0, len(order))
+	for _, tid := range order {
+		profileName := needed[tid]
+		region := profiles[profileName].Region
+		cmds = append(cmds, func() tea.Msg {
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			// Use a profile name that belongs to this tenancy so identity calls can resolve the tenancy name.
-			// We don't care which profile, only that its tenancy/region are valid. Since we lost the profile
-			// name when grouping by tenancy above, reuse the tenancy OCID as a stand-in profile name when
-			// making the identity call; the SDK only needs credentials from the config file, which is keyed
-			// by profile name. To ensure we actually use a real profile name, select one from the original map
-			// that matches the tenancy.
-			profileName := ""
-			for name, p := range profiles {
-				if p.Tenancy == tid {
-					profileName = name
-					break
-				}
-			}
-			if profileName == "" {
-				return
-			}
-			details, err := fetchIdentityDetails(ctx, ociCfgPath, profileName, prof.Region, tid, "", "")
-			if err != nil {
-				return
+			ctx, cancel := context.WithTimeout(parent, timeout)
+			defer cancel()
+			details, err := fetchIdentityDetails(ctx, ociCfgPath, profileName, region, tid, "", "")
+			if err != nil || details.TenancyName == "" {
+				return nil
 			}
 			recordTenancyName(tid, details.TenancyName)
-		}(tenancyOCID, profile)
+			return tenancyNameMsg{ocid: tid, name: details.TenancyName}
+		})
 	}
-	wg.Wait()
+	return tea.Batch(cmds...)
 }
 
 // lookupTenancyName returns a cached friendly name for the tenancy OCID.
@@ -222,6 +249,13 @@ func abbreviateOCID(s string) string {
 func newTuiCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var dryRun bool
+	var noResolve bool
+	var activeOnly, allComps bool
+	var ultra bool
+	var timeout time.Duration
+	var selectValue string
+	var strict bool
 	cmd := &cobra.Command{
 		Use:   "tui [mode]",
 		Short: "Interactive context picker with compartment selection",
@@ -239,19 +273,52 @@ func newTuiCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			profiles, perr := ocicfg.LoadProfiles(cfg.Options.OCIConfigPath)
+			timeout, err := networkTimeout(cmd, cfg, tuiNetworkTimeout)
+			if err != nil {
+				return err
+			}
+			ociCfgPath, err := resolveOCIConfigPath(cfg)
+			if err != nil {
+				return err
+			}
+			profiles, perr := ocicfg.LoadProfiles(ociCfgPath)
 			items := profileMenuItems(cfg, profiles, perr)
 			startMode := ""
 			if len(args) == 1 {
 				startMode = args[0]
 			}
 			m := newTuiModel(cfg, path, items, profiles, startMode)
-			p := tea.NewProgram(m)
-			finalModel, err := p.Run()
-			if err != nil {
-				return err
+			m.dryRun = dryRun
+			m.noResolve = noResolve
+			m.showInactive = allComps || !activeOnly
+			m.networkTimeout = timeout
+			// An explicit --ultra (or --ultra=false) beats the saved density.
+			if cmd.Flags().Changed("ultra") {
+				m.ultraCompact = ultra
+			}
+			var fm tuiModel
+			selected := false
+			if selectValue != "" {
+				fm, selected = m.selectInStartMode(selectValue)
+				if !selected {
+					if strict {
+						return fmt.Errorf("%s", noSelectMatch(fm.mode, selectValue))
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "warn: %s; opening the TUI\n", noSelectMatch(fm.mode, selectValue))
+					m = fm
+				}
+			}
+			if !selected {
+				finalModel, err := tea.NewProgram(m).Run()
+				if err != nil {
+					return err
+				}
+				fm = finalModel.(tuiModel)
+			}
+			if fm.dryRun && fm.finalized && fm.err == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), strings.Join(savePreviewLines(fm.cfg, fm.ctxItem.Context), "\n"))
+				return nil
 			}
-			fm := finalModel.(tuiModel)
 			if fm.selected != "" {
 				fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %s\n", fm.selected)
 			}
@@ -260,6 +327,13 @@ func newTuiCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the planned save instead of writing config")
+	cmd.Flags().BoolVar(&noResolve, "no-resolve", false, "Skip background tenancy name and subcompartment count lookups")
+	addActiveOnlyFlags(cmd, &activeOnly, &allComps)
+	cmd.Flags().BoolVar(&ultra, "ultra", false, "Start in ultra-compact density for this run (overrides the saved preference)")
+	cmd.Flags().DurationVar(&timeout, "timeout", tuiNetworkTimeout, "Timeout per OCI call (overrides options.network_timeout)")
+	cmd.Flags().StringVar(&selectValue, "select", "", "Pick this item in the start mode (context, tenancy, region, or compartment name/OCID), save, and exit without the TUI")
+	cmd.Flags().BoolVar(&strict, "strict", false, "With --select, fail instead of opening the TUI when nothing matches")
 	return cmd
 }
 
@@ -294,6 +368,34 @@ type compDelegate struct {
 	ultraCompact bool
 }
 
+// regionItemsByLatency annotates regions with probe results and sorts them
+// fastest-first. Unreachable regions are kept and listed last.
+func regionItemsByLatency(regions []string, latencies map[string]time.Duration) []list.Item {
+	items := make([]regionItem, 0, len(regions))
+	for _, r := range regions {
+		lat, ok := latencies[r]
+		if !ok {
+			lat = oci.RegionUnreachable
+		}
+		items = append(items, regionItem{name: r, latency: lat, probed: true})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.latency == oci.RegionUnreachable) != (b.latency == oci.RegionUnreachable) {
+			return b.latency == oci.RegionUnreachable
+		}
+		if a.latency != b.latency {
+			return a.latency < b.latency
+		}
+		return a.name < b.name
+	})
+	out := make([]list.Item, len(items))
+	for i, it := range items {
+		out[i] = it
+	}
+	return out
+}
+
 type markedItem struct {
 	base        list.Item
 	title       string
@@ -600,24 +702,11 @@ func (d *regionDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 
 // contextsFromProfiles builds context items from OCI CLI profiles.
 func contextsFromProfiles(profiles map[string]ocicfg.Profile, current config.Context, hasCurrent bool) []list.Item {
-	names := make([]string, 0, len(profiles))
-	for name := range profiles {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	items := make([]list.Item, 0, len(names))
-	for _, name := range names {
-		p := profiles[name]
-		ci := contextItem{Context: config.Context{
-			Name:            name,
-			Profile:         name,
-			AuthMethod:      config.AuthMethodAPIKey,
-			TenancyOCID:     p.Tenancy,
-			CompartmentOCID: p.Tenancy,
-			Region:          p.Region,
-			User:            p.User,
-		}}
-		if hasCurrent && isContextEquivalentToNamedProfile(current, name, p) {
+	contexts := config.ContextsFromProfiles(profiles)
+	items := make([]list.Item, 0, len(contexts))
+	for _, ctx := range contexts {
+		ci := contextItem{Context: ctx}
+		if hasCurrent && isContextEquivalentToNamedProfile(current, ctx.Name, profiles[ctx.Name]) {
 			ci.isCurrent = true
 		}
 		items = append(items, ci)
@@ -849,15 +938,7 @@ func selectProfileForTenancy(item tenancyItem, profiles map[string]ocicfg.Profil
 
 // contextItemForProfile builds a contextItem from a profile entry.
 func contextItemForProfile(name string, p ocicfg.Profile) contextItem {
-	return contextItem{Context: config.Context{
-		Name:            name,
-		Profile:         name,
-		AuthMethod:      config.AuthMethodAPIKey,
-		TenancyOCID:     p.Tenancy,
-		CompartmentOCID: p.Tenancy,
-		Region:          p.Region,
-		User:            p.User,
-	}}
+	return contextItem{Context: config.ContextFromProfile(name, p)}
 }
 
 func toAuthMethodList() []list.Item {
@@ -901,8 +982,28 @@ func isTerminal() bool {
 	return term.IsTerminal(int(os.Stdout.Fd()))
 }
 
+// addActiveOnlyFlags registers --active-only and --all, which decide whether
+// compartment pickers hide compartments that are not ACTIVE.
+func addActiveOnlyFlags(cmd *cobra.Command, activeOnly, all *bool) {
+	cmd.Flags().BoolVar(activeOnly, "active-only", true, "Only offer ACTIVE compartments")
+	cmd.Flags().BoolVar(all, "all", false, "Offer compartments in every lifecycle state (same as --active-only=false)")
+	cmd.MarkFlagsMutuallyExclusive("active-only", "all")
+}
+
+// activeCompartments drops compartments whose lifecycle state is known and not
+// ACTIVE. The prompt and the TUI both use it so they offer the same choices.
+func activeCompartments(items []compItem) []compItem {
+	out := make([]compItem, 0, len(items))
+	for _, it := range items {
+		if it.oc.Status == "" || it.oc.Status == config.CompartmentStateActive {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
 // runPromptFallback provides a non-TTY prompt-based flow.
-func runPromptFallback(cmd *cobra.Command, cfgPathFlag string) error {
+func runPromptFallback(cmd *cobra.Command, cfgPathFlag string, activeOnly bool) error {
 	useGlobal, err := cmd.Flags().GetBool("global")
 	if err != nil {
 		return err
@@ -915,11 +1016,27 @@ func runPromptFallback(cmd *cobra.Command, cfgPathFlag string) error {
 	if err != nil {
 		return err
 	}
-	profiles, perr := ocicfg.LoadProfiles(cfg.Options.OCIConfigPath)
+	ociCfg, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		return err
+	}
+	profiles, perr := ocicfg.LoadProfiles(ociCfg)
+	if perr != nil || len(profiles) == 0 {
+		return fmt.Errorf("no profiles available from %s", ociCfg)
+	}
+	return promptContextSetup(cmd, path, cfg, ociCfg, profiles, false, activeOnly)
+}
+
+// promptContextSetup asks for a profile, optionally a region, and a
+// compartment (one level at a time), then saves the result as the current
+// context. With activeOnly, compartments that are not ACTIVE are not offered.
+func promptContextSetup(cmd *cobra.Command, path string, cfg config.Config, ociCfg string, profiles map[string]ocicfg.Profile, askRegion, activeOnly bool) error {
 	items := contextsFromProfiles(profiles, config.Context{}, false)
-	if perr != nil || len(items) == 0 {
-		return fmt.Errorf("no profiles available from %s", cfg.Options.OCIConfigPath)
+	if len(items) == 0 {
+		return fmt.Errorf("no profiles available from %s", ociCfg)
 	}
+	// Region and compartment lookups below run under cmd's context.
+	cmd.SetContext(ociContext(cmd.Context(), cfg))
 
 	fmt.Fprintln(cmd.OutOrStdout(), "Select profile:")
 	for i, it := range items {
@@ -931,23 +1048,55 @@ func runPromptFallback(cmd *cobra.Command, cfgPathFlag string) error {
 		return err
 	}
 	ctx := items[idx].(contextItem).Context
+	timeout, err := networkTimeout(cmd, cfg, tuiNetworkTimeout)
+	if err != nil {
+		return err
+	}
+	if askRegion {
+		region, err := promptRegion(cmd, ctx, ociCfg, timeout)
+		if err != nil {
+			return err
+		}
+		ctx.Region = region
+	}
 	// drill compartments one level at a time
 	parent := ctx.CompartmentOCID
 	if parent == "" {
 		parent = ctx.TenancyOCID
 	}
-	ociCfg := cfg.Options.OCIConfigPath
+	state := ""
+	// levels records each compartment drilled out of, so b can climb back.
+	type level struct{ id, state string }
+	var levels []level
 	for {
 		fmt.Fprintf(cmd.OutOrStdout(), "Listing compartments under %s...\n", parent)
-		citems, err := fetchPromptChildren(cmd, ctx, ociCfg, parent)
+		citems, err := fetchPromptChildren(cmd, ctx, ociCfg, parent, timeout)
 		if err != nil {
 			return err
 		}
+		hidden := 0
+		if activeOnly {
+			all := len(citems)
+			citems = activeCompartments(citems)
+			hidden = all - len(citems)
+		}
 		if len(citems) == 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "No child compartments; keeping current selection.")
-			break
+			empty := "No child compartments"
+			if hidden > 0 {
+				empty = fmt.Sprintf("No ACTIVE child compartments (%d hidden; use --all)", hidden)
+			}
+			if len(levels) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s; keeping current selection.\n", empty)
+				break
+			}
+			// A leaf reached by drilling in can still be backed out of.
+			fmt.Fprintf(cmd.OutOrStdout(), "%s.\n", empty)
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "Select compartment (or 0 to keep current):")
+		}
+		if len(levels) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "b) back to %s\n", levels[len(levels)-1].id)
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), "Select compartment (or 0 to keep current):")
 		fmt.Fprintf(cmd.OutOrStdout(), "0) stay at %s\n", parent)
 		for i, ci := range citems {
 			marker := ""
@@ -956,33 +1105,78 @@ func runPromptFallback(cmd *cobra.Command, cfgPathFlag string) error {
 			}
 			fmt.Fprintf(cmd.OutOrStdout(), "%d) %s%s\n", i+1, ci.oc.Name, marker)
 		}
-		cidx, err := readChoiceZero(cmd, len(citems))
+		cidx, err := readChoiceZero(cmd, len(citems), len(levels) > 0)
+		if errors.Is(err, io.EOF) && len(citems) == 0 {
+			// Scripted input that ends at a leaf keeps the leaf, as before b existed there.
+			break
+		}
 		if err != nil {
 			return err
 		}
+		if cidx == choiceBack {
+			up := levels[len(levels)-1]
+			levels = levels[:len(levels)-1]
+			parent, state = up.id, up.state
+			continue
+		}
 		if cidx == -1 {
 			// 0 chosen
 			break
 		}
+		levels = append(levels, level{id: parent, state: state})
 		parent = citems[cidx].oc.ID
+		state = citems[cidx].oc.Status
 	}
-	ctx.CompartmentOCID = parent
-	cfg.CurrentContext = ctx.Name
+	ctx.SetCompartment(parent, state)
+	cfg.SwitchContext(ctx.Name)
 	if err := cfg.UpsertContext(ctx); err != nil {
 		return err
 	}
 	if err := config.Save(path, cfg); err != nil {
 		return err
 	}
+	if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+		return err
+	}
 	fmt.Fprintf(cmd.OutOrStdout(), "Selected context %s with compartment %s\n", ctx.Name, parent)
 	return nil
 }
 
-// fetchPromptChildren mirrors the TUI lazy compartment fetch for the non-TTY prompt flow.
-func fetchPromptChildren(cmd *cobra.Command, ctx config.Context, ociCfgPath string, parent string) ([]compItem, error) {
-	c, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+// promptRegion offers the tenancy's subscribed regions, keeping the profile's
+// region on 0 or when the subscriptions cannot be listed. timeout bounds the
+// listing call.
+func promptRegion(cmd *cobra.Command, ctx config.Context, ociCfg string, timeout time.Duration) (string, error) {
+	c, cancel := context.WithTimeout(cmd.Context(), timeout)
 	defer cancel()
-	children, err := oci.FetchCompartments(c, ociCfgPath, ctx.Profile, ctx.Region, parent)
+	regions, err := listRegionSubscriptions(c, ociCfg, ctx.Profile)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warn: list regions: %v; keeping %s\n", withOCIHint(err), ctx.Region)
+		return ctx.Region, nil
+	}
+	if len(regions) == 0 {
+		return ctx.Region, nil
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Select region (or 0 to keep current):")
+	fmt.Fprintf(cmd.OutOrStdout(), "0) stay at %s\n", ctx.Region)
+	for i, r := range regions {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d) %s\n", i+1, r)
+	}
+	idx, err := readChoiceZero(cmd, len(regions), false)
+	if err != nil {
+		return "", err
+	}
+	if idx == -1 {
+		return ctx.Region, nil
+	}
+	return regions[idx], nil
+}
+
+// fetchPromptChildren mirrors the TUI lazy compartment fetch for the non-TTY
+// prompt flow, bounding the call by timeout.
+func fetchPromptChildren(cmd *cobra.Command, ctx config.Context, ociCfgPath string, parent string, timeout time.Duration) ([]compItem, error) {
+	c, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+	children, err := fetchCompartments(c, ociCfgPath, ctx.Profile, ctx.Region, parent)
 	if err != nil {
 		return nil, err
 	}
@@ -1004,11 +1198,23 @@ func readChoice(cmd *cobra.Command, n int) (int, error) {
 	return choice - 1, nil
 }
 
-func readChoiceZero(cmd *cobra.Command, n int) (int, error) {
-	var choice int
-	if _, err := fmt.Fscan(cmd.InOrStdin(), &choice); err != nil {
+// choiceBack is what readChoiceZero returns for b (or -1) when going back is allowed.
+const choiceBack = -2
+
+// readChoiceZero reads a choice from 0 to n, returning -1 for 0 and the
+// zero-based index otherwise. With allowBack, b or -1 returns choiceBack.
+func readChoiceZero(cmd *cobra.Command, n int, allowBack bool) (int, error) {
+	var token string
+	if _, err := fmt.Fscan(cmd.InOrStdin(), &token); err != nil {
 		return 0, err
 	}
+	if allowBack && (strings.EqualFold(token, "b") || token == "-1") {
+		return choiceBack, nil
+	}
+	choice, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid choice")
+	}
 	if choice == 0 {
 		return -1, nil
 	}
@@ -1024,6 +1230,12 @@ type contextItem struct {
 	isCurrent bool
 }
 
+// isZero reports whether no context has been chosen yet. Every chosen
+// context has a name, so that is the only field checked.
+func (c contextItem) isZero() bool {
+	return c.Name == ""
+}
+
 func (c contextItem) Title() string {
 	if c.isCurrent {
 		if !c.fromSaved {
@@ -1064,7 +1276,11 @@ func (t tenancyItem) FilterValue() string {
 }
 
 type compItem struct {
-	oc oci.Compartment
+	oc       oci.Compartment
+	path     string // full name path, set for subtree search results
+	favorite bool   // pinned entry shown above the tenancy root's children
+	children int    // direct subcompartments, valid when counted
+	counted  bool
 }
 
 func (c compItem) Title() string {
@@ -1073,16 +1289,54 @@ func (c compItem) Title() string {
 	if state != "ACTIVE" {
 		marker = fmt.Sprintf(" [%s]", state)
 	}
-	return fmt.Sprintf("%s%s", c.oc.Name, marker)
+	name := c.oc.Name
+	if c.path != "" {
+		name = c.path
+	}
+	if c.favorite {
+		name = "★ " + name
+	}
+	return fmt.Sprintf("%s%s", name, marker)
 }
-func (c compItem) Description() string { return c.oc.ID }
+func (c compItem) Description() string {
+	if !c.counted {
+		return c.oc.ID
+	}
+	return fmt.Sprintf("%s  %s", c.oc.ID, childCountLabel(c.children))
+}
+
+// childCountLabel describes a subcompartment count so dead ends are visible
+// before drilling in.
+func childCountLabel(n int) string {
+	switch {
+	case n <= 0:
+		return "(leaf)"
+	case n == 1:
+		return "(1 subcompartment)"
+	case n >= childCountLimit:
+		return fmt.Sprintf("(%d+ subcompartments)", childCountLimit)
+	default:
+		return fmt.Sprintf("(%d subcompartments)", n)
+	}
+}
+
 func (c compItem) FilterValue() string { return c.oc.Name }
 
 type regionItem struct {
-	name string
+	name    string
+	latency time.Duration // set after an opt-in latency probe
+	probed  bool
 }
 
-func (r regionItem) Title() string       { return r.name }
+func (r regionItem) Title() string {
+	if !r.probed {
+		return r.name
+	}
+	if r.latency == oci.RegionUnreachable {
+		return fmt.Sprintf("%s  (unreachable)", r.name)
+	}
+	return fmt.Sprintf("%s  %dms", r.name, r.latency.Milliseconds())
+}
 func (r regionItem) Description() string { return r.name }
 func (r regionItem) FilterValue() string { return r.name }
 
@@ -1121,6 +1375,7 @@ type tuiModel struct {
 	users              list.Model
 	cfg                config.Config
 	cfgPath            string
+	ociCfgPath         string // resolved OCI CLI config path
 	selected           string
 	profiles           map[string]ocicfg.Profile
 	err                error
@@ -1130,6 +1385,9 @@ type tuiModel struct {
 	regions            list.Model
 	parentCrumb        string
 	compCache          map[string][]compItem
+	childCounts        map[string]map[string]int // parent ID -> child ID -> subcompartment count
+	subtreeCache       map[string][]compItem     // tenancy OCID -> every compartment in its subtree
+	subtreeSearch      bool                      // comps list is showing subtree search results
 	parentID           string
 	parentMap          map[string]string // childID -> parentID
 	nameMap            map[string]string // id -> display name
@@ -1153,8 +1411,17 @@ type tuiModel struct {
 	savedAuthMethod    string              // auth method currently persisted on disk
 	savedUser          string              // user currently persisted on disk
 	ultraCompact       bool                // minimal chrome mode
-	helpVisible        bool                // keybindings panel toggle
+	helpVisible        bool                // full-screen keybindings overlay
+	previewVisible     bool                // planned-save preview overlay toggle
+	dryRun             bool                // finalize reports the plan instead of saving
+	noResolve          bool                // skip background tenancy name lookups
+	showInactive       bool                // list compartments that are not ACTIVE
+	networkTimeout     time.Duration       // per-call timeout for OCI requests
 	initCmd            tea.Cmd             // optional startup command for shortcut modes
+	loadGen            int                 // bumped per compartment/region load; results from older loads are dropped
+	cancelLoad         context.CancelFunc  // cancels the load in flight; nil when none is
+	loadFrom           tuiLevel            // where esc/backspace return to while a load is in flight
+	spinner            spinner.Model       // animates the status line while a load is in flight
 	theme              tuiTheme
 	prefs              tuiPrefs
 	prefsPath          string
@@ -1182,6 +1449,10 @@ func newTuiModel(cfg config.Config, cfgPath string, items []list.Item, profiles
 	if defaultHeight < 10 {
 		defaultHeight = 10
 	}
+	ociCfgPath, err := resolveOCIConfigPath(cfg)
+	if err != nil {
+		ociCfgPath = ociConfigOption(cfg)
+	}
 	prefs, prefsPath, prefsErr := loadTUIPrefs()
 	if prefsErr != nil {
 		prefs = defaultTUIPrefs()
@@ -1205,8 +1476,7 @@ func newTuiModel(cfg config.Config, cfgPath string, items []list.Item, profiles
 	tn.SetShowHelp(false)
 	tn.SetShowStatusBar(false)
 	if len(profiles) > 0 {
-		// Try to pre-populate tenancy friendly names using identity calls so titles show names immediately.
-		primeTenancyNames(context.Background(), profiles, cfg.Options.OCIConfigPath)
+		// Names already cached show immediately; the rest arrive via primeTenancyNamesCmd.
 		tn.SetItems(tenanciesFromProfiles(profiles))
 	}
 	// Preselect current context if present
@@ -1261,26 +1531,32 @@ func newTuiModel(cfg config.Config, cfgPath string, items []list.Item, profiles
 	ul.SetShowHelp(false)
 	ul.SetShowStatusBar(false)
 	m := tuiModel{
-		list:        l,
-		tenancies:   tn,
-		authMethods: al,
-		users:       ul,
-		cfg:         cfg,
-		cfgPath:     cfgPath,
-		mode:        "contexts",
-		profiles:    profiles,
-		comps:       cl,
-		regions:     rl,
-		compCache:   make(map[string][]compItem),
-		parentMap:   make(map[string]string),
-		nameMap:     make(map[string]string),
-		regionCache: make(map[string][]string),
-		theme:       newTUITheme(),
-		prefs:       prefs,
-		prefsPath:   prefsPath,
-		width:       defaultWidth,
-		height:      defaultHeight,
-	}
+		list:         l,
+		tenancies:    tn,
+		authMethods:  al,
+		users:        ul,
+		cfg:          cfg,
+		cfgPath:      cfgPath,
+		ociCfgPath:   ociCfgPath,
+		mode:         "contexts",
+		profiles:     profiles,
+		comps:        cl,
+		regions:      rl,
+		compCache:    make(map[string][]compItem),
+		childCounts:  make(map[string]map[string]int),
+		subtreeCache: make(map[string][]compItem),
+		parentMap:    make(map[string]string),
+		nameMap:      make(map[string]string),
+		regionCache:  make(map[string][]string),
+		theme:        newTUITheme(),
+		prefs:        prefs,
+		prefsPath:    prefsPath,
+		ultraCompact: prefs.Density == tuiDensityUltra,
+		spinner:      spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		width:        defaultWidth,
+		height:       defaultHeight,
+	}
+	m.networkTimeout = tuiNetworkTimeout
 	if current, err := cfg.GetContext(cfg.CurrentContext); err == nil {
 		m.savedContextName = cfg.CurrentContext
 		m.savedTenancyOCID = current.TenancyOCID
@@ -1395,6 +1671,7 @@ func selectInitialContext(items []list.Item, current string) (contextItem, bool)
 // applyStartMode primes the model for the requested starting menu (contexts/compartments/regions/tenancies).
 func (m *tuiModel) applyStartMode(startMode string) {
 	mode := strings.ToLower(strings.TrimSpace(startMode))
+	from := m.level()
 	switch mode {
 	case "", "context", "contexts":
 		// default: nothing to do
@@ -1404,7 +1681,9 @@ func (m *tuiModel) applyStartMode(startMode string) {
 			m.ctxItem = ctx
 			m.mode = "regions"
 			m.status = "Loading regions..."
-			m.initCmd = m.loadRegionsCmd(ctx)
+			m.loadFrom = from
+			loadCtx, gen := m.beginLoad()
+			m.initCmd = m.loadRegionsCmd(loadCtx, gen, ctx)
 			return
 		}
 	case "compartment", "compartments":
@@ -1421,8 +1700,10 @@ func (m *tuiModel) applyStartMode(startMode string) {
 			m.nameMap[ctx.TenancyOCID] = parentLabel(ctx.TenancyOCID, ctx)
 			m.mode = "compartments"
 			m.status = "Loading compartments..."
-			m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-			m.initCmd = m.loadCompsCmd(parent)
+			m.crumb = m.compartmentCrumb(parent)
+			m.loadFrom = from
+			loadCtx, gen := m.beginLoad()
+			m.initCmd = m.loadCompsCmd(loadCtx, gen, parent)
 			return
 		}
 	case "tenancy", "tenancies":
@@ -1460,13 +1741,12 @@ func (m tuiModel) goUpOne() (tea.Model, tea.Cmd) {
 	} else {
 		m.parentCrumb = parentLabel(parent, m.ctxItem)
 	}
-	m.status = "Loading compartments..."
-	m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, m.parentID)
-	return m, m.loadCompsCmd(m.parentID)
+	m.crumb = m.compartmentCrumb(m.parentID)
+	return m.startCompLoad(m.parentID)
 }
 
 func (m tuiModel) ensureActiveContext() (tuiModel, bool) {
-	if m.ctxItem != (contextItem{}) {
+	if !m.ctxItem.isZero() {
 		return m, true
 	}
 	if item, ok := m.list.SelectedItem().(contextItem); ok {
@@ -1512,9 +1792,9 @@ func (m tuiModel) switchToMenu(target string) (tuiModel, tea.Cmd, bool) {
 		m.nameMap[parent] = m.parentCrumb
 		m.nameMap[m.ctxItem.TenancyOCID] = parentLabel(m.ctxItem.TenancyOCID, m.ctxItem)
 		m.mode = "compartments"
-		m.status = "Loading compartments..."
-		m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-		return m, m.loadCompsCmd(parent), true
+		m.crumb = m.compartmentCrumb(parent)
+		nm, cmd := m.startCompLoad(parent)
+		return nm, cmd, true
 	case "regions":
 		var ok bool
 		m, ok = m.ensureActiveContext()
@@ -1529,7 +1809,8 @@ func (m tuiModel) switchToMenu(target string) (tuiModel, tea.Cmd, bool) {
 			m.status = "Select region (Space to stage, Ctrl+S to save)"
 			return m, nil, true
 		}
-		return m, m.loadRegionsCmd(m.ctxItem), true
+		nm, cmd := m.startRegionLoad(m.ctxItem)
+		return nm, cmd, true
 	case "auth":
 		var ok bool
 		m, ok = m.ensureActiveContext()
@@ -1595,10 +1876,27 @@ func (m tuiModel) cycleMenu(forward bool) (tea.Model, tea.Cmd) {
 }
 
 func (m tuiModel) Init() tea.Cmd {
-	return m.initCmd
+	if m.cancelLoad != nil {
+		return tea.Batch(m.initCmd, m.spinner.Tick, m.primeTenancyNamesCmd())
+	}
+	return tea.Batch(m.initCmd, m.primeTenancyNamesCmd())
 }
 
+// Update handles msg and, when that starts a compartment or region load,
+// records the level it was started from for abandonLoad. A load that
+// replaces one still in flight keeps the first one's origin, since the level
+// in between never loaded.
 func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	from, gen, loading := m.level(), m.loadGen, m.cancelLoad != nil
+	next, cmd := m.update(msg)
+	if nm, ok := next.(tuiModel); ok && !loading && nm.cancelLoad != nil && nm.loadGen != gen {
+		nm.loadFrom = from
+		next = nm
+	}
+	return next, cmd
+}
+
+func (m tuiModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.refreshDelegates()
 	m.resizeListsForViewport()
 	var cmd tea.Cmd
@@ -1608,6 +1906,17 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.resizeListsForViewport()
 	case tea.KeyMsg:
+		// The help overlay is modal: it only closes (or quits on ctrl+c), so
+		// the mode and staged selections underneath are left untouched.
+		if m.helpVisible {
+			switch msg.String() {
+			case "?", "esc":
+				m.helpVisible = false
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 		// In wide mode, navigate active list as a grid with arrows or vim keys.
 		if m.shouldUseGridLayout() && m.moveActiveSelectionGrid(msg.String()) {
 			return m, nil
@@ -1617,6 +1926,12 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.activeListFilterState() == list.Filtering {
 			return m.updateActiveList(msg)
 		}
+		if m.cancelLoad != nil {
+			switch msg.String() {
+			case "esc", "delete", "backspace":
+				return m.abandonLoad(), nil
+			}
+		}
 
 		switch msg.String() {
 		case "tab":
@@ -1648,9 +1963,8 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.nameMap[parent] = m.parentCrumb
 					m.nameMap[item.TenancyOCID] = parentLabel(item.TenancyOCID, item)
 					m.mode = "compartments"
-					m.status = "Loading compartments..."
-					m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-					return m, m.loadCompsCmd(parent)
+					m.crumb = m.compartmentCrumb(parent)
+					return m.startCompLoad(parent)
 				}
 			} else if m.mode == "tenancies" {
 				if len(m.tenancies.Items()) == 0 {
@@ -1684,9 +1998,8 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.nameMap[parent] = m.parentCrumb
 					m.nameMap[item.TenancyOCID] = parentLabel(item.TenancyOCID, m.ctxItem)
 					m.mode = "compartments"
-					m.status = "Loading compartments..."
-					m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-					return m, m.loadCompsCmd(parent)
+					m.crumb = m.compartmentCrumb(parent)
+					return m.startCompLoad(parent)
 				}
 				return m, nil
 			} else if m.mode == "compartments" {
@@ -1701,15 +2014,15 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.comps.SetFilterState(list.Unfiltered)
 						m.comps.SetShowFilter(false)
 					}
+					m.subtreeSearch = false
 					m.parentID = item.oc.ID
 					m.parentCrumb = item.oc.Name
 					m.nameMap[item.oc.ID] = item.oc.Name
 					m.parentMap[item.oc.ID] = item.oc.Parent
 					m.pendingSelectionID = ""
 					m.pendingSelectionNm = ""
-					m.status = "Loading compartments..."
-					m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, m.parentID)
-					return m, m.loadCompsCmd(item.oc.ID)
+					m.crumb = m.compartmentCrumb(m.parentID)
+					return m.startCompLoad(item.oc.ID)
 				}
 			} else if m.mode == "regions" {
 				if len(m.regions.Items()) == 0 {
@@ -1857,6 +2170,11 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q":
 			return m.saveAndQuitCurrentMode()
 		case "esc":
+			if m.previewVisible {
+				m.previewVisible = false
+				m.status = "Save preview: OFF"
+				return m, nil
+			}
 			if m.activeListFilterState() == list.FilterApplied {
 				m.clearActiveAppliedFilter()
 				m.status = "Filter cleared"
@@ -1913,7 +2231,7 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.status = "Select region (Space to stage, Ctrl+S to save)"
 						return m, nil
 					}
-					return m, m.loadRegionsCmd(item)
+					return m.startRegionLoad(item)
 				}
 			}
 		case "a":
@@ -1959,7 +2277,7 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.status = "Select region (Space to stage, Ctrl+S to save)"
 					return m, nil
 				}
-				return m, m.loadRegionsCmd(m.ctxItem)
+				return m.startRegionLoad(m.ctxItem)
 			}
 		case "c":
 			// From contexts: open compartments
@@ -1978,16 +2296,15 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.nameMap[parent] = m.parentCrumb
 					m.nameMap[item.TenancyOCID] = parentLabel(item.TenancyOCID, item)
 					m.mode = "compartments"
-					m.status = "Loading compartments..."
-					m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-					return m, m.loadCompsCmd(parent)
+					m.crumb = m.compartmentCrumb(parent)
+					return m.startCompLoad(parent)
 				}
 			}
 		case "C":
 			// From any submenu: go to compartments for the current context/tenancy
 			if m.mode != "contexts" {
 				// ensure ctxItem is set; pick initial if needed
-				if (m.ctxItem == contextItem{}) {
+				if m.ctxItem.isZero() {
 					if ctx, ok := selectInitialContext(m.list.Items(), m.cfg.CurrentContext); ok {
 						m.ctxItem = ctx
 					}
@@ -2004,9 +2321,8 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.nameMap[parent] = m.parentCrumb
 				m.nameMap[m.ctxItem.TenancyOCID] = parentLabel(m.ctxItem.TenancyOCID, m.ctxItem)
 				m.mode = "compartments"
-				m.status = "Loading compartments..."
-				m.crumb = fmt.Sprintf("Current: %s (%s)", m.parentCrumb, parent)
-				return m, m.loadCompsCmd(parent)
+				m.crumb = m.compartmentCrumb(parent)
+				return m.startCompLoad(parent)
 			}
 		case "t":
 			// Tenancies: only valid from main contexts menu
@@ -2084,12 +2400,65 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.users.SetShowFilter(true)
 			}
 			return m, nil
-		case "?":
-			m.helpVisible = !m.helpVisible
-			if m.helpVisible {
-				m.status = "Keybindings help: ON"
+		case "s":
+			if m.mode == "compartments" {
+				tenancy := m.ctxItem.TenancyOCID
+				if tenancy == "" {
+					m.status = "Select a profile first"
+					return m, nil
+				}
+				if cached, ok := m.subtreeCache[tenancy]; ok {
+					m.showSubtreeSearch(tenancy, cached)
+					return m, nil
+				}
+				return m.startSubtreeLoad(tenancy)
+			}
+		case "f":
+			if m.mode == "compartments" {
+				return m.toggleFavoriteCompartment()
+			}
+		case "y":
+			if m.mode == "contexts" || m.mode == "tenancies" || m.mode == "compartments" {
+				return m.copySelectedOCID()
+			}
+		case "L":
+			if m.mode == "regions" {
+				var names []string
+				for _, it := range m.regions.Items() {
+					if ri, ok := it.(regionItem); ok {
+						names = append(names, ri.name)
+					}
+				}
+				if len(names) == 0 {
+					m.status = "No regions to probe"
+					return m, nil
+				}
+				m.status = fmt.Sprintf("Probing latency to %d regions...", len(names))
+				return m, loadRegionLatencyCmd(m.ctxItem.Name, names)
+			}
+		case "D":
+			m.previewVisible = !m.previewVisible
+			if m.previewVisible {
+				m.status = "Save preview: ON"
 			} else {
-				m.status = "Keybindings help: OFF"
+				m.status = "Save preview: OFF"
+			}
+			return m, nil
+		case "?":
+			m.helpVisible = true
+			return m, nil
+		case "z":
+			m.ultraCompact = !m.ultraCompact
+			density := tuiDensityNormal
+			if m.ultraCompact {
+				density = tuiDensityUltra
+			}
+			m.prefs.Density = density
+			m.status = fmt.Sprintf("Density: %s", density)
+			if m.prefsPath != "" {
+				if err := saveTUIDensity(m.prefsPath, density); err != nil {
+					m.status = fmt.Sprintf("Density: %s (save failed: %v)", density, err)
+				}
 			}
 			return m, nil
 		case "v":
@@ -2133,10 +2502,22 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	}
+	// The spinner stops by dropping its tick once no load is pending.
+	if tick, ok := msg.(spinner.TickMsg); ok && tick.ID == m.spinner.ID() {
+		if m.cancelLoad == nil {
+			return m, nil
+		}
+		m.spinner, cmd = m.spinner.Update(tick)
+		return m, cmd
+	}
 	// handle async comp results
 	if res, ok := msg.(compResultMsg); ok {
+		if res.gen != m.loadGen {
+			return m, nil
+		}
+		m.finishLoad()
 		if res.err != nil {
-			m.err = res.err
+			m.err = withOCIHint(res.err)
 			return m, tea.Quit
 		}
 		m.compCache[res.parent] = res.items
@@ -2144,18 +2525,86 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.parentMap[it.oc.ID] = it.oc.Parent
 			m.nameMap[it.oc.ID] = it.oc.Name
 		}
-		m.comps.SetItems(toList(res.items))
+		m.subtreeSearch = false
+		m.comps.SetItems(m.compartmentListItems(res.parent, res.items))
 		m.comps.Title = fmt.Sprintf("Select compartment under %s", res.parent)
-		if len(res.items) == 0 {
+		if len(m.visibleCompartments(res.items)) == 0 {
 			m.status = "Leaf compartment: press backspace/delete to go up, or Enter/Space/Ctrl+S to keep current."
 		} else {
 			m.status = ""
 		}
+		if _, ok := m.childCounts[res.parent]; ok || m.noResolve || len(res.items) == 0 {
+			return m, nil
+		}
+		m.childCounts[res.parent] = make(map[string]int, len(res.items))
+		return m, m.loadChildCountsCmd(res.parent, m.visibleCompartments(res.items))
+	}
+	if res, ok := msg.(childCountMsg); ok {
+		if m.childCounts[res.parent] == nil {
+			return m, nil
+		}
+		m.childCounts[res.parent][res.id] = res.count
+		for i, it := range m.comps.Items() {
+			if ci, ok := it.(compItem); ok && !ci.favorite && ci.oc.ID == res.id {
+				ci.children, ci.counted = res.count, true
+				m.comps.SetItem(i, ci)
+			}
+		}
+		return m, nil
+	}
+	if res, ok := msg.(subtreeResultMsg); ok {
+		if res.gen != m.loadGen {
+			return m, nil
+		}
+		m.finishLoad()
+		// The user may have left or switched tenancy while the scan ran.
+		if m.mode != "compartments" || res.tenancy != m.ctxItem.TenancyOCID {
+			return m, nil
+		}
+		if res.err != nil {
+			m.status = fmt.Sprintf("Compartment search failed: %v", withOCIHint(res.err))
+			return m, nil
+		}
+		if m.subtreeCache == nil {
+			m.subtreeCache = make(map[string][]compItem)
+		}
+		m.subtreeCache[res.tenancy] = res.items
+		m.showSubtreeSearch(res.tenancy, res.items)
+		return m, nil
+	}
+	if res, ok := msg.(tenancyNameMsg); ok {
+		for i, it := range m.tenancies.Items() {
+			if ti, ok := it.(tenancyItem); ok && ti.TenancyOCID == res.ocid {
+				ti.Name = res.name
+				m.tenancies.SetItem(i, ti)
+			}
+		}
+		return m, nil
+	}
+	if res, ok := msg.(regionLatencyMsg); ok {
+		// The user may have left regions or switched context while probing.
+		if m.mode != "regions" || res.ctxName != m.ctxItem.Name {
+			return m, nil
+		}
+		m.regions.SetItems(regionItemsByLatency(res.regions, res.latencies))
+		m.regions.Select(0)
+		unreachable := 0
+		for _, r := range res.regions {
+			if lat, ok := res.latencies[r]; !ok || lat == oci.RegionUnreachable {
+				unreachable++
+			}
+		}
+		m.status = fmt.Sprintf("Sorted %d regions by latency (%d unreachable)", len(res.regions), unreachable)
+		return m, nil
 	}
 	if res, ok := msg.(regionResultMsg); ok {
+		if res.gen != m.loadGen {
+			return m, nil
+		}
+		m.finishLoad()
 		if res.err != nil {
 			// fallback to static regions but keep the error in status for visibility
-			m.status = fmt.Sprintf("Region fetch failed: %v (showing defaults)", res.err)
+			m.status = fmt.Sprintf("Region fetch failed: %v (showing defaults)", withOCIHint(res.err))
 			m.regionCache[res.ctxName] = fallbackRegions
 			m.regions.SetItems(toRegionList(fallbackRegions))
 			m.regions.Select(0)
@@ -2205,8 +2654,14 @@ func (m tuiModel) View() string {
 		return m.theme.panel.Render(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render(fmt.Sprintf("error: %v", m.err)))
 	}
 	if m.finalized {
+		if m.dryRun {
+			return "Dry run: no changes written\n"
+		}
 		return fmt.Sprintf("Selected context %s with compartment %s\n", m.ctxItem.Name, m.parentID)
 	}
+	if m.helpVisible {
+		return m.renderHelpOverlay()
+	}
 	panelContent := m.activeListView()
 	if m.activeListFilterState() == list.Unfiltered {
 		gap := "\n"
@@ -2225,14 +2680,14 @@ func (m tuiModel) View() string {
 		m.theme.panel.Render(panelContent),
 	}
 
-	if !m.ultraCompact && m.helpVisible {
-		lines = append(lines, m.theme.panel.Render(m.renderHelpPanel()))
+	if m.previewVisible {
+		lines = append(lines, m.theme.panel.Render(m.renderSavePreview()))
 	}
 
 	if m.shouldInlineHotkeys() {
 		lines = append(lines, m.renderMetaLineWithHotkeys())
 	} else {
-		if !m.ultraCompact && !m.helpVisible {
+		if !m.ultraCompact {
 			lines = append(lines, m.theme.instructions.Render(primaryHotkeys(m.width > 0 && m.width < 72)))
 		}
 		lines = append(lines, m.renderMetaLine())
@@ -2247,6 +2702,9 @@ func (m tuiModel) View() string {
 
 func (m tuiModel) renderStatusLine() string {
 	s := m.status
+	if m.cancelLoad != nil {
+		s = m.spinner.View() + " " + s
+	}
 	lower := strings.ToLower(s)
 	switch {
 	case strings.Contains(lower, "error"), strings.Contains(lower, "failed"):
@@ -2892,9 +3350,9 @@ func (m tuiModel) shouldInlineHotkeys() bool {
 
 func primaryHotkeys(compact bool) string {
 	if compact {
-		return "enter/backspace drill/up • space stage • / filter • a auth • u user • v verbose • m matrix • q save • ? help"
+		return "tab mode • enter/backspace drill/up • space stage • / filter • q save • ? all keys"
 	}
-	return "enter/backspace drill/up • space stage • / filter • a auth • u user • v verbose • m matrix • q save • ? help"
+	return "tab/shift+tab mode • enter/backspace drill/up • space stage • / filter • v verbose • m matrix • q save • ? all keys"
 }
 
 func inlineStateSummary(m tuiModel) string {
@@ -2923,30 +3381,82 @@ func displayModeName(mode string) string {
 	return mode
 }
 
-func (m tuiModel) renderHelpPanel() string {
+// tuiHelpSection is one block of the `?` overlay; mode is "" for keys that
+// work everywhere.
+type tuiHelpSection struct {
+	title string
+	mode  string
+	keys  [][2]string
+}
+
+var tuiHelpSections = []tuiHelpSection{
+	{title: "Everywhere", keys: [][2]string{
+		{"tab / shift+tab", "next / previous mode"},
+		{"enter, right", "drill in or apply"},
+		{"space", "stage or unstage the highlighted row"},
+		{"backspace", "go up / back (when not filtering)"},
+		{"/", "filter the current list (esc clears)"},
+		{"ctrl+s, q", "save staged selections and quit"},
+		{"D", "toggle the planned-save preview"},
+		{"v", "toggle verbose rows for this mode"},
+		{"m", "toggle the matrix layout"},
+		{"z", "toggle ultra-compact density (remembered)"},
+		{"?", "toggle this help"},
+		{"esc, ctrl+c", "quit without saving"},
+		{"esc, backspace", "cancel a load in flight and go back"},
+	}},
+	{title: "Profiles", mode: "contexts", keys: [][2]string{
+		{"r c t", "regions, compartments, tenancies"},
+		{"a u", "auth method, user"},
+		{"y", "copy the profile's compartment (or tenancy) OCID"},
+	}},
+	{title: "Tenancies", mode: "tenancies", keys: [][2]string{
+		{"y", "copy the tenancy OCID"},
+		{"P R C A U", "profiles, regions, compartments, auth, users"},
+	}},
+	{title: "Compartments", mode: "compartments", keys: [][2]string{
+		{"s", "search every compartment in the tenancy"},
+		{"f", "pin or unpin as a tenancy favorite"},
+		{"y", "copy the compartment OCID"},
+		{"P R T A U", "profiles, regions, tenancies, auth, users"},
+	}},
+	{title: "Regions", mode: "regions", keys: [][2]string{
+		{"L", "probe latency and sort fastest-first"},
+		{"P C T A U", "profiles, compartments, tenancies, auth, users"},
+	}},
+}
+
+// renderHelpOverlay is the full-screen `?` help: every hotkey grouped by
+// mode, with the current mode's section highlighted.
+func (m tuiModel) renderHelpOverlay() string {
+	keyWidth := 0
+	for _, sec := range tuiHelpSections {
+		for _, k := range sec.keys {
+			keyWidth = max(keyWidth, lipgloss.Width(k[0]))
+		}
+	}
+	keyStyle := m.theme.metaValue.Width(keyWidth + 2)
 	lines := []string{
-		"Keys",
-		"Enter/right: drill or apply",
-		"Space: stage selection",
-		"Ctrl+S or q: save and quit",
-		"Esc or Ctrl+C: quit without saving",
-		"/: filter current list",
-		"v: toggle verbose view for current mode",
-		"m: toggle matrix layout for current session",
-		"Backspace/delete: go up/back (when not filtering)",
-		"?: toggle this help panel",
-		"",
-		"Mode Navigation",
-		"profiles: r regions • c compartments • t tenancies • a auth • u users",
-		"submenus: R regions • C compartments • T tenancies • A auth • U users • P profiles",
-	}
-	if m.width > 0 && m.width < 72 {
-		lines = []string{
-			"Keys: enter drill, space stage, q save, esc quit, / filter, ? help",
-			"Switch: r/c/t/a/u in profiles, R/C/T/A/U/P in submenus",
+		m.theme.headerTitle.Render("Keybindings") + m.theme.headerSubtle.Render("  (? or esc to close)"),
+	}
+	for _, sec := range tuiHelpSections {
+		title := m.theme.headerSubtle.Render(sec.title)
+		if sec.mode != "" && sec.mode == m.mode {
+			title = m.theme.tabActive.Render(sec.title)
+		}
+		lines = append(lines, "", title)
+		for _, k := range sec.keys {
+			lines = append(lines, "  "+keyStyle.Render(k[0])+m.theme.instructions.Render(k[1]))
 		}
 	}
-	return strings.Join(lines, "\n")
+	panel := m.theme.panel
+	if m.width > 0 {
+		panel = panel.Width(m.width - 2)
+	}
+	if m.height > 0 {
+		panel = panel.Height(m.height - 2)
+	}
+	return panel.Render(strings.Join(lines, "\n"))
 }
 
 func compactMetaNarrow(m tuiModel) string {
@@ -3067,40 +3577,380 @@ type compResultMsg struct {
 	parent string
 	items  []compItem
 	err    error
+	gen    int // loadGen of the load that produced this result
+}
+
+type subtreeResultMsg struct {
+	tenancy string
+	items   []compItem
+	err     error
+	gen     int // loadGen of the load that produced this result
+}
+
+// childCountMsg carries the subcompartment count of one child of parent so
+// its row can be annotated in place.
+type childCountMsg struct {
+	parent string
+	id     string
+	count  int
+}
+
+// tenancyNameMsg carries one resolved tenancy name so its row can be
+// retitled in place.
+type tenancyNameMsg struct {
+	ocid string
+	name string
+}
+
+// primeTenancyNamesCmd resolves tenancy names after Init so the TUI renders
+// without waiting on the network. It is nil with --no-resolve.
+func (m tuiModel) primeTenancyNamesCmd() tea.Cmd {
+	if m.noResolve {
+		return nil
+	}
+	return primeTenancyNames(ociContext(context.Background(), m.cfg), m.profiles, m.ociCfgPath, m.cfg.Options.IdentityConcurrencyLimit(), m.networkTimeout)
+}
+
+// loadChildCountsCmd counts the subcompartments of each listed child of parent
+// in the background, one single-page call per child, bounded by the identity
+// concurrency. Update caches the counts under parent and asks only once per
+// parent, so scrolling or returning to a level does not repeat the calls.
+// Failures post nothing.
+func (m tuiModel) loadChildCountsCmd(parent string, items []compItem) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+	selected := m.ctxItem.Context
+	ociCfg := m.ociConfigPath()
+	activeOnly := !m.showInactive
+	timeout := m.networkTimeout
+	base := ociContext(context.Background(), m.cfg)
+	sem := make(chan struct{}, m.cfg.Options.IdentityConcurrencyLimit())
+	cmds := make([]tea.Cmd, 0, len(items))
+	for _, it := range items {
+		id := it.oc.ID
+		cmds = append(cmds, func() tea.Msg {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			ctx, cancel := context.WithTimeout(base, timeout)
+			defer cancel()
+			n, err := countChildCompartments(ctx, ociCfg, selected.Profile, selected.Region, id, activeOnly, childCountLimit)
+			if err != nil {
+				return nil
+			}
+			return childCountMsg{parent: parent, id: id, count: n}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+type regionLatencyMsg struct {
+	ctxName   string // context whose regions were probed
+	regions   []string
+	latencies map[string]time.Duration
+}
+
+// regionLatencyTimeout caps the opt-in region latency probe.
+const regionLatencyTimeout = 5 * time.Second
+
+func loadRegionLatencyCmd(ctxName string, regions []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), regionLatencyTimeout)
+		defer cancel()
+		return regionLatencyMsg{ctxName: ctxName, regions: regions, latencies: probeRegionLatency(ctx, regions)}
+	}
 }
 
 type regionResultMsg struct {
 	ctxName string
 	items   []string
 	err     error
+	gen     int // loadGen of the load that produced this result
+}
+
+// tuiLevel is the part of the model a cancelled load restores.
+type tuiLevel struct {
+	mode        string
+	crumb       string
+	parentID    string
+	parentCrumb string
+	ctxItem     contextItem
+}
+
+func (m tuiModel) level() tuiLevel {
+	return tuiLevel{mode: m.mode, crumb: m.crumb, parentID: m.parentID, parentCrumb: m.parentCrumb, ctxItem: m.ctxItem}
 }
 
-func (m tuiModel) loadRegionsCmd(ctxItem contextItem) tea.Cmd {
+// beginLoad cancels any load still in flight and returns the context and
+// generation for the next one.
+func (m *tuiModel) beginLoad() (context.Context, int) {
+	if m.cancelLoad != nil {
+		m.cancelLoad()
+	}
+	ctx, cancel := context.WithCancel(ociContext(context.Background(), m.cfg))
+	m.cancelLoad = cancel
+	m.loadGen++
+	return ctx, m.loadGen
+}
+
+// finishLoad releases the current load's context once its result is in.
+func (m *tuiModel) finishLoad() {
+	if m.cancelLoad != nil {
+		m.cancelLoad()
+		m.cancelLoad = nil
+	}
+}
+
+// abandonLoad cancels the load in flight and returns to the level it was
+// started from. Its result, if one still arrives, is from an older
+// generation and is dropped.
+func (m tuiModel) abandonLoad() tuiModel {
+	m.finishLoad()
+	m.loadGen++
+	from := m.loadFrom
+	if from.mode == "" {
+		from.mode = "contexts"
+	}
+	m.mode, m.crumb = from.mode, from.crumb
+	m.parentID, m.parentCrumb = from.parentID, from.parentCrumb
+	m.ctxItem = from.ctxItem
+	m.status = "Load cancelled"
+	return m
+}
+
+// startRegionLoad loads ctxItem's subscribed regions with the status line
+// spinner running.
+func (m tuiModel) startRegionLoad(ctxItem contextItem) (tuiModel, tea.Cmd) {
+	m.status = "Loading regions..."
+	ctx, gen := m.beginLoad()
+	return m, tea.Batch(m.loadRegionsCmd(ctx, gen, ctxItem), m.spinner.Tick)
+}
+
+func (m tuiModel) loadRegionsCmd(ctx context.Context, gen int, ctxItem contextItem) tea.Cmd {
 	return func() tea.Msg {
-		c, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		c, cancel := context.WithTimeout(ctx, m.networkTimeout)
 		defer cancel()
-		regions, err := oci.ListRegionSubscriptions(c, m.cfg.Options.OCIConfigPath, ctxItem.Profile)
-		return regionResultMsg{ctxName: ctxItem.Name, items: regions, err: err}
+		regions, err := subscribedRegions(c, m.cfg, m.ociConfigPath(), ctxItem.Context, false)
+		return regionResultMsg{ctxName: ctxItem.Name, items: regions, err: err, gen: gen}
 	}
 }
 
-func (m tuiModel) loadCompsCmd(parent string) tea.Cmd {
+// startCompLoad loads parent's children with the status line spinner
+// running, replacing any load still in flight.
+func (m tuiModel) startCompLoad(parent string) (tuiModel, tea.Cmd) {
+	m.status = "Loading compartments..."
+	ctx, gen := m.beginLoad()
+	return m, tea.Batch(m.loadCompsCmd(ctx, gen, parent), m.spinner.Tick)
+}
+
+func (m tuiModel) loadCompsCmd(ctx context.Context, gen int, parent string) tea.Cmd {
 	return func() tea.Msg {
 		// if cached, return cached without call
 		if items, ok := m.compCache[parent]; ok {
-			return compResultMsg{parent: parent, items: items}
+			return compResultMsg{parent: parent, items: items, gen: gen}
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		c, cancel := context.WithTimeout(ctx, m.networkTimeout)
+		defer cancel()
+		citems, err := m.fetchChildren(c, parent)
+		return compResultMsg{parent: parent, items: citems, err: err, gen: gen}
+	}
+}
+
+// startSubtreeLoad scans tenancy's whole compartment subtree with the status
+// line spinner running, so esc/backspace can cancel it like any other load.
+func (m tuiModel) startSubtreeLoad(tenancy string) (tuiModel, tea.Cmd) {
+	m.status = "Searching compartment subtree..."
+	ctx, gen := m.beginLoad()
+	return m, tea.Batch(m.loadSubtreeCmd(ctx, gen, tenancy), m.spinner.Tick)
+}
+
+// loadSubtreeCmd lists every compartment under tenancy in one recursive call.
+func (m tuiModel) loadSubtreeCmd(ctx context.Context, gen int, tenancy string) tea.Cmd {
+	selected := m.ctxItem.Context
+	ociCfg := m.ociConfigPath()
+	// A whole-tenancy scan pages through many results, so it gets twice the per-call budget.
+	timeout := 2 * m.networkTimeout
+	return func() tea.Msg {
+		c, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
-		citems, err := m.fetchChildren(ctx, parent)
-		return compResultMsg{parent: parent, items: citems, err: err}
+		comps, err := fetchCompartmentSubtree(c, ociCfg, selected.Profile, selected.Region, tenancy)
+		if err != nil {
+			return subtreeResultMsg{tenancy: tenancy, err: err, gen: gen}
+		}
+		return subtreeResultMsg{tenancy: tenancy, items: compartmentSubtreeItems(comps), gen: gen}
+	}
+}
+
+// compartmentSubtreeItems converts a flat subtree listing into items titled with
+// their full name path (e.g. "apps/dev/web"), sorted by that path.
+func compartmentSubtreeItems(comps []oci.Compartment) []compItem {
+	byID := make(map[string]oci.Compartment, len(comps))
+	for _, c := range comps {
+		byID[c.ID] = c
+	}
+	items := make([]compItem, 0, len(comps))
+	for _, c := range comps {
+		parts := []string{c.Name}
+		seen := map[string]bool{c.ID: true}
+		for p, ok := byID[c.Parent]; ok && !seen[p.ID]; p, ok = byID[p.Parent] {
+			seen[p.ID] = true
+			parts = append([]string{p.Name}, parts...)
+		}
+		items = append(items, compItem{oc: c, path: strings.Join(parts, "/")})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].path < items[j].path })
+	return items
+}
+
+// showSubtreeSearch lists subtree results with the filter open so typing searches
+// by name. Parent and name maps are filled in so goUpOne works after a jump.
+func (m *tuiModel) showSubtreeSearch(tenancy string, items []compItem) {
+	if m.parentMap == nil {
+		m.parentMap = make(map[string]string)
+	}
+	if m.nameMap == nil {
+		m.nameMap = make(map[string]string)
+	}
+	for _, it := range items {
+		m.parentMap[it.oc.ID] = it.oc.Parent
+		m.nameMap[it.oc.ID] = it.oc.Name
+	}
+	items = m.visibleCompartments(items)
+	m.subtreeSearch = true
+	m.comps.SetItems(toList(items))
+	m.comps.Select(0)
+	m.comps.Title = fmt.Sprintf("Search compartments under %s", tenancy)
+	m.comps.SetFilteringEnabled(true)
+	m.comps.SetFilterText("")
+	m.comps.SetFilterState(list.Filtering)
+	m.comps.SetShowFilter(true)
+	m.status = fmt.Sprintf("Search %d compartments by name (Enter to jump)", len(items))
+}
+
+// compartmentListItems returns items for the compartment list, with the
+// tenancy's favorites pinned above the children of the tenancy root.
+func (m tuiModel) compartmentListItems(parent string, items []compItem) []list.Item {
+	items = m.visibleCompartments(items)
+	if counts := m.childCounts[parent]; len(counts) > 0 {
+		counted := make([]compItem, len(items))
+		for i, it := range items {
+			if n, ok := counts[it.oc.ID]; ok {
+				it.children, it.counted = n, true
+			}
+			counted[i] = it
+		}
+		items = counted
+	}
+	if parent == "" || parent != m.ctxItem.TenancyOCID {
+		return toList(items)
+	}
+	favs := m.cfg.Options.FavoriteCompartmentsFor(parent)
+	out := make([]compItem, 0, len(favs)+len(items))
+	for _, f := range favs {
+		out = append(out, compItem{
+			oc:       oci.Compartment{ID: f.ID, Name: f.Name, Parent: f.Parent, Status: "ACTIVE"},
+			favorite: true,
+		})
+	}
+	return toList(append(out, items...))
+}
+
+// visibleCompartments applies the --active-only filter to items.
+func (m tuiModel) visibleCompartments(items []compItem) []compItem {
+	if m.showInactive {
+		return items
 	}
+	return activeCompartments(items)
+}
+
+// toggleFavoriteCompartment pins or unpins the highlighted compartment for the
+// active tenancy. Favorites are saved right away (not at finalize), except in
+// dry runs where they last for the session.
+func (m tuiModel) toggleFavoriteCompartment() (tea.Model, tea.Cmd) {
+	item, ok := asCompItem(m.comps.SelectedItem())
+	tenancy := m.ctxItem.TenancyOCID
+	if !ok || tenancy == "" {
+		m.status = "Select a compartment to pin"
+		return m, nil
+	}
+	pinned := m.cfg.ToggleFavoriteCompartment(config.FavoriteCompartment{
+		Tenancy: tenancy,
+		ID:      item.oc.ID,
+		Name:    item.oc.Name,
+		Parent:  item.oc.Parent,
+	})
+	verb := "Unpinned"
+	if pinned {
+		verb = "Pinned"
+	}
+	if m.dryRun || m.cfgPath == "" {
+		m.status = fmt.Sprintf("%s %s (session only)", verb, item.oc.Name)
+	} else if err := saveFavoriteCompartments(m.cfgPath, m.cfg.Options.FavoriteCompartments); err != nil {
+		m.status = fmt.Sprintf("%s %s, but saving favorites failed: %v", verb, item.oc.Name, err)
+	} else {
+		m.status = fmt.Sprintf("%s %s", verb, item.oc.Name)
+	}
+	if !m.subtreeSearch && m.parentID == tenancy {
+		m.comps.SetItems(m.compartmentListItems(tenancy, m.compCache[tenancy]))
+	}
+	return m, nil
+}
+
+// copySelectedOCID copies the highlighted item's OCID: a profile's compartment
+// (or tenancy when unset), a tenancy, or a compartment.
+func (m tuiModel) copySelectedOCID() (tea.Model, tea.Cmd) {
+	item := m.activeListModel().SelectedItem()
+	if marked, ok := item.(markedItem); ok {
+		item = marked.base
+	}
+	var ocid string
+	switch it := item.(type) {
+	case contextItem:
+		ocid = it.CompartmentOCID
+		if ocid == "" {
+			ocid = it.TenancyOCID
+		}
+	case tenancyItem:
+		ocid = it.TenancyOCID
+	case compItem:
+		ocid = it.oc.ID
+	}
+	if ocid == "" {
+		m.status = "Nothing to copy"
+		return m, nil
+	}
+	if err := copyToClipboard(ocid); err != nil {
+		m.status = fmt.Sprintf("Copy failed: %v", err)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Copied %s to clipboard", abbreviateOCID(ocid))
+	return m, nil
+}
+
+// saveFavoriteCompartments writes only the favorites list into the config on
+// disk, leaving other pending TUI changes for finalize.
+func saveFavoriteCompartments(path string, favs []config.FavoriteCompartment) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	cfg.Options.FavoriteCompartments = favs
+	return config.Save(path, cfg)
+}
+
+// ociConfigPath returns the resolved OCI CLI config path, resolving it from
+// the config for models built without newTuiModel.
+func (m tuiModel) ociConfigPath() string {
+	if m.ociCfgPath != "" {
+		return m.ociCfgPath
+	}
+	return ociConfigOption(m.cfg)
 }
 
 func (m tuiModel) fetchChildren(ctx context.Context, parent string) ([]compItem, error) {
 	// use selected context's profile/region/tenancy
 	selected := m.ctxItem.Context
-	ociCfg := m.cfg.Options.OCIConfigPath
+	ociCfg := m.ociConfigPath()
 	children, err := oci.FetchCompartments(ctx, ociCfg, selected.Profile, selected.Region, parent)
 	if err != nil {
 		return nil, err
@@ -3115,6 +3965,16 @@ func (m tuiModel) fetchChildren(ctx context.Context, parent string) ([]compItem,
 
 // saveAndQuitCurrentMode consolidates save+exit behavior used by q and Ctrl+S.
 func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
+	staged, ok := m.stageSaveForCurrentMode()
+	if !ok {
+		return m, nil
+	}
+	return staged.finalizeSelection()
+}
+
+// stageSaveForCurrentMode applies the current mode's selection to ctxItem/parentID
+// without persisting. It reports false when the mode has nothing to save.
+func (m tuiModel) stageSaveForCurrentMode() (tuiModel, bool) {
 	if m.mode == "contexts" {
 		if item, ok := m.list.SelectedItem().(contextItem); ok {
 			prevCtxItem := m.ctxItem
@@ -3133,9 +3993,9 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 			}
 			m.parentID = parent
 			m.parentCrumb = parentLabel(parent, item)
-			return m.finalizeSelection()
+			return m, true
 		}
-		return m, nil
+		return m, false
 	}
 	if m.mode == "tenancies" {
 		if item, ok := m.tenancies.SelectedItem().(tenancyItem); ok {
@@ -3145,10 +4005,10 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 				m.ctxItem = contextItemForProfile(profileName, p)
 				m.parentID = item.TenancyOCID
 				m.parentCrumb = parentLabel(item.TenancyOCID, m.ctxItem)
-				return m.finalizeSelection()
+				return m, true
 			}
 		}
-		return m, nil
+		return m, false
 	}
 	if m.mode == "compartments" {
 		if m.pendingSelectionID != "" {
@@ -3157,7 +4017,7 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 				m.parentCrumb = m.pendingSelectionNm
 			}
 		}
-		return m.finalizeSelection()
+		return m, true
 	}
 	if m.mode == "regions" {
 		if item, ok := m.regions.SelectedItem().(regionItem); ok {
@@ -3171,7 +4031,7 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 				m.parentID = parent
 				m.parentCrumb = parentLabel(parent, m.ctxItem)
 			}
-			return m.finalizeSelection()
+			return m, true
 		}
 	}
 	if m.mode == "auth" {
@@ -3185,7 +4045,7 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 				m.parentID = parent
 				m.parentCrumb = parentLabel(parent, m.ctxItem)
 			}
-			return m.finalizeSelection()
+			return m, true
 		}
 	}
 	if m.mode == "users" {
@@ -3199,10 +4059,39 @@ func (m tuiModel) saveAndQuitCurrentMode() (tea.Model, tea.Cmd) {
 				m.parentID = parent
 				m.parentCrumb = parentLabel(parent, m.ctxItem)
 			}
-			return m.finalizeSelection()
+			return m, true
 		}
 	}
-	return m, nil
+	return m, false
+}
+
+// compartmentCrumb renders "Current: root / Eng / Platform (<ocid>)" for id by
+// walking parentMap up to the tenancy. Unknown names fall back to abbreviated OCIDs.
+func (m tuiModel) compartmentCrumb(id string) string {
+	return fmt.Sprintf("Current: %s (%s)", m.compartmentPath(id), id)
+}
+
+// compartmentPath joins the names from the tenancy root down to id.
+func (m tuiModel) compartmentPath(id string) string {
+	tenancy := m.ctxItem.TenancyOCID
+	var parts []string
+	seen := make(map[string]bool)
+	for cur := id; cur != "" && !seen[cur]; cur = m.parentMap[cur] {
+		seen[cur] = true
+		if cur == tenancy {
+			parts = append(parts, "root")
+			break
+		}
+		name := m.nameMap[cur]
+		if name == "" || name == cur {
+			name = abbreviateOCID(cur)
+		}
+		parts = append(parts, name)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " / ")
 }
 
 // parentLabel returns a friendly label for the current parent (root/tenancy fallback).
@@ -3216,20 +4105,14 @@ func parentLabel(parent string, item contextItem) string {
 // finalizeSelection sets the chosen compartment, saves config, and quits.
 func (m tuiModel) finalizeSelection() (tea.Model, tea.Cmd) {
 	m.finalized = true
-	// persist selection (compartment + region if set)
-	m.ctxItem.CompartmentOCID = m.parentID
-	if m.pendingAuthMethod != "" {
-		m.ctxItem.AuthMethod = config.NormalizeAuthMethod(m.pendingAuthMethod)
-	}
-	m.ctxItem.AuthMethod = config.NormalizeAuthMethod(m.ctxItem.AuthMethod)
-	if m.pendingUser != "" {
-		m.ctxItem.User = strings.TrimSpace(m.pendingUser)
+	m.applyPendingToContext()
+	if m.dryRun {
+		// Dry run: report the plan on exit without touching config or OCI defaults.
+		m.selected = ""
+		return m, tea.Quit
 	}
-	m.ctxItem.User = strings.TrimSpace(m.ctxItem.User)
-	m.maybeDeriveContextName()
-	m.selected = m.ctxItem.Name
 	// Region persisted by UpsertContext from ctxItem; regionSet already applied
-	m.cfg.CurrentContext = m.ctxItem.Name
+	m.cfg.SwitchContext(m.ctxItem.Name)
 	if err := m.cfg.UpsertContext(m.ctxItem.Context); err != nil {
 		m.err = err
 		return m, tea.Quit
@@ -3254,6 +4137,92 @@ func (m tuiModel) finalizeSelection() (tea.Model, tea.Cmd) {
 	return m, tea.Quit
 }
 
+// applyPendingToContext folds staged compartment/auth/user values into ctxItem
+// exactly as a save would, without persisting anything.
+func (m *tuiModel) applyPendingToContext() {
+	// persist selection (compartment + region if set)
+	m.ctxItem.SetCompartment(m.parentID, m.compartmentState(m.parentID))
+	if m.pendingAuthMethod != "" {
+		m.ctxItem.AuthMethod = config.NormalizeAuthMethod(m.pendingAuthMethod)
+	}
+	m.ctxItem.AuthMethod = config.NormalizeAuthMethod(m.ctxItem.AuthMethod)
+	if m.pendingUser != "" {
+		m.ctxItem.User = strings.TrimSpace(m.pendingUser)
+	}
+	m.ctxItem.User = strings.TrimSpace(m.ctxItem.User)
+	m.maybeDeriveContextName()
+	m.selected = m.ctxItem.Name
+}
+
+// compartmentState returns the lifecycle state of a compartment seen in this
+// session's listings, or "" when it was never listed (e.g. the tenancy root).
+func (m tuiModel) compartmentState(id string) string {
+	for _, cache := range []map[string][]compItem{m.compCache, m.subtreeCache} {
+		for _, items := range cache {
+			for _, it := range items {
+				if it.oc.ID == id {
+					return it.oc.Status
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// plannedSave returns the context a save from the current mode would write.
+func (m tuiModel) plannedSave() (config.Context, bool) {
+	staged, ok := m.stageSaveForCurrentMode()
+	if !ok {
+		return config.Context{}, false
+	}
+	staged.applyPendingToContext()
+	return staged.ctxItem.Context, true
+}
+
+// renderSavePreview describes the planned save as a field diff against what is stored.
+func (m tuiModel) renderSavePreview() string {
+	planned, ok := m.plannedSave()
+	if !ok {
+		return "Save preview\nNothing to save from this view."
+	}
+	return strings.Join(append([]string{"Save preview (not written until Ctrl+S/q)"}, savePreviewLines(m.cfg, planned)...), "\n")
+}
+
+// savePreviewLines lists changed fields between the stored context and planned.
+func savePreviewLines(cfg config.Config, planned config.Context) []string {
+	stored, err := cfg.GetContext(planned.Name)
+	isNew := err != nil
+	lines := []string{}
+	if isNew {
+		lines = append(lines, fmt.Sprintf("context: %s (new)", planned.Name))
+	} else {
+		lines = append(lines, fmt.Sprintf("context: %s", planned.Name))
+	}
+	diff := func(label, before, after string) {
+		if before == after {
+			return
+		}
+		if before == "" {
+			before = "-"
+		}
+		if after == "" {
+			after = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", label, before, after))
+	}
+	diff("current", cfg.CurrentContext, planned.Name)
+	diff("profile", stored.Profile, planned.Profile)
+	diff("auth", config.NormalizeAuthMethod(stored.AuthMethod), config.NormalizeAuthMethod(planned.AuthMethod))
+	diff("tenancy", stored.TenancyOCID, planned.TenancyOCID)
+	diff("compartment", stored.CompartmentOCID, planned.CompartmentOCID)
+	diff("region", stored.Region, planned.Region)
+	diff("user", stored.User, planned.User)
+	if len(lines) == 1 && !isNew {
+		lines = append(lines, "no changes")
+	}
+	return lines
+}
+
 func (m *tuiModel) maybeDeriveContextName() {
 	profileName := strings.TrimSpace(m.ctxItem.Profile)
 	if profileName == "" {
@@ -12,8 +12,15 @@ type tuiPrefs struct {
 	VerboseTenancies    bool `yaml:"verbose_tenancies"`
 	VerboseCompartments bool `yaml:"verbose_compartments"`
 	VerboseRegions      bool `yaml:"verbose_regions"`
+	// Density is tuiDensityNormal or tuiDensityUltra; "" means normal.
+	Density string `yaml:"density,omitempty"`
 }
 
+const (
+	tuiDensityNormal = "normal"
+	tuiDensityUltra  = "ultra"
+)
+
 func defaultTUIPrefs() tuiPrefs {
 	return tuiPrefs{
 		VerboseContexts:     true,
@@ -36,19 +43,35 @@ func loadTUIPrefs() (tuiPrefs, string, error) {
 	if err != nil {
 		return defaultTUIPrefs(), "", err
 	}
+	prefs, err := readTUIPrefs(path)
+	return prefs, path, err
+}
 
+// readTUIPrefs reads path over the defaults; a missing file is not an error.
+func readTUIPrefs(path string) (tuiPrefs, error) {
 	prefs := defaultTUIPrefs()
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return prefs, path, nil
+			return prefs, nil
 		}
-		return prefs, path, err
+		return prefs, err
 	}
 	if err := yaml.Unmarshal(data, &prefs); err != nil {
-		return defaultTUIPrefs(), path, err
+		return defaultTUIPrefs(), err
+	}
+	return prefs, nil
+}
+
+// saveTUIDensity records density in the prefs file at path, leaving the other
+// saved prefs as they are on disk rather than as toggled this session.
+func saveTUIDensity(path, density string) error {
+	prefs, err := readTUIPrefs(path)
+	if err != nil {
+		return err
 	}
-	return prefs, path, nil
+	prefs.Density = density
+	return saveTUIPrefs(path, prefs)
 }
 
 func saveTUIPrefs(path string, prefs tuiPrefs) error {
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectInStartMode makes the choice `tui [mode] --select value` asks for
+// without a terminal, the way a user would: it waits for the start mode's
+// first load, highlights or stages the matching row, and saves as Ctrl+S
+// does. Contexts match by name, tenancies by OCID or name, regions by name,
+// and compartments (children of the context's compartment) by OCID or name.
+// ok is false when nothing matches; a failed load is left in m.err.
+func (m tuiModel) selectInStartMode(value string) (tuiModel, bool) {
+	value = strings.TrimSpace(value)
+	if m.initCmd != nil {
+		if msg := m.initCmd(); msg != nil {
+			next, _ := m.Update(msg)
+			m = next.(tuiModel)
+			if m.err != nil {
+				return m, true
+			}
+		}
+	}
+	found := false
+	switch m.mode {
+	case "contexts":
+		for i, it := range m.list.Items() {
+			if ci, ok := it.(contextItem); ok && ci.Name == value {
+				m.list.Select(i)
+				found = true
+				break
+			}
+		}
+	case "tenancies":
+		for i, it := range m.tenancies.Items() {
+			if ti, ok := it.(tenancyItem); ok && (ti.TenancyOCID == value || (ti.Name != "" && strings.EqualFold(ti.Name, value))) {
+				m.tenancies.Select(i)
+				found = true
+				break
+			}
+		}
+	case "regions":
+		for i, it := range m.regions.Items() {
+			if ri, ok := it.(regionItem); ok && ri.name == value {
+				m.regions.Select(i)
+				found = true
+				break
+			}
+		}
+	case "compartments":
+		for _, ci := range m.visibleCompartments(m.compCache[m.parentID]) {
+			if ci.oc.ID == value || ci.oc.Name == value {
+				m.pendingSelectionID = ci.oc.ID
+				m.pendingSelectionNm = ci.oc.Name
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return m, false
+	}
+	next, _ := m.saveAndQuitCurrentMode()
+	return next.(tuiModel), true
+}
+
+// noSelectMatch describes a --select value that matched nothing.
+func noSelectMatch(mode, value string) string {
+	return fmt.Sprintf("--select %q matched nothing in %s mode", value, mode)
+}
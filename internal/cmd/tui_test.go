@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/adrianmross/oci-context/pkg/config"
 	"github.com/adrianmross/oci-context/pkg/oci"
@@ -383,6 +388,26 @@ func TestTUIQFromContextsUsesStagedCompartmentSelection(t *testing.T) {
 	}
 }
 
+func TestTUISaveRecordsCompartmentState(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}, Contexts: []config.Context{ci.Context}}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.ctxItem = ci
+	m.compCache[ci.TenancyOCID] = []compItem{{oc: oci.Compartment{ID: "ocid1.compartment.oc1..gone", Name: "Gone", Status: "DELETED"}}}
+
+	m.parentID = "ocid1.compartment.oc1..gone"
+	m.applyPendingToContext()
+	if got := m.ctxItem.CompartmentState; got != "DELETED" {
+		t.Fatalf("expected DELETED recorded, got %q", got)
+	}
+
+	m.parentID = ci.TenancyOCID
+	m.applyPendingToContext()
+	if got := m.ctxItem.CompartmentState; got != "" {
+		t.Fatalf("expected unknown state for the tenancy root, got %q", got)
+	}
+}
+
 func TestTUISpaceStagesRegion(t *testing.T) {
 	ci := newTestContextItem()
 	cfg := config.Config{
@@ -430,6 +455,37 @@ func TestTUISpaceTogglesUnstageCompartment(t *testing.T) {
 	}
 }
 
+// runTeaCmd runs cmd, expanding batches concurrently the way bubbletea does,
+// and returns the non-nil messages.
+func runTeaCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		if msg == nil {
+			return nil
+		}
+		return []tea.Msg{msg}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var out []tea.Msg
+	for _, c := range batch {
+		wg.Add(1)
+		go func(c tea.Cmd) {
+			defer wg.Done()
+			msgs := runTeaCmd(c)
+			mu.Lock()
+			out = append(out, msgs...)
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+	return out
+}
+
 func TestPrimeTenancyNamesCachesFriendlyNames(t *testing.T) {
 	resetTenancyCache()
 	orig := fetchIdentityDetails
@@ -442,7 +498,10 @@ func TestPrimeTenancyNamesCachesFriendlyNames(t *testing.T) {
 	profiles := map[string]ocicfg.Profile{
 		"DEFAULT": {Tenancy: "ocid1.tenancy.oc1..xyz", Region: "us-phoenix-1", User: "ocid1.user.oc1..user"},
 	}
-	primeTenancyNames(context.Background(), profiles, "/tmp/oci")
+	msgs := runTeaCmd(primeTenancyNames(context.Background(), profiles, "/tmp/oci", 0, time.Second))
+	if len(msgs) != 1 || msgs[0] != (tenancyNameMsg{ocid: "ocid1.tenancy.oc1..xyz", name: "My Tenancy"}) {
+		t.Fatalf("expected one tenancyNameMsg, got %#v", msgs)
+	}
 
 	if got := lookupTenancyName("ocid1.tenancy.oc1..xyz"); got != "My Tenancy" {
 		t.Fatalf("expected cached tenancy name, got %q", got)
@@ -461,6 +520,84 @@ func TestPrimeTenancyNamesCachesFriendlyNames(t *testing.T) {
 	}
 }
 
+func TestTUIResolvesTenancyNamesInBackground(t *testing.T) {
+	resetTenancyCache()
+	orig := fetchIdentityDetails
+	defer func() { fetchIdentityDetails = orig }()
+	calls := 0
+	fetchIdentityDetails = func(ctx context.Context, cfgPath, profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		calls++
+		return oci.IdentityDetails{TenancyName: "My Tenancy", TenancyOCID: tenancyOCID}, nil
+	}
+
+	profiles := map[string]ocicfg.Profile{
+		"DEFAULT": {Tenancy: "ocid1.tenancy.oc1..xyz", Region: "us-phoenix-1"},
+	}
+	cfg := config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}}
+	m := newTuiModel(cfg, "", profileMenuItems(cfg, profiles, nil), profiles, "")
+	if calls != 0 {
+		t.Fatalf("expected no identity calls while building the model, got %d", calls)
+	}
+	if got := m.tenancies.Items()[0].(tenancyItem).Title(); got != abbreviateOCID("ocid1.tenancy.oc1..xyz") {
+		t.Fatalf("expected abbreviated OCID before names arrive, got %q", got)
+	}
+
+	msgs := runTeaCmd(m.primeTenancyNamesCmd())
+	if len(msgs) != 1 {
+		t.Fatalf("expected one message per tenancy, got %#v", msgs)
+	}
+	model, _ := m.Update(msgs[0])
+	if got := model.(tuiModel).tenancies.Items()[0].(tenancyItem).Title(); got != "My Tenancy" {
+		t.Fatalf("expected title updated to friendly name, got %q", got)
+	}
+
+	m.noResolve = true
+	if m.primeTenancyNamesCmd() != nil {
+		t.Fatalf("expected no lookup command with --no-resolve")
+	}
+}
+
+func TestPrimeTenancyNamesHonorsConcurrencyLimit(t *testing.T) {
+	resetTenancyCache()
+	orig := fetchIdentityDetails
+	defer func() { fetchIdentityDetails = orig }()
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	fetchIdentityDetails = func(ctx context.Context, cfgPath, profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return oci.IdentityDetails{TenancyName: "name-" + profile, TenancyOCID: tenancyOCID}, nil
+	}
+
+	profiles := map[string]ocicfg.Profile{}
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("P%d", i)
+		profiles[name] = ocicfg.Profile{Tenancy: "ocid1.tenancy.oc1..t" + name, Region: "us-phoenix-1"}
+	}
+	if msgs := runTeaCmd(primeTenancyNames(context.Background(), profiles, "/tmp/oci", 2, time.Second)); len(msgs) != 8 {
+		t.Fatalf("expected 8 tenancy name messages, got %d", len(msgs))
+	}
+
+	if peak > 2 {
+		t.Fatalf("expected at most 2 concurrent fetches, saw %d", peak)
+	}
+	if peak == 0 {
+		t.Fatalf("expected fetches to run")
+	}
+	if got := lookupTenancyName("ocid1.tenancy.oc1..tP3"); got != "name-P3" {
+		t.Fatalf("expected cached name for P3, got %q", got)
+	}
+}
+
 func TestProfileMenuItemsHidesContextDuplicatesOfProfiles(t *testing.T) {
 	profiles := map[string]ocicfg.Profile{
 		"DEFAULT": {
@@ -1110,3 +1247,750 @@ func TestTUIBackspaceAtCompartmentRootReturnsToTenancies(t *testing.T) {
 		t.Fatalf("expected to return to tenancies from root, got %s", res.mode)
 	}
 }
+
+func TestTUIHelpOverlayIsModalAndKeepsStagedSelection(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.width, m.height = 100, 40
+	m.mode = "regions"
+	m.regions.SetItems(toRegionList([]string{"us-phoenix-1", "us-ashburn-1"}))
+	m.regions.Select(1)
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	res := model.(tuiModel)
+
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	res = model.(tuiModel)
+	view := res.View()
+	for _, want := range []string{"Keybindings", "Profiles", "Tenancies", "Compartments", "Regions", "probe latency", "next / previous mode"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in help overlay, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "us-ashburn-1") {
+		t.Fatalf("expected the overlay to replace the list, got:\n%s", view)
+	}
+
+	// Keys other than ?/esc are swallowed; esc closes instead of quitting.
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeySpace}, {Type: tea.KeyEsc}} {
+		var cmd tea.Cmd
+		model, cmd = res.Update(key)
+		res = model.(tuiModel)
+		if cmd != nil {
+			t.Fatalf("%s: expected no command while the overlay is open", key)
+		}
+	}
+	if res.helpVisible || res.mode != "regions" || res.pendingRegion != "us-ashburn-1" || res.finalized {
+		t.Fatalf("expected to return to regions with the staged region: help=%v mode=%s pending=%q", res.helpVisible, res.mode, res.pendingRegion)
+	}
+
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if res = model.(tuiModel); res.helpVisible || !strings.Contains(res.View(), "us-ashburn-1") {
+		t.Fatalf("expected ? to toggle the overlay off")
+	}
+}
+
+func TestTUIDensityToggleIsRemembered(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path, err := tuiPrefsPath()
+	if err != nil {
+		t.Fatalf("prefs path: %v", err)
+	}
+	if err := saveTUIPrefs(path, tuiPrefs{VerboseContexts: true, VerboseCompartments: true}); err != nil {
+		t.Fatalf("seed prefs: %v", err)
+	}
+	ci := newTestContextItem()
+	cfg := config.Config{Contexts: []config.Context{ci.Context}}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	if m.ultraCompact {
+		t.Fatalf("expected normal density without a saved preference")
+	}
+	m.prefs.VerboseContexts = false // a session-only toggle
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if res := model.(tuiModel); !res.ultraCompact || res.status != "Density: ultra" {
+		t.Fatalf("expected z to switch to ultra, got ultra=%v status=%q", res.ultraCompact, res.status)
+	}
+	saved, err := readTUIPrefs(path)
+	if err != nil {
+		t.Fatalf("read prefs: %v", err)
+	}
+	if saved.Density != tuiDensityUltra || !saved.VerboseCompartments || !saved.VerboseContexts {
+		t.Fatalf("expected only density saved over the on-disk prefs, got %+v", saved)
+	}
+	if !newTuiModel(cfg, "", []list.Item{ci}, nil, "").ultraCompact {
+		t.Fatalf("expected the next session to start in ultra density")
+	}
+
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if model.(tuiModel).ultraCompact || newTuiModel(cfg, "", []list.Item{ci}, nil, "").ultraCompact {
+		t.Fatalf("expected z to switch back to normal and remember it")
+	}
+}
+
+func TestTUISavePreviewShowsDiffWithoutPersisting(t *testing.T) {
+	base := newTestContextItem()
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Options:        config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts:       []config.Context{base.Context},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := newTuiModel(cfg, cfgPath, []list.Item{base}, nil, "")
+	m.mode = "regions"
+	m.ctxItem = base
+	m.parentID = base.TenancyOCID
+	m.regions.SetItems(toRegionList([]string{"us-phoenix-1", "us-ashburn-1"}))
+	m.regions.Select(1)
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	res := model.(tuiModel)
+	if !res.previewVisible {
+		t.Fatalf("expected preview overlay to be visible")
+	}
+	view := res.View()
+	if !strings.Contains(view, "region: us-phoenix-1 -> us-ashburn-1") {
+		t.Fatalf("expected region diff in preview, got:\n%s", view)
+	}
+	if res.finalized {
+		t.Fatalf("preview must not finalize")
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.Contexts[0].Region != "us-phoenix-1" {
+		t.Fatalf("preview persisted changes: %+v", loaded.Contexts[0])
+	}
+
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	res = model.(tuiModel)
+	if !res.finalized {
+		t.Fatalf("expected ctrl+s to save after preview")
+	}
+	loaded, err = config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.Contexts[0].Region != "us-ashburn-1" {
+		t.Fatalf("expected saved region after ctrl+s, got %+v", loaded.Contexts[0])
+	}
+}
+
+func TestTUIDryRunFinalizeDoesNotPersist(t *testing.T) {
+	base := newTestContextItem()
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{base.Context},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := newTuiModel(cfg, cfgPath, []list.Item{base}, nil, "")
+	m.dryRun = true
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	res := model.(tuiModel)
+	if !res.finalized || res.selected != "" {
+		t.Fatalf("expected dry-run finalize without selection, got finalized=%t selected=%q", res.finalized, res.selected)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.CurrentContext != "" {
+		t.Fatalf("dry run persisted current context %q", loaded.CurrentContext)
+	}
+}
+
+func TestTUISubtreeSearchJumpsAndKeepsParentChain(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	calls := 0
+	orig := fetchCompartmentSubtree
+	t.Cleanup(func() { fetchCompartmentSubtree = orig })
+	fetchCompartmentSubtree = func(ctx context.Context, profileConfigPath, profile, region, tenancyID string) ([]oci.Compartment, error) {
+		calls++
+		if tenancyID != ci.TenancyOCID {
+			t.Fatalf("expected subtree scan under tenancy, got %s", tenancyID)
+		}
+		return []oci.Compartment{
+			{ID: "ocid1.compartment.oc1..web", Name: "web", Parent: "ocid1.compartment.oc1..dev", Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: ci.TenancyOCID, Status: "ACTIVE"},
+			{ID: "ocid1.compartment.oc1..dev", Name: "dev", Parent: "ocid1.compartment.oc1..apps", Status: "ACTIVE"},
+		}, nil
+	}
+
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	m.parentID = ci.TenancyOCID
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if cmd == nil {
+		t.Fatalf("expected subtree load command")
+	}
+	model, _ = model.(tuiModel).Update(cmd().(tea.BatchMsg)[0]())
+	res := model.(tuiModel)
+	if !res.subtreeSearch || res.comps.FilterState() != list.Filtering {
+		t.Fatalf("expected subtree search with filter open, got search=%v filter=%v", res.subtreeSearch, res.comps.FilterState())
+	}
+	var titles []string
+	for _, it := range res.comps.Items() {
+		titles = append(titles, it.(compItem).Title())
+	}
+	if got := strings.Join(titles, ","); got != "apps,apps/dev,apps/dev/web" {
+		t.Fatalf("unexpected subtree titles: %s", got)
+	}
+
+	res.comps.SetFilterState(list.Unfiltered)
+	res.comps.Select(2)
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	res = model.(tuiModel)
+	if res.parentID != "ocid1.compartment.oc1..web" || res.subtreeSearch {
+		t.Fatalf("expected jump to web, got parent=%s search=%v", res.parentID, res.subtreeSearch)
+	}
+
+	// Backspace only goes up once web's children have loaded.
+	model, _ = res.Update(compResultMsg{parent: res.parentID, gen: res.loadGen})
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	res = model.(tuiModel)
+	if res.parentID != "ocid1.compartment.oc1..dev" || res.parentCrumb != "dev" {
+		t.Fatalf("expected goUpOne to reach dev, got %s (%s)", res.parentID, res.parentCrumb)
+	}
+
+	model, cmd = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if cmd != nil || calls != 1 {
+		t.Fatalf("expected cached subtree on second search, calls=%d", calls)
+	}
+	if len(model.(tuiModel).comps.Items()) != 3 {
+		t.Fatalf("expected cached subtree items")
+	}
+}
+
+func TestTUISubtreeResultDroppedAfterLeavingTenancy(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	orig := fetchCompartmentSubtree
+	t.Cleanup(func() { fetchCompartmentSubtree = orig })
+	fetchCompartmentSubtree = func(ctx context.Context, _, _, _, tenancyID string) ([]oci.Compartment, error) {
+		return []oci.Compartment{{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: tenancyID, Status: "ACTIVE"}}, nil
+	}
+
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	m.parentID = ci.TenancyOCID
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	res := model.(tuiModel)
+	if res.cancelLoad == nil {
+		t.Fatalf("expected the subtree scan to run as a cancellable load")
+	}
+	result := cmd().(tea.BatchMsg)[0]()
+
+	// Another tenancy's context is open by the time the scan finishes.
+	other := ci
+	other.TenancyOCID = "ocid1.tenancy.oc1..other"
+	res.ctxItem = other
+	res.parentID = other.TenancyOCID
+	model, _ = res.Update(result)
+	if res = model.(tuiModel); res.subtreeSearch || len(res.comps.Items()) != 0 {
+		t.Fatalf("expected the other tenancy's subtree to be dropped, got %d items", len(res.comps.Items()))
+	}
+
+	// esc cancels a scan in flight and its late result is ignored.
+	res.ctxItem = ci
+	res.parentID = ci.TenancyOCID
+	model, cmd = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	result = cmd().(tea.BatchMsg)[0]()
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	res = model.(tuiModel)
+	if res.cancelLoad != nil || res.status != "Load cancelled" {
+		t.Fatalf("expected esc to cancel the scan, status=%q", res.status)
+	}
+	model, _ = res.Update(result)
+	if res = model.(tuiModel); res.subtreeSearch {
+		t.Fatalf("expected the cancelled scan's result to be dropped")
+	}
+}
+
+func TestTUICopySelectedOCID(t *testing.T) {
+	var copied []string
+	orig := copyToClipboard
+	copyToClipboard = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	defer func() { copyToClipboard = orig }()
+
+	ci := newTestContextItem()
+	cfg := config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}, Contexts: []config.Context{ci.Context}}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	press := func(m tuiModel) tuiModel {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		return model.(tuiModel)
+	}
+
+	res := press(m)
+	if len(copied) != 1 || copied[0] != ci.TenancyOCID {
+		t.Fatalf("expected profile without a compartment to copy its tenancy, got %v", copied)
+	}
+	if want := "Copied " + abbreviateOCID(ci.TenancyOCID) + " to clipboard"; res.status != want {
+		t.Fatalf("status = %q, want %q", res.status, want)
+	}
+
+	m.mode = "compartments"
+	m.ctxItem = ci
+	m.parentID = ci.TenancyOCID
+	model, _ := m.Update(compResultMsg{parent: ci.TenancyOCID, items: []compItem{
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+	}})
+	press(model.(tuiModel))
+	if len(copied) != 2 || copied[1] != "ocid1.compartment.oc1..apps" {
+		t.Fatalf("expected compartment copied, got %v", copied)
+	}
+
+	copyToClipboard = func(string) error { return errors.New("no clipboard utility found") }
+	res = press(model.(tuiModel))
+	if !strings.Contains(res.status, "Copy failed: no clipboard utility found") {
+		t.Fatalf("expected graceful failure status, got %q", res.status)
+	}
+}
+
+func TestTUIHidesInactiveCompartmentsUnlessShowInactive(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}, Contexts: []config.Context{ci.Context}}
+	items := []compItem{
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..old", Name: "old", Parent: ci.TenancyOCID, Status: "DELETED"}},
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+	}
+	for _, tc := range []struct {
+		showInactive bool
+		want         int
+	}{{false, 1}, {true, 2}} {
+		m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+		m.showInactive = tc.showInactive
+		m.mode = "compartments"
+		m.ctxItem = ci
+		model, _ := m.Update(compResultMsg{parent: ci.TenancyOCID, items: items})
+		res := model.(tuiModel)
+		if got := len(res.comps.Items()); got != tc.want {
+			t.Fatalf("showInactive=%v: expected %d compartments listed, got %d", tc.showInactive, tc.want, got)
+		}
+		if len(res.compCache[ci.TenancyOCID]) != 2 {
+			t.Fatalf("expected the cache to keep every compartment, got %+v", res.compCache[ci.TenancyOCID])
+		}
+		res.showSubtreeSearch(ci.TenancyOCID, items)
+		if got := len(res.comps.Items()); got != tc.want {
+			t.Fatalf("showInactive=%v: expected %d search results, got %d", tc.showInactive, tc.want, got)
+		}
+	}
+}
+
+func TestTUIAnnotatesSubcompartmentCounts(t *testing.T) {
+	calls := 0
+	orig := countChildCompartments
+	countChildCompartments = func(_ context.Context, _, _, _, parent string, activeOnly bool, limit int) (int, error) {
+		calls++
+		if !activeOnly || limit != childCountLimit {
+			t.Errorf("unexpected count call activeOnly=%v limit=%d", activeOnly, limit)
+		}
+		if parent == "ocid1.compartment.oc1..apps" {
+			return 3, nil
+		}
+		return 0, nil
+	}
+	defer func() { countChildCompartments = orig }()
+
+	ci := newTestContextItem()
+	cfg := config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}, Contexts: []config.Context{ci.Context}}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	res := compResultMsg{parent: ci.TenancyOCID, items: []compItem{
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..ops", Name: "ops", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+	}}
+	model, cmd := m.Update(res)
+	if cmd == nil {
+		t.Fatalf("expected a background count command")
+	}
+	if strings.Contains(model.(tuiModel).comps.Items()[0].(compItem).Description(), "(") {
+		t.Fatalf("expected no count before results arrive")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("expected a batch of count commands")
+	}
+	for _, c := range batch {
+		model, _ = model.(tuiModel).Update(c())
+	}
+	items := model.(tuiModel).comps.Items()
+	if got := items[0].(compItem).Description(); got != "ocid1.compartment.oc1..apps  (3 subcompartments)" {
+		t.Fatalf("apps description = %q", got)
+	}
+	if got := items[1].(compItem).Description(); got != "ocid1.compartment.oc1..ops  (leaf)" {
+		t.Fatalf("ops description = %q", got)
+	}
+
+	// Returning to the level reuses the cached counts without new calls.
+	model, cmd = model.(tuiModel).Update(res)
+	if cmd != nil || calls != 2 {
+		t.Fatalf("expected cached counts, got cmd=%v after %d calls", cmd != nil, calls)
+	}
+	if got := model.(tuiModel).comps.Items()[1].(compItem).Description(); !strings.HasSuffix(got, "(leaf)") {
+		t.Fatalf("expected cached leaf label, got %q", got)
+	}
+}
+
+func TestTUIFavoriteCompartmentsPinnedAtTenancyRoot(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := newTuiModel(cfg, cfgPath, []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	m.parentID = ci.TenancyOCID
+	children := []compItem{
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..apps", Name: "apps", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..ops", Name: "ops", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+	}
+	model, _ := m.Update(compResultMsg{parent: ci.TenancyOCID, items: children})
+	res := model.(tuiModel)
+	res.comps.Select(1)
+
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	res = model.(tuiModel)
+	var titles []string
+	for _, it := range res.comps.Items() {
+		titles = append(titles, it.(compItem).Title())
+	}
+	if got := strings.Join(titles, ","); got != "★ ops,apps,ops" {
+		t.Fatalf("expected favorite pinned first, got %s", got)
+	}
+	saved, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if favs := saved.Options.FavoriteCompartmentsFor(ci.TenancyOCID); len(favs) != 1 || favs[0].ID != "ocid1.compartment.oc1..ops" {
+		t.Fatalf("expected ops saved as favorite, got %+v", saved.Options.FavoriteCompartments)
+	}
+
+	res.comps.Select(0)
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	res = model.(tuiModel)
+	if res.parentID != "ocid1.compartment.oc1..ops" || res.parentMap[res.parentID] != ci.TenancyOCID {
+		t.Fatalf("expected jump to ops under tenancy, got parent=%s up=%s", res.parentID, res.parentMap[res.parentID])
+	}
+
+	other := m
+	other.ctxItem.TenancyOCID = "ocid1.tenancy.oc1..other"
+	other.cfg = res.cfg
+	if items := other.compartmentListItems(other.ctxItem.TenancyOCID, nil); len(items) != 0 {
+		t.Fatalf("favorites must not leak across tenancies, got %d items", len(items))
+	}
+}
+
+func TestTUICompartmentCrumbShowsAncestry(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	eng := "ocid1.compartment.oc1..eng"
+	platform := "ocid1.compartment.oc1..platformxxxxxxxx"
+	m.parentMap[eng] = ci.TenancyOCID
+	m.nameMap[eng] = "Eng"
+	m.parentMap[platform] = eng
+	m.parentID = platform
+	team := compItem{oc: oci.Compartment{ID: "ocid1.compartment.oc1..team", Name: "Team-A", Parent: platform, Status: "ACTIVE"}}
+	m.comps.SetItems([]list.Item{team})
+	m.comps.Select(0)
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	res := model.(tuiModel)
+	want := "Current: root / Eng / ocid1.…xxxxxx / Team-A (ocid1.compartment.oc1..team)"
+	if res.crumb != want {
+		t.Fatalf("unexpected crumb after drill\nwant: %q\ngot:  %q", want, res.crumb)
+	}
+
+	model, _ = res.Update(compResultMsg{parent: res.parentID, gen: res.loadGen})
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	res = model.(tuiModel)
+	want = "Current: root / Eng / ocid1.…xxxxxx (" + platform + ")"
+	if res.crumb != want {
+		t.Fatalf("unexpected crumb after goUpOne\nwant: %q\ngot:  %q", want, res.crumb)
+	}
+}
+
+func TestTUIRegionLatencySortsFastestFirst(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	orig := probeRegionLatency
+	t.Cleanup(func() { probeRegionLatency = orig })
+	probeRegionLatency = func(ctx context.Context, regions []string) map[string]time.Duration {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatalf("expected latency probe to run with a timeout")
+		}
+		return map[string]time.Duration{
+			"us-ashburn-1":   80 * time.Millisecond,
+			"us-phoenix-1":   20 * time.Millisecond,
+			"eu-frankfurt-1": oci.RegionUnreachable,
+		}
+	}
+
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "regions"
+	m.ctxItem = ci
+	m.regions.SetItems(toRegionList([]string{"eu-frankfurt-1", "us-ashburn-1", "us-phoenix-1"}))
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if cmd == nil {
+		t.Fatalf("expected latency probe command")
+	}
+	model, _ = model.(tuiModel).Update(cmd())
+	res := model.(tuiModel)
+	var titles []string
+	for _, it := range res.regions.Items() {
+		titles = append(titles, it.(regionItem).Title())
+	}
+	want := "us-phoenix-1  20ms,us-ashburn-1  80ms,eu-frankfurt-1  (unreachable)"
+	if got := strings.Join(titles, ","); got != want {
+		t.Fatalf("unexpected region order\nwant: %s\ngot:  %s", want, got)
+	}
+	if !strings.Contains(res.status, "1 unreachable") {
+		t.Fatalf("unexpected status: %q", res.status)
+	}
+
+	// A probe that finishes after a switch to another context is dropped.
+	model, cmd = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	late := cmd()
+	res = model.(tuiModel)
+	other := ci
+	other.Name = "other"
+	res.ctxItem = other
+	res.regions.SetItems(toRegionList([]string{"ap-tokyo-1"}))
+	model, _ = res.Update(late)
+	if items := model.(tuiModel).regions.Items(); len(items) != 1 || items[0].(regionItem).Title() != "ap-tokyo-1" {
+		t.Fatalf("expected the other context's regions to be kept, got %v", items)
+	}
+}
+
+func TestTUISelectSavesWithoutTheTUI(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	prev := listRegionSubscriptions
+	listRegionSubscriptions = func(context.Context, string, string) ([]string, error) {
+		return []string{"us-phoenix-1", "us-ashburn-1"}, nil
+	}
+	t.Cleanup(func() { listRegionSubscriptions = prev })
+
+	ci := newTestContextItem()
+	cfgPath := filepath.Join(tmp, "config.yml")
+	cfg := config.Config{
+		Options:        config.Options{OCIConfigPath: filepath.Join(tmp, "oci-config")},
+		Contexts:       []config.Context{ci.Context},
+		CurrentContext: ci.Name,
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		root := newRootCmd()
+		out := &bytes.Buffer{}
+		root.SetOut(out)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(append(args, "--config", cfgPath))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("tui", "regions", "--select", "eu-frankfurt-1", "--strict"); err == nil || !strings.Contains(err.Error(), `--select "eu-frankfurt-1" matched nothing in regions mode`) {
+		t.Fatalf("expected --strict to reject an unknown region, got %v", err)
+	}
+	out, err := run("tui", "regions", "--select", "us-ashburn-1")
+	if err != nil {
+		t.Fatalf("tui --select: %v", err)
+	}
+	if out != "Switched to context dev\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	saved, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got, _ := saved.GetContext("dev"); got.Region != "us-ashburn-1" {
+		t.Fatalf("expected the selected region saved, got %q", got.Region)
+	}
+}
+
+func TestTUISelectStagesCompartmentByName(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:        config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts:       []config.Context{ci.Context},
+		CurrentContext: ci.Name,
+	}
+	m := newTuiModel(cfg, filepath.Join(t.TempDir(), "config.yml"), []list.Item{ci}, nil, "compartments")
+	m.compCache[ci.TenancyOCID] = []compItem{
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..app", Name: "app", Parent: ci.TenancyOCID, Status: "ACTIVE"}},
+		{oc: oci.Compartment{ID: "ocid1.compartment.oc1..gone", Name: "gone", Parent: ci.TenancyOCID, Status: "DELETED"}},
+	}
+
+	if _, ok := m.selectInStartMode("gone"); ok {
+		t.Fatalf("expected a hidden inactive compartment not to match")
+	}
+	res, ok := m.selectInStartMode("app")
+	if !ok || !res.finalized || res.ctxItem.CompartmentOCID != "ocid1.compartment.oc1..app" {
+		t.Fatalf("expected app to be saved, ok=%v finalized=%v compartment=%q", ok, res.finalized, res.ctxItem.CompartmentOCID)
+	}
+}
+
+func TestTUISpinnerRunsWhileCompartmentsLoad(t *testing.T) {
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.mode = "compartments"
+	m.ctxItem = ci
+	child := compItem{oc: oci.Compartment{ID: "ocid1.compartment.oc1..child", Name: "child", Parent: ci.TenancyOCID, Status: "ACTIVE"}}
+	m.comps.SetItems([]list.Item{child})
+	m.comps.Select(0)
+
+	// The load command itself is not run: it would call OCI.
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	res := model.(tuiModel)
+	if cmd == nil || res.cancelLoad == nil {
+		t.Fatalf("expected a pending load")
+	}
+	first := res.renderStatusLine()
+	if !strings.Contains(first, res.spinner.View()+" Loading compartments...") {
+		t.Fatalf("expected the spinner in the status line, got %q", first)
+	}
+	model, cmd = res.Update(res.spinner.Tick())
+	res = model.(tuiModel)
+	if cmd == nil || res.renderStatusLine() == first {
+		t.Fatalf("expected a tick to advance the spinner and schedule the next frame")
+	}
+
+	res.comps.SetFilteringEnabled(true)
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	model, _ = model.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	res = model.(tuiModel)
+	if res.mode != "compartments" || res.comps.FilterState() != list.Filtering || res.comps.FilterValue() != "t" {
+		t.Fatalf("expected filtering to work during a load: mode=%s state=%v value=%q", res.mode, res.comps.FilterState(), res.comps.FilterValue())
+	}
+	model, _ = res.Update(res.spinner.Tick())
+	if res = model.(tuiModel); res.comps.FilterValue() != "t" {
+		t.Fatalf("expected a tick to leave the filter alone, got %q", res.comps.FilterValue())
+	}
+
+	model, _ = res.Update(compResultMsg{parent: child.oc.ID, gen: res.loadGen})
+	res = model.(tuiModel)
+	if res.cancelLoad != nil || strings.Contains(res.renderStatusLine(), res.spinner.View()) {
+		t.Fatalf("expected the result to stop the spinner, status=%q", res.renderStatusLine())
+	}
+	if _, cmd = res.Update(res.spinner.Tick()); cmd != nil {
+		t.Fatalf("expected no further frames once loading is done")
+	}
+}
+
+func TestTUIEscCancelsInFlightLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	prev := listRegionSubscriptions
+	t.Cleanup(func() { listRegionSubscriptions = prev })
+	listRegionSubscriptions = func(ctx context.Context, _, _ string) ([]string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	ci := newTestContextItem()
+	cfg := config.Config{
+		Options:  config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{ci.Context},
+	}
+	m := newTuiModel(cfg, "", []list.Item{ci}, nil, "")
+	m.list.Select(0)
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	res := model.(tuiModel)
+	if res.mode != "regions" || res.cancelLoad == nil {
+		t.Fatalf("expected a region load in flight, mode=%s", res.mode)
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd().(tea.BatchMsg)[0]() }()
+
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	res = model.(tuiModel)
+	if res.mode != "contexts" || res.cancelLoad != nil || res.status != "Load cancelled" {
+		t.Fatalf("expected esc to cancel and go back, mode=%s status=%q", res.mode, res.status)
+	}
+	var late tea.Msg
+	select {
+	case late = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the region lookup to be cancelled")
+	}
+	model, _ = res.Update(late)
+	if res = model.(tuiModel); res.mode != "contexts" || res.status != "Load cancelled" || res.err != nil {
+		t.Fatalf("expected the late result to be dropped, mode=%s status=%q", res.mode, res.status)
+	}
+
+	// Backspace during a drill returns to the parent level, which is still listed.
+	res.mode = "compartments"
+	res.ctxItem = ci
+	res.parentID = ci.TenancyOCID
+	res.crumb = res.compartmentCrumb(ci.TenancyOCID)
+	child := compItem{oc: oci.Compartment{ID: "ocid1.compartment.oc1..child", Name: "child", Parent: ci.TenancyOCID, Status: "ACTIVE"}}
+	res.comps.SetItems([]list.Item{child})
+	res.comps.Select(0)
+	crumb := res.crumb
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if res = model.(tuiModel); res.parentID != child.oc.ID {
+		t.Fatalf("expected to drill into child, got %s", res.parentID)
+	}
+	stale := res.loadGen
+	model, _ = res.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	res = model.(tuiModel)
+	if res.mode != "compartments" || res.parentID != ci.TenancyOCID || res.crumb != crumb || res.cancelLoad != nil {
+		t.Fatalf("expected backspace to return to the root level, parent=%s crumb=%q", res.parentID, res.crumb)
+	}
+	model, _ = res.Update(compResultMsg{parent: child.oc.ID, gen: stale})
+	if res = model.(tuiModel); len(res.comps.Items()) != 1 || res.parentID != ci.TenancyOCID {
+		t.Fatalf("expected the cancelled drill's result to be dropped")
+	}
+}
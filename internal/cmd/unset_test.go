@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestUnsetClearsCurrentContextIdempotently(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		cmd := newUnsetCmd()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs([]string{"--config", cfgPath})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unset run %d: %v", i+1, err)
+		}
+		if got := buf.String(); got != "Cleared current context\n" {
+			t.Fatalf("unexpected output on run %d: %q", i+1, got)
+		}
+	}
+
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.CurrentContext != "" {
+		t.Fatalf("expected current context cleared, got %q", loaded.CurrentContext)
+	}
+	if len(loaded.Contexts) != 1 {
+		t.Fatalf("unset should not remove contexts, got %+v", loaded.Contexts)
+	}
+}
@@ -1,17 +1,24 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/ocidutil"
 	"github.com/spf13/cobra"
 )
 
 func newUseCmd() *cobra.Command {
 	var cfgPath string
 	var useGlobal bool
+	var compartment string
+	var create bool
 
 	cmd := &cobra.Command{
-		Use:   "use <name>",
-		Short: "Switch current context",
+		Use:   "use <name>|- [--compartment <ocid>] [--create]",
+		Short: "Switch current context (- returns to the previous one)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useGlobal, err := cmd.Flags().GetBool("global")
@@ -27,18 +34,69 @@ func newUseCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if _, err := cfg.GetContext(name); err != nil {
+			back := name == "-"
+			if back {
+				if cfg.PreviousContext == "" {
+					return fmt.Errorf("no previous context to switch back to")
+				}
+				name = cfg.PreviousContext
+			}
+			ctx, err := cfg.GetContext(name)
+			switch {
+			case err == nil:
+			case back:
+				return fmt.Errorf("previous context %s: %w", name, err)
+			case create && errors.Is(err, config.ErrContextNotFound):
+				if ctx, err = contextFromOCIProfile(cfg, name); err != nil {
+					return fmt.Errorf("%s: no such context, and cannot create it: %w", name, err)
+				}
+				ctx.Notes = config.ImportedNotes
+				ctx.Source = config.SourceImport
+				if err := ctx.Validate(); err != nil {
+					return fmt.Errorf("profile %s invalid: %w", name, err)
+				}
+				if err := cfg.UpsertContext(ctx); err != nil {
+					return err
+				}
+				fmt.Fprintf(infoWriter(cmd.ErrOrStderr()), "Created context %s from OCI profile %s\n", name, name)
+			default:
 				return err
 			}
-			cfg.CurrentContext = name
+			if compartment = strings.TrimSpace(compartment); compartment != "" {
+				if err := ocidutil.Validate("--compartment", compartment, ocidutil.TypeCompartment, ocidutil.TypeTenancy); err != nil {
+					return err
+				}
+				if ocidutil.IsTenancy(compartment) && compartment != ctx.TenancyOCID {
+					return fmt.Errorf("--compartment %s is a different tenancy than context %s (%s)", compartment, name, ctx.TenancyOCID)
+				}
+				ctx.SetCompartment(compartment, "")
+				if err := cfg.UpsertContext(ctx); err != nil {
+					return err
+				}
+			}
+			was := cfg.CurrentContext
+			cfg.SwitchContext(name)
 			if err := config.Save(path, cfg); err != nil {
 				return err
 			}
-			return syncOCIDefaultsForCurrent(cfg)
+			if err := syncOCIDefaultsForCurrent(cfg); err != nil {
+				return err
+			}
+			warnInactiveCompartment(cmd.ErrOrStderr(), ctx)
+			if back {
+				if was == "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "Switched to %s\n", name)
+				} else {
+					fmt.Fprintf(cmd.OutOrStdout(), "Switched to %s (was %s)\n", name, was)
+				}
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
 	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVar(&compartment, "compartment", "", "Also set the context's compartment OCID (or tenancy OCID for root)")
+	cmd.Flags().BoolVar(&create, "create", false, "Create the context from the OCI CLI profile of the same name if it does not exist")
 	return cmd
 }
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+)
+
+func TestUseWithCompartment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	dev := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+	}
+	prod := dev
+	prod.Name = "prod"
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{dev, prod}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newUseCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		return cmd.Execute()
+	}
+
+	if err := run("prod", "--compartment", "ocid1.user.oc1..nope"); err == nil || !strings.Contains(err.Error(), "--compartment") {
+		t.Fatalf("expected non-compartment OCID to fail, got %v", err)
+	}
+	if err := run("prod", "--compartment", "ocid1.tenancy.oc1..other"); err == nil || !strings.Contains(err.Error(), "different tenancy") {
+		t.Fatalf("expected foreign tenancy root to fail, got %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if loaded.CurrentContext != "dev" {
+		t.Fatalf("failed use should not switch, got %q", loaded.CurrentContext)
+	}
+
+	if err := run("prod", "--compartment", "ocid1.compartment.oc1..billing"); err != nil {
+		t.Fatalf("use --compartment: %v", err)
+	}
+	loaded, err = config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	got, _ := loaded.GetContext("prod")
+	if loaded.CurrentContext != "prod" || got.CompartmentOCID != "ocid1.compartment.oc1..billing" {
+		t.Fatalf("unexpected config: current=%q prod=%+v", loaded.CurrentContext, got)
+	}
+	if err := run("dev", "--compartment", dev.TenancyOCID); err != nil {
+		t.Fatalf("use --compartment <tenancy root>: %v", err)
+	}
+}
+
+func TestUseDashSwitchesToPreviousContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	dev := config.Context{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..bbbb"}
+	prod := dev
+	prod.Name = "prod"
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{dev, prod}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newUseCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("-"); err == nil || !strings.Contains(err.Error(), "no previous context") {
+		t.Fatalf("expected missing previous context error, got %v", err)
+	}
+	if _, err := run("prod"); err != nil {
+		t.Fatalf("use prod: %v", err)
+	}
+	out, err := run("-")
+	if err != nil || out != "Switched to dev (was prod)\n" {
+		t.Fatalf("use -: %v (%q)", err, out)
+	}
+	out, err = run("-")
+	if err != nil || out != "Switched to prod (was dev)\n" {
+		t.Fatalf("second use -: %v (%q)", err, out)
+	}
+}
+
+func TestUseWarnsWhenCompartmentWasInactive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	dev := config.Context{
+		Name:             "dev",
+		Profile:          "DEFAULT",
+		TenancyOCID:      "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID:  "ocid1.compartment.oc1..bbbb",
+		CompartmentState: "DELETED",
+	}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{dev}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) string {
+		cmd := newUseCmd()
+		errOut := &bytes.Buffer{}
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(errOut)
+		cmd.SilenceUsage = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("use: %v", err)
+		}
+		return errOut.String()
+	}
+
+	if got := run("dev"); !strings.Contains(got, "compartment ocid1.compartment.oc1..bbbb was DELETED when last checked") {
+		t.Fatalf("expected inactive compartment warning, got %q", got)
+	}
+	if got := run("dev", "--compartment", "ocid1.compartment.oc1..cccc"); got != "" {
+		t.Fatalf("new compartment should drop the stale state, got %q", got)
+	}
+}
+
+func TestUseCreateBuildsMissingContextFromProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "oci_config")
+	if err := os.WriteFile(ociPath, []byte("[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	cfgPath := filepath.Join(tmp, "config.yml")
+	if err := config.Save(cfgPath, config.Config{Options: config.Options{OCIConfigPath: ociPath}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	run := func(args ...string) error {
+		cmd := newUseCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append(args, "--config", cfgPath))
+		return cmd.Execute()
+	}
+
+	if err := run("DEV"); err == nil {
+		t.Fatalf("expected missing context to fail without --create")
+	}
+	for i := 0; i < 2; i++ {
+		if err := run("DEV", "--create"); err != nil {
+			t.Fatalf("use --create (run %d): %v", i+1, err)
+		}
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ctx, err := loaded.GetContext("DEV")
+	if err != nil || loaded.CurrentContext != "DEV" || len(loaded.Contexts) != 1 {
+		t.Fatalf("expected DEV created once and current: current=%q contexts=%+v", loaded.CurrentContext, loaded.Contexts)
+	}
+	if ctx.TenancyOCID != "ocid1.tenancy.oc1..dev" || ctx.CompartmentOCID != ctx.TenancyOCID || ctx.Source != config.SourceImport {
+		t.Fatalf("unexpected created context: %+v", ctx)
+	}
+	if err := run("PROD", "--create"); err == nil || !strings.Contains(err.Error(), "profile PROD not found") {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+}
+
+func TestUseCreateHonorsOCIConfigOverrideWithoutSavingIt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { cliOCIConfig = "" })
+	tmp := t.TempDir()
+	ociPath := filepath.Join(tmp, "other_oci_config")
+	if err := os.WriteFile(ociPath, []byte("[DEV]\ntenancy=ocid1.tenancy.oc1..dev\nregion=us-phoenix-1\n"), 0o600); err != nil {
+		t.Fatalf("write oci config: %v", err)
+	}
+	configured := filepath.Join(tmp, "missing_oci_config")
+	cfgPath := filepath.Join(tmp, "config.yml")
+	if err := config.Save(cfgPath, config.Config{Options: config.Options{OCIConfigPath: configured}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	root := newRootCmd()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"use", "DEV", "--create", "--oci-config", ociPath, "--config", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("use --create --oci-config: %v", err)
+	}
+	loaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if _, err := loaded.GetContext("DEV"); err != nil {
+		t.Fatalf("expected DEV created from the override file: %v", err)
+	}
+	if loaded.Options.OCIConfigPath != configured {
+		t.Fatalf("override must not be saved, got oci_config_path %q", loaded.Options.OCIConfigPath)
+	}
+}
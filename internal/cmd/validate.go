@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocidutil"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// getCompartment is a seam to allow testing compartment checks without the network.
+var getCompartment = oci.GetCompartment
+
+type validateResult struct {
+	Context string `json:"context" yaml:"context"`
+	OK      bool   `json:"ok" yaml:"ok"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+	// compartmentState is the lifecycle state OCI reported for the
+	// compartment, or "" when it was not looked up.
+	compartmentState string
+}
+
+func newValidateCmd() *cobra.Command {
+	var cfgPath string
+	var useGlobal bool
+	var contextName string
+	var output string
+	var timeout time.Duration
+	var checkCompartment bool
+	var record bool
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check every context against OCI identity",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			useGlobal, err := cmd.Flags().GetBool("global")
+			if err != nil {
+				return err
+			}
+			path, err := resolveConfigPath(cfgPath, useGlobal)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if err := config.ValidateFile(cfg); err != nil {
+				return fmt.Errorf("config %s: %w", path, err)
+			}
+			contexts := cfg.Contexts
+			if name := strings.TrimSpace(contextName); name != "" {
+				ctx, err := cfg.GetContext(name)
+				if err != nil {
+					return err
+				}
+				contexts = []config.Context{ctx}
+			}
+			if len(contexts) == 0 {
+				return fmt.Errorf("no contexts to validate")
+			}
+			ociCfgPath, err := resolveOCIConfigPath(cfg)
+			if err != nil {
+				return err
+			}
+
+			results := validateContexts(ociContext(cmd.Context(), cfg), contexts, ociCfgPath, timeout, checkCompartment, cfg.Options.IdentityConcurrencyLimit())
+			if err := printValidateResults(cmd, results, output); err != nil {
+				return err
+			}
+			if record {
+				if n := recordCompartmentStates(&cfg, results); n > 0 {
+					if err := config.Save(path, cfg); err != nil {
+						return err
+					}
+					fmt.Fprintf(infoWriter(cmd.ErrOrStderr()), "Recorded compartment state for %d context(s) in %s\n", n, path)
+				}
+			}
+			failed := 0
+			for _, r := range results {
+				if !r.OK {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d contexts failed validation", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to config file")
+	cmd.Flags().BoolVarP(&useGlobal, "global", "g", false, "Use global config (~/.oci-context/config.yml)")
+	cmd.Flags().StringVar(&contextName, "context", "", "Validate only this context")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json|yaml")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Second, "Per-context timeout for OCI calls")
+	cmd.Flags().BoolVar(&checkCompartment, "check-compartment", true, "Verify the compartment OCID still exists, is ACTIVE, and is in the context's tenancy")
+	cmd.Flags().BoolVar(&record, "record", false, "Save the compartment states OCI reported to the config file")
+	return cmd
+}
+
+// validateContexts checks contexts concurrently with a bounded worker pool and
+// returns results in the input order.
+func validateContexts(parent context.Context, contexts []config.Context, ociCfgPath string, timeout time.Duration, checkCompartment bool, concurrency int) []validateResult {
+	if concurrency <= 0 {
+		concurrency = config.DefaultIdentityConcurrency
+	}
+	results := make([]validateResult, len(contexts))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, c := range contexts {
+		wg.Add(1)
+		go func(i int, c config.Context) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = validateOneContext(parent, c, ociCfgPath, timeout, checkCompartment)
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+func validateOneContext(parent context.Context, c config.Context, ociCfgPath string, timeout time.Duration, checkCompartment bool) validateResult {
+	res := validateResult{Context: c.Name}
+	if err := c.Validate(); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	if _, err := fetchIdentity(ctx, ociCfgPath, c.Profile, c.Region, c.TenancyOCID, c.CompartmentOCID, c.User); err != nil {
+		res.Error = withOCIHint(err).Error()
+		return res
+	}
+	if checkCompartment && c.CompartmentOCID != "" && c.CompartmentOCID != c.TenancyOCID {
+		comp, err := getCompartment(ctx, ociCfgPath, c.Profile, c.Region, c.CompartmentOCID)
+		if err != nil {
+			res.Error = withOCIHint(err).Error()
+			return res
+		}
+		res.compartmentState = comp.Status
+		if comp.Status != "" && comp.Status != "ACTIVE" {
+			res.Error = fmt.Sprintf("compartment %s is %s", c.CompartmentOCID, comp.Status)
+			return res
+		}
+		if err := checkCompartmentTenancy(ctx, ociCfgPath, c, comp); err != nil {
+			res.Error = withOCIHint(err).Error()
+			return res
+		}
+	}
+	res.OK = true
+	return res
+}
+
+// recordCompartmentStates stores the compartment states validate observed on
+// cfg's contexts, so `use` and `status` stop warning about a compartment that
+// is ACTIVE again. It returns how many contexts changed.
+func recordCompartmentStates(cfg *config.Config, results []validateResult) int {
+	changed := 0
+	for _, r := range results {
+		if r.compartmentState == "" {
+			continue
+		}
+		ctx, err := cfg.GetContext(r.Context)
+		if err != nil || ctx.CompartmentState == r.compartmentState {
+			continue
+		}
+		ctx.CompartmentState = r.compartmentState
+		if cfg.UpsertContext(ctx) == nil {
+			changed++
+		}
+	}
+	return changed
+}
+
+// checkCompartmentTenancy follows comp's parents up to a tenancy and fails
+// when that is not c's tenancy, as when a compartment OCID is pasted from
+// another tenancy. A compartment with no recorded parent is not checked.
+func checkCompartmentTenancy(ctx context.Context, ociCfgPath string, c config.Context, comp oci.Compartment) error {
+	seen := map[string]bool{comp.ID: true}
+	for range oci.DefaultWalkDepth {
+		parent := comp.Parent
+		switch {
+		case parent == "" || parent == c.TenancyOCID:
+			return nil
+		case ocidutil.IsTenancy(parent):
+			return fmt.Errorf("compartment %s belongs to a different tenancy (%s, not %s)", c.CompartmentOCID, parent, c.TenancyOCID)
+		case seen[parent]:
+			return fmt.Errorf("%w: %s is its own ancestor", oci.ErrCompartmentCycle, parent)
+		}
+		seen[parent] = true
+		next, err := getCompartment(ctx, ociCfgPath, c.Profile, c.Region, parent)
+		if err != nil {
+			return err
+		}
+		comp = next
+	}
+	return fmt.Errorf("compartment %s is nested more than %d levels below any tenancy", c.CompartmentOCID, oci.DefaultWalkDepth)
+}
+
+func printValidateResults(cmd *cobra.Command, results []validateResult, output string) error {
+	switch strings.ToLower(output) {
+	case "", "text":
+		for _, r := range results {
+			if r.OK {
+				fmt.Fprintf(cmd.OutOrStdout(), "OK    %s\n", r.Context)
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "ERROR %s: %s\n", r.Context, r.Error)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "yaml", "yml":
+		enc := yaml.NewEncoder(cmd.OutOrStdout())
+		defer enc.Close()
+		return enc.Encode(results)
+	default:
+		return fmt.Errorf("unsupported output format: %s", output)
+	}
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func TestValidateReportsPerContextResults(t *testing.T) {
+	origIdentity, origComp := fetchIdentity, getCompartment
+	t.Cleanup(func() {
+		fetchIdentity = origIdentity
+		getCompartment = origComp
+	})
+	fetchIdentity = func(_ context.Context, _path, profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		if profile == "BROKEN" {
+			return oci.IdentityDetails{}, errors.New("get tenancy: 401 NotAuthenticated")
+		}
+		return oci.IdentityDetails{TenancyOCID: tenancyOCID}, nil
+	}
+	getCompartment = func(_ context.Context, _path, _profile, _region, compartmentID string) (oci.Compartment, error) {
+		if strings.HasSuffix(compartmentID, "gone") {
+			return oci.Compartment{}, errors.New("get compartment: 404 NotAuthorizedOrNotFound")
+		}
+		return oci.Compartment{ID: compartmentID, Status: "ACTIVE"}, nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..bbbb"},
+			{Name: "stale", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..gone"},
+			{Name: "broken", Profile: "BROKEN", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.tenancy.oc1..aaaa"},
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newValidateCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--config", cfgPath})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "2 of 3 contexts failed validation") {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	want := strings.Join([]string{
+		"OK    dev",
+		"ERROR stale: get compartment: 404 NotAuthorizedOrNotFound",
+		"ERROR broken: get tenancy: 401 NotAuthenticated",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+	}
+
+	single := newValidateCmd()
+	buf.Reset()
+	single.SetOut(buf)
+	single.SetErr(buf)
+	single.SetArgs([]string{"--config", cfgPath, "--context", "dev"})
+	if err := single.Execute(); err != nil {
+		t.Fatalf("expected dev to validate, got %v", err)
+	}
+	if got := buf.String(); got != "OK    dev\n" {
+		t.Fatalf("unexpected single-context output: %q", got)
+	}
+}
+
+func TestValidateReportsMalformedContextPerContext(t *testing.T) {
+	origIdentity := fetchIdentity
+	t.Cleanup(func() { fetchIdentity = origIdentity })
+	fetchIdentity = func(_ context.Context, _, _, _, tenancyOCID, _, _ string) (oci.IdentityDetails, error) {
+		return oci.IdentityDetails{TenancyOCID: tenancyOCID}, nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "good", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.tenancy.oc1..aaaa"},
+			{Name: "bad", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "garbage"},
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newValidateCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--config", cfgPath})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "1 of 2 contexts failed validation") {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	want := "OK    good\nERROR bad: context compartment_ocid \"garbage\" does not look like a compartment OCID\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+	}
+
+	single := newValidateCmd()
+	buf.Reset()
+	single.SetOut(buf)
+	single.SetErr(buf)
+	single.SetArgs([]string{"--config", cfgPath, "--context", "good"})
+	if err := single.Execute(); err != nil {
+		t.Fatalf("expected good to validate, got %v", err)
+	}
+	if got := buf.String(); got != "OK    good\n" {
+		t.Fatalf("unexpected single-context output: %q", got)
+	}
+}
+
+func TestValidateRejectsCompartmentFromAnotherTenancy(t *testing.T) {
+	origIdentity, origComp := fetchIdentity, getCompartment
+	t.Cleanup(func() {
+		fetchIdentity = origIdentity
+		getCompartment = origComp
+	})
+	fetchIdentity = func(_ context.Context, _, _, _, tenancyOCID, _, _ string) (oci.IdentityDetails, error) {
+		return oci.IdentityDetails{TenancyOCID: tenancyOCID}, nil
+	}
+	tenancy := "ocid1.tenancy.oc1..aaaa"
+	parents := map[string]string{
+		"ocid1.compartment.oc1..apps":      tenancy,
+		"ocid1.compartment.oc1..team":      "ocid1.compartment.oc1..apps",
+		"ocid1.compartment.oc1..other":     "ocid1.compartment.oc1..otherroot",
+		"ocid1.compartment.oc1..otherroot": "ocid1.tenancy.oc1..zzzz",
+		"ocid1.compartment.oc1..loop":      "ocid1.compartment.oc1..loop2",
+		"ocid1.compartment.oc1..loop2":     "ocid1.compartment.oc1..loop",
+	}
+	getCompartment = func(_ context.Context, _, _, _, compartmentID string) (oci.Compartment, error) {
+		return oci.Compartment{ID: compartmentID, Status: "ACTIVE", Parent: parents[compartmentID]}, nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	ctx := func(name, compartment string) config.Context {
+		return config.Context{Name: name, Profile: "DEFAULT", TenancyOCID: tenancy, CompartmentOCID: compartment}
+	}
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			ctx("team", "ocid1.compartment.oc1..team"),
+			ctx("pasted", "ocid1.compartment.oc1..other"),
+			ctx("loop", "ocid1.compartment.oc1..loop"),
+		},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := newValidateCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--config", cfgPath})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "2 of 3 contexts failed validation") {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	want := strings.Join([]string{
+		"OK    team",
+		"ERROR pasted: compartment ocid1.compartment.oc1..other belongs to a different tenancy (ocid1.tenancy.oc1..zzzz, not ocid1.tenancy.oc1..aaaa)",
+		"ERROR loop: compartment hierarchy has a cycle: ocid1.compartment.oc1..loop is its own ancestor",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\nwant:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestValidateRecordsCompartmentState(t *testing.T) {
+	origIdentity, origComp := fetchIdentity, getCompartment
+	t.Cleanup(func() {
+		fetchIdentity = origIdentity
+		getCompartment = origComp
+	})
+	fetchIdentity = func(_ context.Context, _, _, _, tenancyOCID, _, _ string) (oci.IdentityDetails, error) {
+		return oci.IdentityDetails{TenancyOCID: tenancyOCID}, nil
+	}
+	getCompartment = func(_ context.Context, _, _, _, compartmentID string) (oci.Compartment, error) {
+		return oci.Compartment{ID: compartmentID, Status: "ACTIVE", Parent: "ocid1.tenancy.oc1..aaaa"}, nil
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{
+			{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..bbbb", CompartmentState: "CREATING"},
+		},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newValidateCmd()
+		stderr := &bytes.Buffer{}
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(stderr)
+		cmd.SetArgs(append([]string{"--config", cfgPath}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("validate: %v", err)
+		}
+		return stderr.String()
+	}
+
+	// Without --record the config file is left alone.
+	before, _ := os.ReadFile(cfgPath)
+	if out := run(); out != "" {
+		t.Fatalf("expected no notice without --record, got %q", out)
+	}
+	if after, _ := os.ReadFile(cfgPath); !bytes.Equal(before, after) {
+		t.Fatalf("validate without --record rewrote the config:\n%s", after)
+	}
+
+	if out, want := run("--record"), "Recorded compartment state for 1 context(s) in "+cfgPath+"\n"; out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+	got, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ctx, _ := got.GetContext("dev")
+	if ctx.CompartmentState != config.CompartmentStateActive {
+		t.Fatalf("expected validate to record ACTIVE, got %q", ctx.CompartmentState)
+	}
+	stderr := &bytes.Buffer{}
+	warnInactiveCompartment(stderr, ctx)
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no warning after validate, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+)
+
+// compartmentFetchTimeout bounds one list_compartments OCI call.
+const compartmentFetchTimeout = 30 * time.Second
+
+// maxCompartmentCacheEntries bounds the list_compartments cache; the oldest
+// entries are evicted first.
+const maxCompartmentCacheEntries = 256
+
+// fetchCompartments is a seam so tests can serve a fake hierarchy.
+var fetchCompartments = oci.FetchCompartments
+
+// compartmentCacheKey names one cached listing. The same profile name in two
+// OCI config files names different credentials, so the path is part of it.
+type compartmentCacheKey struct {
+	ociPath, profile, region, parent string
+}
+
+type compartmentCacheEntry struct {
+	items     []oci.Compartment
+	fetchedAt time.Time
+}
+
+// listCompartments returns the children of parent (default: the current
+// context's tenancy) using the current context's profile and region. Results
+// are cached per OCI config path, profile, region, and parent for
+// CompartmentCacheTTL.
+func (s *Service) listCompartments(parent string) (interface{}, error) {
+	if err := s.reloadConfig(); err != nil {
+		return nil, err
+	}
+	cfg := s.currentConfig()
+	if cfg.CurrentContext == "" {
+		return nil, errors.New("no current context set")
+	}
+	ctx, err := cfg.GetContext(cfg.CurrentContext)
+	if err != nil {
+		return nil, err
+	}
+	if parent == "" {
+		parent = ctx.TenancyOCID
+	}
+	ociPath, err := ocicfg.ResolveConfigPath("", cfg.Options.OCIConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	key := compartmentCacheKey{ociPath: ociPath, profile: ctx.Profile, region: ctx.Region, parent: parent}
+
+	s.compMu.Lock()
+	entry, ok := s.compCache[key]
+	s.compMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < s.opts.CompartmentCacheTTL {
+		return entry.items, nil
+	}
+
+	c, cancel := context.WithTimeout(oci.WithRetryAttempts(context.Background(), cfg.Options.APIRetryAttempts()), compartmentFetchTimeout)
+	defer cancel()
+	items, err := fetchCompartments(c, ociPath, ctx.Profile, ctx.Region, parent)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []oci.Compartment{}
+	}
+	s.storeCompartments(key, items)
+	return items, nil
+}
+
+// storeCompartments caches items under key, first dropping expired entries
+// and then the oldest ones beyond maxCompartmentCacheEntries.
+func (s *Service) storeCompartments(key compartmentCacheKey, items []oci.Compartment) {
+	s.compMu.Lock()
+	defer s.compMu.Unlock()
+	now := time.Now()
+	for k, e := range s.compCache {
+		if now.Sub(e.fetchedAt) >= s.opts.CompartmentCacheTTL {
+			delete(s.compCache, k)
+		}
+	}
+	s.compCache[key] = compartmentCacheEntry{items: items, fetchedAt: now}
+	if extra := len(s.compCache) - maxCompartmentCacheEntries; extra > 0 {
+		keys := make([]compartmentCacheKey, 0, len(s.compCache))
+		for k := range s.compCache {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return s.compCache[keys[i]].fetchedAt.Before(s.compCache[keys[j]].fetchedAt)
+		})
+		for _, k := range keys[:extra] {
+			delete(s.compCache, k)
+		}
+	}
+}
+
+// CacheClearResult is the clear_cache response.
+type CacheClearResult struct {
+	Removed int `json:"removed"`
+}
+
+// clearCache drops cached list_compartments results. With a context payload
+// only entries for its profile go, whichever OCI config file they were read
+// from (the context may already be deleted from the config); without one the
+// whole cache is cleared.
+func (s *Service) clearCache(raw json.RawMessage) (interface{}, error) {
+	profile := ""
+	if len(raw) > 0 {
+		var ctx config.Context
+		if err := json.Unmarshal(raw, &ctx); err != nil {
+			return nil, fmt.Errorf("invalid context: %w", err)
+		}
+		if ctx.Profile == "" {
+			return nil, errors.New("context profile required")
+		}
+		profile = ctx.Profile
+	}
+	s.compMu.Lock()
+	defer s.compMu.Unlock()
+	removed := 0
+	for key := range s.compCache {
+		if profile == "" || key.profile == profile {
+			delete(s.compCache, key)
+			removed++
+		}
+	}
+	return CacheClearResult{Removed: removed}, nil
+}
@@ -2,11 +2,13 @@ package daemon
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +16,7 @@ import (
 	srvipc "github.com/adrianmross/oci-context/internal/ipc"
 	"github.com/adrianmross/oci-context/pkg/config"
 	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
 )
 
 // ServiceOptions controls daemon background behaviors.
@@ -23,6 +26,12 @@ type ServiceOptions struct {
 	RefreshInterval        time.Duration
 	RefreshOnValidateError bool
 	ValidateOnStart        bool
+	// Version is reported by the ping and capabilities methods.
+	Version string
+	// RequestTimeout bounds how long a client may take to send each request.
+	RequestTimeout time.Duration
+	// CompartmentCacheTTL is how long list_compartments results are reused.
+	CompartmentCacheTTL time.Duration
 }
 
 // DefaultServiceOptions returns conservative defaults.
@@ -33,6 +42,8 @@ func DefaultServiceOptions() ServiceOptions {
 		RefreshInterval:        15 * time.Minute,
 		RefreshOnValidateError: true,
 		ValidateOnStart:        true,
+		RequestTimeout:         srvipc.DefaultServerOptions().ReadTimeout,
+		CompartmentCacheTTL:    5 * time.Minute,
 	}
 }
 
@@ -58,6 +69,13 @@ type AuthStatus struct {
 	Reason         string `json:"reason,omitempty"`
 }
 
+// PingResult is returned by the ping method.
+type PingResult struct {
+	Pong    bool   `json:"pong"`
+	PID     int    `json:"pid"`
+	Version string `json:"version"`
+}
+
 type authStatusState struct {
 	ContextName      string
 	AuthMethod       string
@@ -87,6 +105,9 @@ type Service struct {
 
 	backoffMu sync.Mutex
 	backoff   map[string]backoffState
+
+	compMu    sync.Mutex
+	compCache map[compartmentCacheKey]compartmentCacheEntry
 }
 
 type backoffState struct {
@@ -119,30 +140,60 @@ func NewServiceWithOptions(cfgPath string, opts ServiceOptions) (*Service, error
 		opts.RefreshInterval = 15 * time.Minute
 	}
 	return &Service{
-		cfgPath: cfgPath,
-		cfg:     cfg,
-		opts:    opts,
-		status:  make(map[string]authStatusState),
-		backoff: make(map[string]backoffState),
+		cfgPath:   cfgPath,
+		cfg:       cfg,
+		opts:      opts,
+		status:    make(map[string]authStatusState),
+		backoff:   make(map[string]backoffState),
+		compCache: make(map[compartmentCacheKey]compartmentCacheEntry),
 	}, nil
 }
 
 // Serve runs the IPC server.
 func (s *Service) Serve() error {
+	return s.ServeContext(context.Background())
+}
+
+// ServeContext runs the IPC server until ctx is cancelled, then drains
+// in-flight requests and stops the auth maintenance loop.
+func (s *Service) ServeContext(ctx context.Context) error {
 	if s.opts.AutoRefresh {
-		go s.authMaintenanceLoop()
+		go s.authMaintenanceLoop(ctx)
+	}
+	serverOpts := srvipc.DefaultServerOptions()
+	if s.opts.RequestTimeout > 0 {
+		serverOpts.ReadTimeout = s.opts.RequestTimeout
 	}
-	return srvipc.Serve(s.currentConfig().Options.SocketPath, s.handle)
+	return srvipc.ServeContext(ctx, s.currentConfig().Options.SocketPath, s.handle, serverOpts)
+}
+
+// methods lists what handle implements, for the capabilities method.
+// TestCapabilitiesMatchHandle fails if an entry is not in handle's switch.
+var methods = []string{
+	"ping",
+	"capabilities",
+	"get_current",
+	"list",
+	"use_context",
+	"add_context",
+	"delete_context",
+	"export",
+	"auth_status",
+	"auth_nudge",
+	"list_compartments",
+	"clear_cache",
 }
 
 func (s *Service) handle(req ipcmsg.Request) (interface{}, error) {
 	switch req.Method {
+	case "ping":
+		return PingResult{Pong: true, PID: os.Getpid(), Version: s.opts.Version}, nil
+	case "capabilities":
+		return ipcmsg.Capabilities{Protocol: ipcmsg.ProtocolVersion, Version: s.opts.Version, Methods: slices.Clone(methods)}, nil
 	case "get_current":
 		return s.getCurrent()
 	case "list":
-		s.mu.RLock()
-		defer s.mu.RUnlock()
-		return s.cfg.Contexts, nil
+		return s.currentConfig().Contexts, nil
 	case "use_context":
 		return s.useContext(req.Name)
 	case "add_context":
@@ -150,20 +201,29 @@ func (s *Service) handle(req ipcmsg.Request) (interface{}, error) {
 	case "delete_context":
 		return s.deleteContext(req.Name)
 	case "export":
-		return s.export(req.Format)
+		return s.export(req.Format, req.Style)
 	case "auth_status":
 		return s.authStatus(req.Name)
 	case "auth_nudge":
 		return s.authNudge(req.Name)
+	case "list_compartments":
+		return s.listCompartments(req.Parent)
+	case "clear_cache":
+		return s.clearCache(req.Context)
 	default:
 		return nil, srvipc.ErrNotImplemented
 	}
 }
 
+// currentConfig returns a snapshot that stays valid after the lock is
+// released. Its Contexts slice is copied because add and delete rewrite
+// s.cfg.Contexts in place, and results are encoded outside the lock.
 func (s *Service) currentConfig() config.Config {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.cfg
+	cfg := s.cfg
+	cfg.Contexts = slices.Clone(s.cfg.Contexts)
+	return cfg
 }
 
 func (s *Service) reloadConfig() error {
@@ -196,7 +256,7 @@ func (s *Service) useContext(name string) (interface{}, error) {
 	if _, err := s.cfg.GetContext(name); err != nil {
 		return nil, err
 	}
-	s.cfg.CurrentContext = name
+	s.cfg.SwitchContext(name)
 	if err := config.Save(s.cfgPath, s.cfg); err != nil {
 		return nil, err
 	}
@@ -235,7 +295,7 @@ func (s *Service) deleteContext(name string) (interface{}, error) {
 	return map[string]string{"deleted": name}, nil
 }
 
-func (s *Service) export(format string) (interface{}, error) {
+func (s *Service) export(format, style string) (interface{}, error) {
 	ctxAny, err := s.getCurrent()
 	if err != nil {
 		return nil, err
@@ -244,13 +304,18 @@ func (s *Service) export(format string) (interface{}, error) {
 
 	switch format {
 	case "env":
-		lines := []string{
-			fmt.Sprintf("OCI_CLI_PROFILE=%s", c.Profile),
-			fmt.Sprintf("OCI_TENANCY_OCID=%s", c.TenancyOCID),
-			fmt.Sprintf("OCI_COMPARTMENT_OCID=%s", c.CompartmentOCID),
+		style, err := config.ParseEnvStyle(style)
+		if err != nil {
+			return nil, err
+		}
+		// Resolve like the CLI's export, so an unset option still names a file.
+		ociPath, err := ocicfg.ResolveConfigPath("", s.currentConfig().Options.OCIConfigPath)
+		if err != nil {
+			return nil, err
 		}
-		if c.Region != "" {
-			lines = append(lines, fmt.Sprintf("OCI_REGION=%s", c.Region))
+		var lines []string
+		for _, kv := range config.ExportEnv(c, ociPath, style) {
+			lines = append(lines, kv[0]+"="+kv[1])
 		}
 		return map[string][]string{"env": lines}, nil
 	case "json", "":
@@ -397,7 +462,7 @@ func authModeForMethod(method string) string {
 	return "validate-only"
 }
 
-func (s *Service) authMaintenanceLoop() {
+func (s *Service) authMaintenanceLoop(ctx context.Context) {
 	validateTicker := time.NewTicker(s.opts.ValidateInterval)
 	refreshTicker := time.NewTicker(s.opts.RefreshInterval)
 	defer validateTicker.Stop()
@@ -409,6 +474,8 @@ func (s *Service) authMaintenanceLoop() {
 
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-validateTicker.C:
 			s.maintainAuth("validate")
 		case <-refreshTicker.C:
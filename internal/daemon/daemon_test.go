@@ -1,10 +1,19 @@
 package daemon
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/adrianmross/oci-context/pkg/config"
+	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
+	"github.com/adrianmross/oci-context/pkg/oci"
 )
 
 func TestBuildValidateOCIArgsOmitsCompartmentFlag(t *testing.T) {
@@ -177,3 +186,283 @@ func TestFinalizeAuthStatusBackfillsOlderDaemonStatus(t *testing.T) {
 		t.Fatalf("expected older validate-ok status to be finalized as ready warning, got %+v", got)
 	}
 }
+
+func TestListCompartmentsDefaultsToTenancyAndCaches(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.tenancy.oc1..aaaa",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewService(cfgPath)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	var calls []string
+	prev := fetchCompartments
+	fetchCompartments = func(_ context.Context, ociPath, profile, region, parent string) ([]oci.Compartment, error) {
+		calls = append(calls, strings.Join([]string{ociPath, profile, region, parent}, " "))
+		return []oci.Compartment{{ID: "ocid1.compartment.oc1..app", Name: "app", Status: "ACTIVE"}}, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prev })
+
+	for i := 0; i < 2; i++ {
+		data, err := svc.handle(ipcmsg.Request{Method: "list_compartments"})
+		if err != nil {
+			t.Fatalf("list_compartments: %v", err)
+		}
+		if items := data.([]oci.Compartment); len(items) != 1 || items[0].Name != "app" {
+			t.Fatalf("unexpected compartments: %+v", items)
+		}
+	}
+	if len(calls) != 1 || calls[0] != "/tmp/oci DEFAULT us-phoenix-1 ocid1.tenancy.oc1..aaaa" {
+		t.Fatalf("expected one cached fetch of the tenancy root, got %v", calls)
+	}
+
+	if _, err := svc.handle(ipcmsg.Request{Method: "list_compartments", Parent: "ocid1.compartment.oc1..app"}); err != nil {
+		t.Fatalf("list_compartments parent: %v", err)
+	}
+	if len(calls) != 2 || !strings.HasSuffix(calls[1], "ocid1.compartment.oc1..app") {
+		t.Fatalf("expected a second fetch for the new parent, got %v", calls)
+	}
+
+	for _, tc := range []struct {
+		profile string
+		removed int
+	}{{"OTHER", 0}, {"DEFAULT", 2}} {
+		raw, _ := json.Marshal(config.Context{Name: "gone", Profile: tc.profile})
+		data, err := svc.handle(ipcmsg.Request{Method: "clear_cache", Context: raw})
+		if err != nil {
+			t.Fatalf("clear_cache %s: %v", tc.profile, err)
+		}
+		if got := data.(CacheClearResult).Removed; got != tc.removed {
+			t.Fatalf("clear_cache %s removed %d, want %d", tc.profile, got, tc.removed)
+		}
+	}
+	if _, err := svc.handle(ipcmsg.Request{Method: "list_compartments"}); err != nil {
+		t.Fatalf("list_compartments after clear: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected a refetch after clear_cache, got %v", calls)
+	}
+}
+
+func TestCompartmentCacheKeysOnOCIConfigAndEvicts(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci-a"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.tenancy.oc1..aaaa",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewService(cfgPath)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	var calls []string
+	prev := fetchCompartments
+	fetchCompartments = func(_ context.Context, ociPath, _, _, _ string) ([]oci.Compartment, error) {
+		calls = append(calls, ociPath)
+		return []oci.Compartment{}, nil
+	}
+	t.Cleanup(func() { fetchCompartments = prev })
+
+	// The same profile name in another OCI config file is a separate entry.
+	for _, ociPath := range []string{"/tmp/oci-a", "/tmp/oci-b", "/tmp/oci-a"} {
+		cfg.Options.OCIConfigPath = ociPath
+		if err := config.Save(cfgPath, cfg); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		if _, err := svc.handle(ipcmsg.Request{Method: "list_compartments"}); err != nil {
+			t.Fatalf("list_compartments: %v", err)
+		}
+	}
+	if strings.Join(calls, " ") != "/tmp/oci-a /tmp/oci-b" {
+		t.Fatalf("expected one fetch per OCI config file, got %v", calls)
+	}
+
+	// Storing drops expired entries, then the oldest beyond the limit.
+	svc.compMu.Lock()
+	old := time.Now().Add(-time.Hour)
+	for key, e := range svc.compCache {
+		e.fetchedAt = old
+		svc.compCache[key] = e
+	}
+	svc.compMu.Unlock()
+	for i := 0; i < maxCompartmentCacheEntries+10; i++ {
+		svc.storeCompartments(compartmentCacheKey{parent: fmt.Sprintf("p%d", i)}, nil)
+	}
+	svc.compMu.Lock()
+	defer svc.compMu.Unlock()
+	if len(svc.compCache) != maxCompartmentCacheEntries {
+		t.Fatalf("expected the cache capped at %d, got %d", maxCompartmentCacheEntries, len(svc.compCache))
+	}
+	if _, ok := svc.compCache[compartmentCacheKey{ociPath: "/tmp/oci-a", profile: "DEFAULT", region: "us-phoenix-1", parent: "ocid1.tenancy.oc1..aaaa"}]; ok {
+		t.Fatalf("expected the expired entry to be evicted")
+	}
+}
+
+func TestConcurrentAddContextAndListDoNotRace(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	base := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.tenancy.oc1..aaaa",
+		Region:          "us-phoenix-1",
+	}
+	if err := config.Save(cfgPath, config.Config{Contexts: []config.Context{base}, CurrentContext: "dev"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewService(cfgPath)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			ctx := base
+			ctx.Name = fmt.Sprintf("ctx-%d", i%3)
+			ctx.Region = fmt.Sprintf("region-%d", i)
+			raw, _ := json.Marshal(ctx)
+			if _, err := svc.handle(ipcmsg.Request{Method: "add_context", Context: raw}); err != nil {
+				errs <- err
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			data, err := svc.handle(ipcmsg.Request{Method: "list"})
+			if err != nil {
+				errs <- err
+				return
+			}
+			// The IPC server encodes results after handle returns, outside the lock.
+			if _, err := json.Marshal(data); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent request: %v", err)
+	}
+	data, err := svc.handle(ipcmsg.Request{Method: "list"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if got := len(data.([]config.Context)); got != 4 {
+		t.Fatalf("expected dev plus three added contexts, got %d", got)
+	}
+}
+
+func TestCapabilitiesListsMethods(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := config.Save(cfgPath, config.Config{}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewServiceWithOptions(cfgPath, ServiceOptions{Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	data, err := svc.handle(ipcmsg.Request{Method: "capabilities"})
+	if err != nil {
+		t.Fatalf("capabilities: %v", err)
+	}
+	caps := data.(ipcmsg.Capabilities)
+	if caps.Protocol != ipcmsg.ProtocolVersion || caps.Version != "1.2.3" {
+		t.Fatalf("unexpected capabilities %+v", caps)
+	}
+	for _, m := range []string{"ping", "capabilities", "list", "list_compartments", "clear_cache"} {
+		if !caps.Supports(m) {
+			t.Fatalf("capabilities missing %s: %v", m, caps.Methods)
+		}
+	}
+	if caps.Supports("bogus") {
+		t.Fatalf("capabilities lists an unknown method")
+	}
+	if _, err := svc.handle(ipcmsg.Request{Method: "bogus"}); !errors.Is(err, ipcmsg.ErrNotImplemented) {
+		t.Fatalf("expected ErrNotImplemented for an unknown method, got %v", err)
+	}
+}
+
+// TestCapabilitiesMatchHandle keeps the capabilities list in step with handle:
+// every listed method must be dispatched, even if it fails on an empty request.
+func TestCapabilitiesMatchHandle(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := config.Save(cfgPath, config.Config{}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewServiceWithOptions(cfgPath, ServiceOptions{})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	for _, m := range methods {
+		if _, err := svc.handle(ipcmsg.Request{Method: m}); errors.Is(err, ipcmsg.ErrNotImplemented) {
+			t.Errorf("capabilities lists %s but handle does not implement it", m)
+		}
+	}
+}
+
+func TestExportEnvMatchesCLIStyles(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	cfg := config.Config{
+		Options: config.Options{OCIConfigPath: "/tmp/oci"},
+		Contexts: []config.Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+		}},
+		CurrentContext: "dev",
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	svc, err := NewService(cfgPath)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	for _, tc := range []struct {
+		style string
+		want  []string
+	}{
+		{"", []string{"OCI_CLI_PROFILE=DEFAULT", "OCI_CLI_REGION=us-phoenix-1", "OCI_CLI_CONFIG_FILE=/tmp/oci", "OCI_TENANCY_OCID=ocid1.tenancy.oc1..aaaa", "OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..bbbb", "OCI_REGION=us-phoenix-1"}},
+		{"oci-cli", []string{"OCI_CLI_PROFILE=DEFAULT", "OCI_CLI_REGION=us-phoenix-1", "OCI_CLI_CONFIG_FILE=/tmp/oci", "OCI_CLI_TENANCY=ocid1.tenancy.oc1..aaaa", "OCI_COMPARTMENT_OCID=ocid1.compartment.oc1..bbbb"}},
+	} {
+		data, err := svc.handle(ipcmsg.Request{Method: "export", Format: "env", Style: tc.style})
+		if err != nil {
+			t.Fatalf("export style %q: %v", tc.style, err)
+		}
+		if got := data.(map[string][]string)["env"]; strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Fatalf("export style %q = %v, want %v", tc.style, got, tc.want)
+		}
+	}
+	if _, err := svc.handle(ipcmsg.Request{Method: "export", Format: "env", Style: "bogus"}); err == nil {
+		t.Fatalf("expected an unknown style to fail")
+	}
+}
@@ -2,11 +2,14 @@ package ipc
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"os"
+	"sync"
+	"time"
 
 	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
 )
@@ -14,36 +17,157 @@ import (
 // HandlerFunc processes a request and returns a response payload or error.
 type HandlerFunc func(req ipcmsg.Request) (interface{}, error)
 
-// Serve starts a Unix socket server and handles requests with the provided handler.
+// ServerOptions bounds what a single client can cost the server.
+type ServerOptions struct {
+	// ReadTimeout is how long a connection may take to deliver each request line.
+	ReadTimeout time.Duration
+	// MaxRequestBytes caps the length of one request line.
+	MaxRequestBytes int
+}
+
+// DefaultServerOptions returns the limits used by Serve.
+func DefaultServerOptions() ServerOptions {
+	return ServerOptions{
+		ReadTimeout:     30 * time.Second,
+		MaxRequestBytes: 1 << 20,
+	}
+}
+
+// errRequestTooLarge is returned by readRequest when a line exceeds the cap.
+var errRequestTooLarge = errors.New("request too large")
+
+// Serve starts a socket server on socketPath (a Unix socket path, "@name" for
+// an abstract socket, or "tcp://host:port") and handles requests with the
+// provided handler.
 func Serve(socketPath string, handler HandlerFunc) error {
-	// remove stale socket
-	if err := os.RemoveAll(socketPath); err != nil {
-		return fmt.Errorf("remove stale socket: %w", err)
+	return ServeContext(context.Background(), socketPath, handler, DefaultServerOptions())
+}
+
+// checkLoopback refuses a TCP listen address whose host is not loopback. A TCP
+// socket has no file permissions and requests are not authenticated, so any
+// host that can reach it could switch contexts or export credentials.
+func checkLoopback(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("socket_path tcp://%s: %w", address, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("socket_path tcp://%s: host %q is not a loopback address; the daemon does not authenticate requests, so it only listens on 127.0.0.1, ::1, or localhost", address, host)
+}
+
+// ServeContext is like Serve but stops when ctx is cancelled: it closes the
+// listener, lets in-flight requests finish, waits for every connection
+// handler to return, and removes the socket file. It returns nil on a clean stop.
+func ServeContext(ctx context.Context, socketPath string, handler HandlerFunc, opts ServerOptions) error {
+	defaults := DefaultServerOptions()
+	if opts.ReadTimeout <= 0 {
+		opts.ReadTimeout = defaults.ReadTimeout
+	}
+	if opts.MaxRequestBytes <= 0 {
+		opts.MaxRequestBytes = defaults.MaxRequestBytes
+	}
+	network, address := ipcmsg.ParseAddress(socketPath)
+	if network == "tcp" {
+		if err := checkLoopback(address); err != nil {
+			return err
+		}
 	}
-	ln, err := net.Listen("unix", socketPath)
+	// TCP and abstract sockets have no file to clean up or restrict.
+	onDisk := ipcmsg.IsFilesystemSocket(socketPath)
+	if onDisk {
+		// remove stale socket
+		if err := os.RemoveAll(socketPath); err != nil {
+			return fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen(network, address)
 	if err != nil {
 		return fmt.Errorf("listen: %w", err)
 	}
-	defer ln.Close()
-	if err := os.Chmod(socketPath, 0o600); err != nil {
-		return fmt.Errorf("chmod socket: %w", err)
+	if onDisk {
+		defer func() {
+			_ = os.Remove(socketPath)
+		}()
+		if err := os.Chmod(socketPath, 0o600); err != nil {
+			ln.Close()
+			return fmt.Errorf("chmod socket: %w", err)
+		}
 	}
 
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		conns = make(map[net.Conn]struct{})
+	)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		ln.Close()
+		// Unblock idle reads; a handler already running still writes its response.
+		mu.Lock()
+		for c := range conns {
+			if uc, ok := c.(interface{ CloseRead() error }); ok {
+				_ = uc.CloseRead()
+			} else {
+				_ = c.SetReadDeadline(time.Now())
+			}
+		}
+		mu.Unlock()
+	}()
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
 	for {
 		conn, err := ln.Accept()
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
 			return fmt.Errorf("accept: %w", err)
 		}
-		go handleConn(conn, handler)
+		mu.Lock()
+		if ctx.Err() != nil {
+			mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		conns[conn] = struct{}{}
+		wg.Add(1)
+		mu.Unlock()
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				delete(conns, conn)
+				mu.Unlock()
+			}()
+			handleConn(conn, handler, opts)
+		}()
 	}
 }
 
-func handleConn(c net.Conn, handler HandlerFunc) {
+func handleConn(c net.Conn, handler HandlerFunc, opts ServerOptions) {
 	defer c.Close()
 	rw := bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))
 	for {
-		line, err := rw.ReadBytes('\n')
+		_ = c.SetReadDeadline(time.Now().Add(opts.ReadTimeout))
+		line, err := readRequest(rw.Reader, opts.MaxRequestBytes)
 		if err != nil {
+			// Answer a client that sent a partial or oversized line; idle or
+			// closed connections are dropped silently.
+			if errors.Is(err, errRequestTooLarge) || len(line) > 0 {
+				writeResp(rw, ipcmsg.Response{OK: false, Error: "invalid request: " + requestErrorReason(err)})
+			}
 			return
 		}
 		var req ipcmsg.Request
@@ -60,6 +184,33 @@ func handleConn(c net.Conn, handler HandlerFunc) {
 	}
 }
 
+// readRequest reads one newline-terminated line of at most max bytes. On
+// error it returns whatever was read so far.
+func readRequest(r *bufio.Reader, max int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > max {
+			return append(line, chunk[:max-len(line)]...), errRequestTooLarge
+		}
+		line = append(line, chunk...)
+		if err == nil {
+			return line, nil
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, err
+		}
+	}
+}
+
+func requestErrorReason(err error) string {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return "read timeout"
+	}
+	return err.Error()
+}
+
 func writeResp(w *bufio.ReadWriter, resp ipcmsg.Response) {
 	b, err := json.Marshal(resp)
 	if err != nil {
@@ -71,4 +222,4 @@ func writeResp(w *bufio.ReadWriter, resp ipcmsg.Response) {
 }
 
 // ErrNotImplemented is returned for unknown methods.
-var ErrNotImplemented = errors.New("method not implemented")
+var ErrNotImplemented = ipcmsg.ErrNotImplemented
@@ -0,0 +1,267 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	ipcmsg "github.com/adrianmross/oci-context/pkg/ipc"
+)
+
+func TestServeContextDrainsInFlightRequests(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "d.sock")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(req ipcmsg.Request) (interface{}, error) {
+		close(started)
+		<-release
+		return "done", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- ServeContext(ctx, socketPath, handler, DefaultServerOptions()) }()
+
+	var conn net.Conn
+	var err error
+	for i := 0; i < 100; i++ {
+		if conn, err = net.Dial("unix", socketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	// An idle connection must not keep the server alive after cancel.
+	idle, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial idle: %v", err)
+	}
+	defer idle.Close()
+
+	if _, err := conn.Write([]byte(`{"method":"list"}` + "\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		t.Fatalf("server returned before in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	var resp ipcmsg.Response
+	if err := json.Unmarshal(line, &resp); err != nil || !resp.OK || resp.Data != "done" {
+		t.Fatalf("unexpected response %s (%v)", line, err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("serve: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("server did not stop after draining")
+	}
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Fatalf("expected socket removed, stat err=%v", err)
+	}
+}
+
+func startTestServer(t *testing.T, opts ServerOptions) string {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "d.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeContext(ctx, socketPath, func(ipcmsg.Request) (interface{}, error) { return "ok", nil }, opts)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-served
+	})
+	for i := 0; i < 100; i++ {
+		if c, err := net.Dial("unix", socketPath); err == nil {
+			c.Close()
+			return socketPath
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server did not start")
+	return ""
+}
+
+func readResponse(t *testing.T, conn net.Conn) (ipcmsg.Response, error) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return ipcmsg.Response{}, err
+	}
+	var resp ipcmsg.Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("unmarshal %s: %v", line, err)
+	}
+	// The server closes the connection after a rejected request.
+	if _, err := r.ReadByte(); err == nil {
+		t.Fatalf("expected connection closed after %s", line)
+	}
+	return resp, nil
+}
+
+func TestServeRejectsOversizedRequest(t *testing.T) {
+	socketPath := startTestServer(t, ServerOptions{MaxRequestBytes: 64})
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	go func() {
+		_, _ = conn.Write([]byte(`{"method":"` + strings.Repeat("x", 10000) + `"}` + "\n"))
+	}()
+	resp, err := readResponse(t, conn)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if resp.OK || resp.Error != "invalid request: request too large" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestServeTimesOutSlowRequest(t *testing.T) {
+	socketPath := startTestServer(t, ServerOptions{ReadTimeout: 50 * time.Millisecond})
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(`{"method":`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	resp, err := readResponse(t, conn)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if resp.OK || resp.Error != "invalid request: read timeout" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestServeOverTCPAndAbstractSockets(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	tcpAddr := "tcp://" + ln.Addr().String()
+	ln.Close()
+	addrs := []string{tcpAddr}
+	if runtime.GOOS == "linux" {
+		addrs = append(addrs, fmt.Sprintf("@oci-context-test-%d", time.Now().UnixNano()))
+	}
+
+	for _, addr := range addrs {
+		t.Run(addr, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() {
+				served <- ServeContext(ctx, addr, func(ipcmsg.Request) (interface{}, error) { return "pong", nil }, DefaultServerOptions())
+			}()
+			var conn *ipcmsg.Conn
+			for i := 0; i < 100; i++ {
+				if conn, err = ipcmsg.Dial(addr); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil {
+				cancel()
+				t.Fatalf("dial: %v", err)
+			}
+			if err := conn.SendRequest(ipcmsg.Request{Method: "ping"}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			var resp ipcmsg.Response
+			if err := conn.ReadResponse(&resp); err != nil || !resp.OK || resp.Data != "pong" {
+				t.Fatalf("unexpected response %+v (%v)", resp, err)
+			}
+			conn.Close()
+			cancel()
+			if err := <-served; err != nil {
+				t.Fatalf("serve: %v", err)
+			}
+			// Nothing named after the address may be left on disk.
+			if _, err := os.Stat(addr); !os.IsNotExist(err) {
+				t.Fatalf("unexpected file for %s: %v", addr, err)
+			}
+		})
+	}
+}
+
+func TestServeRefusesNonLoopbackTCP(t *testing.T) {
+	for _, addr := range []string{"tcp://0.0.0.0:7777", "tcp://:7777", "tcp://192.0.2.10:7777", "tcp://example.com:7777"} {
+		err := ServeContext(context.Background(), addr, func(ipcmsg.Request) (interface{}, error) { return nil, nil }, DefaultServerOptions())
+		if err == nil || !strings.Contains(err.Error(), "not a loopback address") {
+			t.Fatalf("expected %s to be refused, got %v", addr, err)
+		}
+	}
+}
+
+func TestNegotiateFallsBackForOlderDaemons(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		handler HandlerFunc
+		wantErr error
+	}{
+		{"current", func(ipcmsg.Request) (interface{}, error) {
+			return ipcmsg.Capabilities{Protocol: ipcmsg.ProtocolVersion, Version: "v1", Methods: []string{"ping", "capabilities"}}, nil
+		}, nil},
+		{"legacy", func(ipcmsg.Request) (interface{}, error) { return nil, ErrNotImplemented }, ipcmsg.ErrNotImplemented},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			socketPath := filepath.Join(t.TempDir(), "d.sock")
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() { served <- ServeContext(ctx, socketPath, tc.handler, DefaultServerOptions()) }()
+			defer func() {
+				cancel()
+				<-served
+			}()
+			var conn *ipcmsg.Conn
+			var err error
+			for i := 0; i < 100; i++ {
+				if conn, err = ipcmsg.Dial(socketPath); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			caps, err := conn.Negotiate()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("negotiate error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && (caps.Version != "v1" || !caps.Supports("ping") || caps.Supports("list")) {
+				t.Fatalf("unexpected capabilities %+v", caps)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle is a portable set of contexts for moving between machines with
+// export-config/import-config. Unlike Config it carries no options or
+// current context, so importing it never changes local settings.
+type Bundle struct {
+	SchemaVersion int       `yaml:"schema_version" json:"schema_version"`
+	Contexts      []Context `yaml:"contexts" json:"contexts"`
+}
+
+// MarshalBundle encodes contexts as a Bundle, in JSON when asJSON is set and
+// YAML otherwise.
+func MarshalBundle(contexts []Context, asJSON bool) ([]byte, error) {
+	b := Bundle{SchemaVersion: CurrentSchemaVersion, Contexts: contexts}
+	if asJSON {
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(b); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadBundle loads a bundle written by MarshalBundle (JSON for .json paths,
+// YAML otherwise). Unknown fields are rejected and older schema versions are
+// migrated like a config file.
+func ReadBundle(path string) (Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Bundle{}, err
+	}
+	var b Bundle
+	if isJSONPath(path) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&b)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&b)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Bundle{}, fmt.Errorf("parse bundle %s: %w", path, err)
+	}
+	cfg := Config{SchemaVersion: b.SchemaVersion, Contexts: b.Contexts}
+	if err := cfg.migrate(); err != nil {
+		return Bundle{}, fmt.Errorf("bundle %s: %w", path, err)
+	}
+	cfg.normalizeSources()
+	return Bundle{SchemaVersion: cfg.SchemaVersion, Contexts: cfg.Contexts}, nil
+}
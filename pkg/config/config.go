@@ -1,24 +1,31 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/gofrs/flock"
+	"github.com/adrianmross/oci-context/pkg/ocidutil"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the persisted state for oci-context.
 type Config struct {
+	SchemaVersion  int            `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
 	Options        Options        `yaml:"options" json:"options"`
 	Contexts       []Context      `yaml:"contexts" json:"contexts"`
 	TokenServices  []TokenService `yaml:"token_services,omitempty" json:"token_services,omitempty"`
 	CurrentContext string         `yaml:"current_context" json:"current_context"`
-	CurrentService string         `yaml:"current_service,omitempty" json:"current_service,omitempty"`
+	// PreviousContext is the context that was current before the last switch;
+	// `use -` returns to it.
+	PreviousContext string `yaml:"previous_context,omitempty" json:"previous_context,omitempty"`
+	CurrentService  string `yaml:"current_service,omitempty" json:"current_service,omitempty"`
 }
 
 // Options holds global settings.
@@ -27,6 +34,108 @@ type Options struct {
 	SocketPath     string   `yaml:"socket_path" json:"socket_path"`
 	DefaultProfile string   `yaml:"default_profile" json:"default_profile"`
 	DaemonContexts []string `yaml:"daemon_contexts,omitempty" json:"daemon_contexts,omitempty"`
+	// IdentityConcurrency bounds parallel OCI identity lookups (tenancy names, resolvers).
+	IdentityConcurrency int `yaml:"identity_concurrency,omitempty" json:"identity_concurrency,omitempty"`
+	// NetworkTimeout is a Go duration ("5s", "1m") for OCI calls made by status and the TUI.
+	NetworkTimeout string `yaml:"network_timeout,omitempty" json:"network_timeout,omitempty"`
+	// APIRetries is how many times a throttled (429) or server-side (5xx)
+	// identity API call is retried; 0 disables retries. Unset means DefaultAPIRetries.
+	APIRetries *int `yaml:"api_retries,omitempty" json:"api_retries,omitempty"`
+	// FavoriteCompartments are pinned to the top of the TUI compartment picker.
+	FavoriteCompartments []FavoriteCompartment `yaml:"favorite_compartments,omitempty" json:"favorite_compartments,omitempty"`
+	// Defaults is a partial context used as a team template: `add` fills any
+	// field not given on the command line from it, and `import` starts
+	// contexts at its compartment instead of the tenancy root. Name is unused.
+	Defaults *Context `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+}
+
+// DefaultCompartmentFor returns the template compartment for a new context
+// in tenancy, or "" unless the template pins both a compartment and that
+// tenancy: a compartment OCID is only valid in its own tenancy.
+func (o Options) DefaultCompartmentFor(tenancy string) string {
+	d := o.Defaults
+	if d == nil || d.CompartmentOCID == "" || d.TenancyOCID != tenancy {
+		return ""
+	}
+	return d.CompartmentOCID
+}
+
+// FavoriteCompartment is a pinned compartment. Favorites are scoped to their
+// tenancy so OCIDs from one tenancy never show up while browsing another.
+type FavoriteCompartment struct {
+	Tenancy string `yaml:"tenancy" json:"tenancy"`
+	ID      string `yaml:"id" json:"id"`
+	Name    string `yaml:"name" json:"name"`
+	// Parent lets the TUI go up a level after jumping to the favorite.
+	Parent string `yaml:"parent,omitempty" json:"parent,omitempty"`
+}
+
+// FavoriteCompartmentsFor returns the favorites pinned for tenancy, in the
+// order they were added.
+func (o Options) FavoriteCompartmentsFor(tenancy string) []FavoriteCompartment {
+	var out []FavoriteCompartment
+	for _, f := range o.FavoriteCompartments {
+		if f.Tenancy == tenancy {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ToggleFavoriteCompartment pins fav, or unpins it when the same tenancy and
+// ID are already pinned. It reports whether fav is pinned afterwards.
+func (c *Config) ToggleFavoriteCompartment(fav FavoriteCompartment) bool {
+	kept := make([]FavoriteCompartment, 0, len(c.Options.FavoriteCompartments)+1)
+	for _, f := range c.Options.FavoriteCompartments {
+		if f.Tenancy != fav.Tenancy || f.ID != fav.ID {
+			kept = append(kept, f)
+		}
+	}
+	pinned := len(kept) == len(c.Options.FavoriteCompartments)
+	if pinned {
+		kept = append(kept, fav)
+	}
+	// A fresh slice keeps copies of this Config that share the old one intact.
+	c.Options.FavoriteCompartments = kept
+	return pinned
+}
+
+// DefaultIdentityConcurrency is used when Options.IdentityConcurrency is unset.
+const DefaultIdentityConcurrency = 4
+
+// IdentityConcurrencyLimit returns the configured identity lookup concurrency or the default.
+func (o Options) IdentityConcurrencyLimit() int {
+	if o.IdentityConcurrency > 0 {
+		return o.IdentityConcurrency
+	}
+	return DefaultIdentityConcurrency
+}
+
+// DefaultAPIRetries is used when Options.APIRetries is unset.
+const DefaultAPIRetries = 3
+
+// APIRetryAttempts returns the total number of calls an identity API request
+// may make: the first call plus the configured or default retries.
+func (o Options) APIRetryAttempts() int {
+	if o.APIRetries == nil {
+		return DefaultAPIRetries + 1
+	}
+	return max(*o.APIRetries, 0) + 1
+}
+
+// NetworkTimeoutOr returns the configured network timeout, or def when unset.
+func (o Options) NetworkTimeoutOr(def time.Duration) (time.Duration, error) {
+	if strings.TrimSpace(o.NetworkTimeout) == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(o.NetworkTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("network_timeout %q: %w", o.NetworkTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("network_timeout %q must be positive", o.NetworkTimeout)
+	}
+	return d, nil
 }
 
 // Context describes a selectable OCI context.
@@ -39,8 +148,28 @@ type Context struct {
 	Region          string `yaml:"region" json:"region"`
 	User            string `yaml:"user" json:"user"`
 	Notes           string `yaml:"notes" json:"notes"`
+	Source          string `yaml:"source,omitempty" json:"source,omitempty"`
+	// Tags are free-form key=value labels for grouping (e.g. env=prod, team=core).
+	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// CompartmentState is the compartment's lifecycle state (ACTIVE, DELETED,
+	// ...) as seen when it was selected; empty when unknown.
+	CompartmentState string `yaml:"compartment_state,omitempty" json:"compartment_state,omitempty"`
 }
 
+// CompartmentStateActive is the lifecycle state of a usable compartment.
+const CompartmentStateActive = "ACTIVE"
+
+const (
+	// SourceManual marks contexts authored by hand (add/set/TUI).
+	SourceManual = "manual"
+	// SourceImport marks contexts created from OCI CLI profiles by import.
+	SourceImport = "import"
+)
+
+// ImportedNotes is the notes marker written by import before Source existed;
+// Load uses it to classify legacy imported contexts.
+const ImportedNotes = "imported from OCI CLI config"
+
 // TokenService describes a named token provider for command handoffs.
 type TokenService struct {
 	Name                      string   `yaml:"name" json:"name"`
@@ -152,11 +281,12 @@ func IsValidAuthMethod(v string) bool {
 	return false
 }
 
-// DefaultConfig returns the initial config.
+// DefaultConfig returns the initial config. OCIConfigPath is left empty so the
+// OCI CLI config is resolved at lookup time ($OCI_CLI_CONFIG_FILE, then
+// ~/.oci/config) rather than pinned when the file is first written.
 func DefaultConfig(home string) Config {
 	return Config{
 		Options: Options{
-			OCIConfigPath:  filepath.Join(home, ".oci", "config"),
 			SocketPath:     filepath.Join(home, ".oci-context", "daemon.sock"),
 			DefaultProfile: "",
 			DaemonContexts: []string{},
@@ -233,42 +363,123 @@ func EnsureDefaultConfig(path string) error {
 	return Save(path, cfg)
 }
 
-// Load reads config with a file lock for safety.
+// Load reads config with a file lock for safety (see LockTimeout). Unknown keys are rejected so
+// typos do not silently drop state, and older schema versions are migrated.
 func Load(path string) (Config, error) {
-	lock := flock.New(path + ".lock")
-	if err := lock.Lock(); err != nil {
+	lock, err := lockConfig(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer lock.Unlock()
+	return readConfig(path)
+}
+
+// LoadEdited re-reads path after an external edit and validates it while
+// holding the config lock. If the file no longer parses or validates, backup
+// is written back before the lock is released and the error is returned.
+func LoadEdited(path string, backup []byte) (Config, error) {
+	lock, err := lockConfig(path)
+	if err != nil {
 		return Config{}, err
 	}
 	defer lock.Unlock()
 
+	cfg, err := readConfig(path)
+	if err == nil {
+		err = Validate(cfg)
+	}
+	if err != nil {
+		if restoreErr := writeFileAtomic(path, backup, 0o600); restoreErr != nil {
+			return Config{}, fmt.Errorf("%w (restore failed: %v)", err, restoreErr)
+		}
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// readConfig decodes and migrates path; callers must hold the config lock.
+func readConfig(path string) (Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return Config{}, err
 	}
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return Config{}, err
+	if err := decodeConfig(path, data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
 	}
+	if err := cfg.migrate(); err != nil {
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	// Hand-edited contexts may still omit source.
+	cfg.normalizeSources()
 	return cfg, nil
 }
 
-// Save writes config with a file lock.
+// isJSONPath reports whether path should be read and written as JSON rather
+// than YAML.
+func isJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// decodeConfig rejects unknown fields in either format; an empty file decodes
+// to the zero Config.
+func decodeConfig(path string, data []byte, cfg *Config) error {
+	if !isJSONPath(path) {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line := 1 + bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// normalizeSources fills Source for contexts written before provenance was tracked.
+func (c *Config) normalizeSources() {
+	for i := range c.Contexts {
+		if c.Contexts[i].Source != "" {
+			continue
+		}
+		if c.Contexts[i].Notes == ImportedNotes {
+			c.Contexts[i].Source = SourceImport
+		} else {
+			c.Contexts[i].Source = SourceManual
+		}
+	}
+}
+
+// Save writes config with a file lock (see LockTimeout). YAML files keep their
+// comments and key order (see marshalYAMLPreserving).
 func Save(path string, cfg Config) error {
-	lock := flock.New(path + ".lock")
-	if err := lock.Lock(); err != nil {
+	lock, err := lockConfig(path)
+	if err != nil {
 		return err
 	}
 	defer lock.Unlock()
 
+	cfg.SchemaVersion = CurrentSchemaVersion
 	var data []byte
-	var err error
-	if strings.EqualFold(filepath.Ext(path), ".json") {
+	if isJSONPath(path) {
 		data, err = json.MarshalIndent(&cfg, "", "  ")
 		if err == nil {
 			data = append(data, '\n')
 		}
 	} else {
-		data, err = yaml.Marshal(&cfg)
+		data, err = marshalYAMLPreserving(path, &cfg)
 	}
 	if err != nil {
 		return err
@@ -279,6 +490,15 @@ func Save(path string, cfg Config) error {
 	return writeFileAtomic(path, data, 0o600)
 }
 
+// writeTempFile writes data to the temp file; tests replace it to simulate
+// failures such as a full disk.
+var writeTempFile = func(f *os.File, data []byte) error {
+	_, err := f.Write(data)
+	return err
+}
+
+// writeFileAtomic writes data to a temp file in the same directory and renames
+// it over path, so a failed or interrupted write never truncates the original.
 func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
 	dir := filepath.Dir(path)
 	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
@@ -289,7 +509,7 @@ func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
 	defer func() {
 		_ = os.Remove(tmpPath)
 	}()
-	if _, err := tmp.Write(data); err != nil {
+	if err := writeTempFile(tmp, data); err != nil {
 		_ = tmp.Close()
 		return err
 	}
@@ -297,6 +517,10 @@ func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
 		_ = tmp.Close()
 		return err
 	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
 	if err := tmp.Close(); err != nil {
 		return err
 	}
@@ -316,8 +540,12 @@ func (c Config) GetContext(name string) (Context, error) {
 	return Context{}, ErrContextNotFound
 }
 
-// UpsertContext adds or updates a context.
+// UpsertContext adds or updates a context. Contexts without a Source are
+// recorded as manual.
 func (c *Config) UpsertContext(ctx Context) error {
+	if ctx.Source == "" {
+		ctx.Source = SourceManual
+	}
 	for i, existing := range c.Contexts {
 		if existing.Name == ctx.Name {
 			c.Contexts[i] = ctx
@@ -347,9 +575,60 @@ func (c *Config) DeleteContext(name string) error {
 	if c.CurrentContext == name {
 		c.CurrentContext = ""
 	}
+	if c.PreviousContext == name {
+		c.PreviousContext = ""
+	}
+	return nil
+}
+
+// SwitchContext makes name current and remembers the context it replaces as
+// PreviousContext. Re-selecting the current context changes nothing.
+func (c *Config) SwitchContext(name string) {
+	if c.CurrentContext == name {
+		return
+	}
+	c.PreviousContext = c.CurrentContext
+	c.CurrentContext = name
+}
+
+// MoveContext shifts a context delta positions in Contexts (negative moves it
+// toward the top). Moves past either end clamp to the first or last position.
+func (c *Config) MoveContext(name string, delta int) error {
+	idx := -1
+	for i, ctx := range c.Contexts {
+		if ctx.Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return ErrContextNotFound
+	}
+	dst := min(max(idx+delta, 0), len(c.Contexts)-1)
+	ctx := c.Contexts[idx]
+	c.Contexts = append(c.Contexts[:idx], c.Contexts[idx+1:]...)
+	c.Contexts = append(c.Contexts[:dst], append([]Context{ctx}, c.Contexts[dst:]...)...)
 	return nil
 }
 
+// SetCompartment points ctx at compartment id, recording state when known. An
+// unknown state ("") clears the recorded one only if the compartment changed.
+func (ctx *Context) SetCompartment(id, state string) {
+	if id != ctx.CompartmentOCID || state != "" {
+		ctx.CompartmentState = state
+	}
+	ctx.CompartmentOCID = id
+}
+
+// InactiveCompartmentState returns the recorded compartment state when it is
+// known and not ACTIVE, else "".
+func (ctx Context) InactiveCompartmentState() string {
+	if ctx.CompartmentState == "" || ctx.CompartmentState == CompartmentStateActive {
+		return ""
+	}
+	return ctx.CompartmentState
+}
+
 // Validate minimal required fields.
 func (ctx Context) Validate() error {
 	if ctx.Name == "" {
@@ -364,8 +643,36 @@ func (ctx Context) Validate() error {
 	if ctx.CompartmentOCID == "" {
 		return fmt.Errorf("context compartment_ocid is required")
 	}
+	if !ValidOCID("tenancy", ctx.TenancyOCID) {
+		return fmt.Errorf("context tenancy_ocid %q does not look like a tenancy OCID", ctx.TenancyOCID)
+	}
+	if !ValidOCID("compartment", ctx.CompartmentOCID) {
+		return fmt.Errorf("context compartment_ocid %q does not look like a compartment OCID", ctx.CompartmentOCID)
+	}
+	// user may be a free-form hint; only OCID-shaped values are checked. Session
+	// auth profiles without a user carry the tenancy OCID as a placeholder.
+	if strings.HasPrefix(ctx.User, "ocid1.") && !ValidOCID("user", ctx.User) && !ValidOCID("tenancy", ctx.User) {
+		return fmt.Errorf("context user %q does not look like a user OCID", ctx.User)
+	}
 	if !IsValidAuthMethod(ctx.AuthMethod) {
 		return fmt.Errorf("context auth_method %q is invalid", ctx.AuthMethod)
 	}
 	return nil
 }
+
+// ValidOCID reports whether s has the ocid1.<type>.<realm>..<unique> shape
+// with the type segment expected for kind ("tenancy", "compartment", or
+// "user"). A compartment may be the tenancy OCID, which names the root
+// compartment.
+func ValidOCID(kind, s string) bool {
+	switch kind {
+	case "tenancy":
+		return ocidutil.IsTenancy(s)
+	case "compartment":
+		return ocidutil.IsCompartment(s)
+	case "user":
+		return ocidutil.IsUser(s)
+	default:
+		return ocidutil.IsType(s, ocidutil.Type(kind))
+	}
+}
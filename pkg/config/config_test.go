@@ -2,10 +2,12 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func testConfig() Config {
@@ -96,3 +98,392 @@ func containsString(values []string, want string) bool {
 	}
 	return false
 }
+
+func TestValidateChecksOCIDTypes(t *testing.T) {
+	ctx := testConfig().Contexts[0]
+	if err := ctx.Validate(); err != nil {
+		t.Fatalf("expected valid context, got %v", err)
+	}
+
+	rootCompartment := ctx
+	rootCompartment.CompartmentOCID = ctx.TenancyOCID
+	if err := rootCompartment.Validate(); err != nil {
+		t.Fatalf("root compartment should be accepted, got %v", err)
+	}
+
+	badTenancy := ctx
+	badTenancy.TenancyOCID = "ocid1.compartment.oc1..bbbb"
+	if err := badTenancy.Validate(); err == nil || !strings.Contains(err.Error(), "tenancy_ocid \"ocid1.compartment.oc1..bbbb\" does not look like a tenancy OCID") {
+		t.Fatalf("expected tenancy type error, got %v", err)
+	}
+
+	badCompartment := ctx
+	badCompartment.CompartmentOCID = "not-an-ocid"
+	if err := badCompartment.Validate(); err == nil || !strings.Contains(err.Error(), "does not look like a compartment OCID") {
+		t.Fatalf("expected malformed compartment error, got %v", err)
+	}
+
+	userHint := ctx
+	userHint.User = "alice@example.com"
+	if err := userHint.Validate(); err != nil {
+		t.Fatalf("non-OCID user hint should be accepted, got %v", err)
+	}
+
+	badUser := ctx
+	badUser.User = "ocid1.group.oc1..gggg"
+	if err := badUser.Validate(); err == nil || !strings.Contains(err.Error(), "does not look like a user OCID") {
+		t.Fatalf("expected user type error, got %v", err)
+	}
+}
+
+func TestValidOCID(t *testing.T) {
+	cases := []struct {
+		kind, ocid string
+		want       bool
+	}{
+		{"tenancy", "ocid1.tenancy.oc1..aaaa", true},
+		{"tenancy", "us-phoenix-1", false},
+		{"compartment", "ocid1.compartment.oc1..bbbb", true},
+		{"compartment", "ocid1.tenancy.oc1..aaaa", true},
+		{"compartment", "ocid1.user.oc1..cccc", false},
+		{"user", "ocid1.user.oc1..cccc", true},
+		{"user", "ocid1.user", false},
+	}
+	for _, tc := range cases {
+		if got := ValidOCID(tc.kind, tc.ocid); got != tc.want {
+			t.Errorf("ValidOCID(%q, %q) = %t, want %t", tc.kind, tc.ocid, got, tc.want)
+		}
+	}
+}
+
+func TestLoadDefaultsContextSource(t *testing.T) {
+	cfg := testConfig()
+	imported := cfg.Contexts[0]
+	imported.Name = "legacy-import"
+	imported.Notes = ImportedNotes
+	tagged := cfg.Contexts[0]
+	tagged.Name = "tagged"
+	tagged.Source = SourceImport
+	cfg.Contexts = append(cfg.Contexts, imported, tagged)
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := map[string]string{"dev": SourceManual, "legacy-import": SourceImport, "tagged": SourceImport}
+	for _, ctx := range loaded.Contexts {
+		if ctx.Source != want[ctx.Name] {
+			t.Fatalf("context %s source = %q, want %q", ctx.Name, ctx.Source, want[ctx.Name])
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	data := "options:\n  oci_config_path: /tmp/oci\ncontexts: []\ncurent_context: dev\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "curent_context") {
+		t.Fatalf("expected unknown field error naming curent_context, got %v", err)
+	}
+}
+
+func TestLoadMigratesAndSaveStampsSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	legacy := "contexts:\n  - name: old\n    profile: DEFAULT\n    tenancy_ocid: ocid1.tenancy.oc1..aaaa\n    compartment_ocid: ocid1.tenancy.oc1..aaaa\n    region: us-ashburn-1\n    user: \"\"\n    notes: " + ImportedNotes + "\ncurrent_context: old\n"
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load legacy: %v", err)
+	}
+	if cfg.SchemaVersion != CurrentSchemaVersion || cfg.Contexts[0].Source != SourceImport {
+		t.Fatalf("expected migration to v%d with import source, got v%d %+v", CurrentSchemaVersion, cfg.SchemaVersion, cfg.Contexts[0])
+	}
+
+	cfg.SchemaVersion = 0
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(b), "schema_version: 1") {
+		t.Fatalf("expected schema_version stamped, got:\n%s", b)
+	}
+
+	if err := os.WriteFile(path, []byte("schema_version: 99\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Fatalf("expected newer schema error, got %v", err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	if err := Validate(testConfig()); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	dup := testConfig()
+	dup.Contexts = append(dup.Contexts, dup.Contexts[0])
+	if err := Validate(dup); !errors.Is(err, ErrDuplicateName) {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+
+	dangling := testConfig()
+	dangling.CurrentContext = "missing"
+	if err := Validate(dangling); !errors.Is(err, ErrContextNotFound) {
+		t.Fatalf("expected dangling current_context error, got %v", err)
+	}
+
+	invalid := testConfig()
+	invalid.Contexts[0].Profile = ""
+	if err := Validate(invalid); err == nil || !strings.Contains(err.Error(), "contexts[0] (dev)") {
+		t.Fatalf("expected indexed context error, got %v", err)
+	}
+
+	named := testConfig()
+	named.Options.Defaults = &Context{Name: "team", Region: "us-ashburn-1"}
+	if err := Validate(named); err == nil || !strings.Contains(err.Error(), "options.defaults: name") {
+		t.Fatalf("expected a named template to be rejected, got %v", err)
+	}
+
+	unpinned := testConfig()
+	unpinned.Options.Defaults = &Context{CompartmentOCID: "ocid1.compartment.oc1..shared"}
+	if err := Validate(unpinned); err == nil || !strings.Contains(err.Error(), "compartment_ocid needs tenancy_ocid") {
+		t.Fatalf("expected a template compartment without a tenancy to be rejected, got %v", err)
+	}
+	if got := unpinned.Options.DefaultCompartmentFor("ocid1.tenancy.oc1..any"); got != "" {
+		t.Fatalf("expected no template compartment without a tenancy, got %q", got)
+	}
+}
+
+func TestSaveWriteErrorLeavesOriginalIntact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	if err := Save(path, testConfig()); err != nil {
+		t.Fatalf("initial save: %v", err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	orig := writeTempFile
+	t.Cleanup(func() { writeTempFile = orig })
+	writeTempFile = func(f *os.File, data []byte) error {
+		// Write a partial payload, then fail as a full disk would.
+		_, _ = f.Write(data[:len(data)/2])
+		return errors.New("no space left on device")
+	}
+
+	changed := testConfig()
+	changed.Contexts = nil
+	changed.CurrentContext = ""
+	if err := Save(path, changed); err == nil || !strings.Contains(err.Error(), "no space left") {
+		t.Fatalf("expected simulated write error, got %v", err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read after failed save: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Fatalf("original config changed after failed save:\n%s", after)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 config, got %v", fi.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Fatalf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestJSONConfigRoundTripAndStrictDecode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".oci-context.json")
+	cfg := testConfig()
+	cfg.Contexts[0].Tags = map[string]string{"env": "dev"}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save json: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load json: %v", err)
+	}
+	loaded.CurrentService = "changed"
+	if err := Save(path, loaded); err != nil {
+		t.Fatalf("resave json: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	if !json.Valid(b) || !strings.Contains(string(b), `"current_service": "changed"`) || !strings.Contains(string(b), `"env": "dev"`) {
+		t.Fatalf("expected json written back, got %s", b)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"curent_context": "dev"}`), 0o600); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "curent_context") {
+		t.Fatalf("expected unknown json field error, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{\n  \"contexts\": [,]\n}"), 0o600); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected json syntax error with line, got %v", err)
+	}
+}
+
+func TestMoveContextClamps(t *testing.T) {
+	names := func(c Config) string {
+		var out []string
+		for _, ctx := range c.Contexts {
+			out = append(out, ctx.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	cfg := Config{Contexts: []Context{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}}
+	steps := []struct {
+		name  string
+		delta int
+		want  string
+	}{
+		{"c", -1, "a,c,b,d"},
+		{"c", -10, "c,a,b,d"},
+		{"a", 1, "c,b,a,d"},
+		{"c", 99, "b,a,d,c"},
+		{"c", 1, "b,a,d,c"},
+		{"b", 0, "b,a,d,c"},
+	}
+	for _, s := range steps {
+		if err := cfg.MoveContext(s.name, s.delta); err != nil {
+			t.Fatalf("move %s %d: %v", s.name, s.delta, err)
+		}
+		if got := names(cfg); got != s.want {
+			t.Fatalf("move %s %d: got %s, want %s", s.name, s.delta, got, s.want)
+		}
+	}
+	if err := cfg.MoveContext("missing", 1); !errors.Is(err, ErrContextNotFound) {
+		t.Fatalf("expected ErrContextNotFound, got %v", err)
+	}
+}
+
+func TestNetworkTimeoutOr(t *testing.T) {
+	if d, err := (Options{}).NetworkTimeoutOr(15 * time.Second); err != nil || d != 15*time.Second {
+		t.Fatalf("expected default, got %s (%v)", d, err)
+	}
+	if d, err := (Options{NetworkTimeout: "1m"}).NetworkTimeoutOr(15 * time.Second); err != nil || d != time.Minute {
+		t.Fatalf("expected 1m, got %s (%v)", d, err)
+	}
+	cfg := testConfig()
+	cfg.Options.NetworkTimeout = "soon"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "options.network_timeout") {
+		t.Fatalf("expected invalid network_timeout error, got %v", err)
+	}
+	cfg.Options.NetworkTimeout = "-5s"
+	if err := Validate(cfg); err == nil {
+		t.Fatalf("expected non-positive network_timeout to be rejected")
+	}
+}
+
+func TestAPIRetryAttempts(t *testing.T) {
+	if got := (Options{}).APIRetryAttempts(); got != DefaultAPIRetries+1 {
+		t.Fatalf("expected default attempts, got %d", got)
+	}
+	zero := 0
+	if got := (Options{APIRetries: &zero}).APIRetryAttempts(); got != 1 {
+		t.Fatalf("expected api_retries: 0 to disable retries, got %d attempts", got)
+	}
+	cfg := testConfig()
+	negative := -1
+	cfg.Options.APIRetries = &negative
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "options.api_retries") {
+		t.Fatalf("expected negative api_retries to be rejected, got %v", err)
+	}
+}
+
+func TestToggleFavoriteCompartmentIsPerTenancy(t *testing.T) {
+	cfg := testConfig()
+	app := FavoriteCompartment{Tenancy: "ocid1.tenancy.oc1..a", ID: "ocid1.compartment.oc1..app", Name: "app"}
+	other := FavoriteCompartment{Tenancy: "ocid1.tenancy.oc1..b", ID: "ocid1.compartment.oc1..app", Name: "app"}
+	if !cfg.ToggleFavoriteCompartment(app) || !cfg.ToggleFavoriteCompartment(other) {
+		t.Fatalf("expected both favorites to be pinned")
+	}
+	if got := cfg.Options.FavoriteCompartmentsFor("ocid1.tenancy.oc1..a"); len(got) != 1 || got[0] != app {
+		t.Fatalf("unexpected favorites for tenancy a: %+v", got)
+	}
+	snapshot := cfg
+	if cfg.ToggleFavoriteCompartment(app) {
+		t.Fatalf("expected second toggle to unpin")
+	}
+	if got := cfg.Options.FavoriteCompartmentsFor("ocid1.tenancy.oc1..a"); len(got) != 0 {
+		t.Fatalf("expected tenancy a favorites cleared, got %+v", got)
+	}
+	if len(cfg.Options.FavoriteCompartments) != 1 || len(snapshot.Options.FavoriteCompartments) != 2 {
+		t.Fatalf("unpin should leave tenancy b and earlier copies intact: %+v / %+v", cfg.Options.FavoriteCompartments, snapshot.Options.FavoriteCompartments)
+	}
+}
+
+func TestSwitchAndDeleteTrackPreviousContext(t *testing.T) {
+	cfg := testConfig()
+	cfg.CurrentContext = "dev"
+	cfg.SwitchContext("dev")
+	if cfg.PreviousContext != "" {
+		t.Fatalf("re-selecting current should not set previous, got %q", cfg.PreviousContext)
+	}
+	other := cfg.Contexts[0]
+	other.Name = "other"
+	if err := cfg.UpsertContext(other); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	cfg.SwitchContext("other")
+	if cfg.CurrentContext != "other" || cfg.PreviousContext != "dev" {
+		t.Fatalf("unexpected switch result: current=%q previous=%q", cfg.CurrentContext, cfg.PreviousContext)
+	}
+	if err := cfg.DeleteContext("dev"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if cfg.PreviousContext != "" {
+		t.Fatalf("deleting the previous context should clear it, got %q", cfg.PreviousContext)
+	}
+}
+
+func TestSetCompartmentTracksState(t *testing.T) {
+	ctx := Context{CompartmentOCID: "ocid1.compartment.oc1..a", CompartmentState: "DELETED"}
+	ctx.SetCompartment("ocid1.compartment.oc1..a", "")
+	if ctx.CompartmentState != "DELETED" {
+		t.Fatalf("unknown state for the same compartment should keep the record, got %q", ctx.CompartmentState)
+	}
+	if got := ctx.InactiveCompartmentState(); got != "DELETED" {
+		t.Fatalf("expected DELETED to be reported inactive, got %q", got)
+	}
+	ctx.SetCompartment("ocid1.compartment.oc1..b", "")
+	if ctx.CompartmentOCID != "ocid1.compartment.oc1..b" || ctx.CompartmentState != "" {
+		t.Fatalf("changing compartment should drop the stale state: %+v", ctx)
+	}
+	ctx.SetCompartment("ocid1.compartment.oc1..b", CompartmentStateActive)
+	if ctx.CompartmentState != CompartmentStateActive || ctx.InactiveCompartmentState() != "" {
+		t.Fatalf("expected ACTIVE recorded and not reported: %+v", ctx)
+	}
+}
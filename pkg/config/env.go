@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment variables that define an ephemeral context for --from-env.
+const (
+	EnvContextName        = "OCI_CONTEXT_NAME"
+	EnvContextProfile     = "OCI_CONTEXT_PROFILE"
+	EnvContextAuthMethod  = "OCI_CONTEXT_AUTH_METHOD"
+	EnvContextTenancy     = "OCI_CONTEXT_TENANCY"
+	EnvContextCompartment = "OCI_CONTEXT_COMPARTMENT"
+	EnvContextRegion      = "OCI_CONTEXT_REGION"
+	EnvContextUser        = "OCI_CONTEXT_USER"
+	EnvContextOCIConfig   = "OCI_CONTEXT_OCI_CONFIG"
+)
+
+// EnvContextSource marks contexts synthesized from environment variables.
+const EnvContextSource = "env"
+
+// EnvConfigPath names the config file for the CLI when neither --config nor
+// --global is given. It takes precedence over project discovery.
+const EnvConfigPath = "OCI_CONTEXT_CONFIG"
+
+// ConfigFromEnv builds an in-memory config holding a single current context
+// defined by OCI_CONTEXT_* variables. getenv is usually os.Getenv. The
+// compartment defaults to the tenancy root and the name defaults to "env".
+func ConfigFromEnv(getenv func(string) string) (Config, error) {
+	get := func(key string) string { return strings.TrimSpace(getenv(key)) }
+	ctx := Context{
+		Name:            get(EnvContextName),
+		Profile:         get(EnvContextProfile),
+		AuthMethod:      get(EnvContextAuthMethod),
+		TenancyOCID:     get(EnvContextTenancy),
+		CompartmentOCID: get(EnvContextCompartment),
+		Region:          get(EnvContextRegion),
+		User:            get(EnvContextUser),
+		Source:          EnvContextSource,
+	}
+	if ctx.Name == "" {
+		ctx.Name = "env"
+	}
+	if ctx.CompartmentOCID == "" {
+		ctx.CompartmentOCID = ctx.TenancyOCID
+	}
+	if err := ctx.Validate(); err != nil {
+		return Config{}, err
+	}
+	return Config{
+		Options:        Options{OCIConfigPath: get(EnvContextOCIConfig)},
+		Contexts:       []Context{ctx},
+		CurrentContext: ctx.Name,
+	}, nil
+}
+
+// Variable naming styles for exported context environment variables.
+const (
+	// EnvStyleOCIContext pairs the OCI CLI profile, region, and config file
+	// with OCI_TENANCY_OCID, OCI_COMPARTMENT_OCID, and OCI_REGION, the names
+	// the OCI Terraform provider and most scripts read.
+	EnvStyleOCIContext = "oci-context"
+	// EnvStyleOCICLI uses the OCI CLI's own OCI_CLI_* names wherever one
+	// exists; the compartment has none and stays OCI_COMPARTMENT_OCID.
+	EnvStyleOCICLI = "oci-cli"
+)
+
+// ParseEnvStyle validates a variable naming style; "" means EnvStyleOCIContext.
+func ParseEnvStyle(style string) (string, error) {
+	switch s := strings.ToLower(strings.TrimSpace(style)); s {
+	case "", EnvStyleOCIContext:
+		return EnvStyleOCIContext, nil
+	case EnvStyleOCICLI:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unsupported variable style %q (use %s or %s)", style, EnvStyleOCIContext, EnvStyleOCICLI)
+	}
+}
+
+// EnvNames lists every variable ExportEnv can set for style, in output order.
+func EnvNames(style string) []string {
+	if style == EnvStyleOCICLI {
+		return []string{"OCI_CLI_PROFILE", "OCI_CLI_REGION", "OCI_CLI_CONFIG_FILE", "OCI_CLI_TENANCY", "OCI_COMPARTMENT_OCID"}
+	}
+	return []string{"OCI_CLI_PROFILE", "OCI_CLI_REGION", "OCI_CLI_CONFIG_FILE", "OCI_TENANCY_OCID", "OCI_COMPARTMENT_OCID", "OCI_REGION"}
+}
+
+// ExportEnv returns the name/value pairs that describe ctx in style, as the
+// CLI and daemon export them. Profile, region, and config file are omitted
+// when empty; tenancy and compartment are always present.
+func ExportEnv(ctx Context, ociConfigPath, style string) [][2]string {
+	var pairs [][2]string
+	if ctx.Profile != "" {
+		pairs = append(pairs, [2]string{"OCI_CLI_PROFILE", ctx.Profile})
+	}
+	if ctx.Region != "" {
+		pairs = append(pairs, [2]string{"OCI_CLI_REGION", ctx.Region})
+	}
+	if ociConfigPath != "" {
+		pairs = append(pairs, [2]string{"OCI_CLI_CONFIG_FILE", ociConfigPath})
+	}
+	if style == EnvStyleOCICLI {
+		return append(pairs,
+			[2]string{"OCI_CLI_TENANCY", ctx.TenancyOCID},
+			[2]string{"OCI_COMPARTMENT_OCID", ctx.CompartmentOCID},
+		)
+	}
+	pairs = append(pairs,
+		[2]string{"OCI_TENANCY_OCID", ctx.TenancyOCID},
+		[2]string{"OCI_COMPARTMENT_OCID", ctx.CompartmentOCID},
+	)
+	if ctx.Region != "" {
+		pairs = append(pairs, [2]string{"OCI_REGION", ctx.Region})
+	}
+	return pairs
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// EnvLockTimeout overrides how long Load and Save wait for the config lock.
+// It accepts a Go duration ("10s", "500ms") or a whole number of seconds.
+const EnvLockTimeout = "OCI_CONTEXT_LOCK_TIMEOUT"
+
+// DefaultLockTimeout is used when EnvLockTimeout is unset or invalid.
+const DefaultLockTimeout = 5 * time.Second
+
+// ErrConfigLocked is returned when the config lock cannot be acquired in time.
+var ErrConfigLocked = errors.New("config is locked by another process")
+
+const lockRetryDelay = 50 * time.Millisecond
+
+// LockTimeout returns the configured lock timeout.
+func LockTimeout() time.Duration {
+	v := strings.TrimSpace(os.Getenv(EnvLockTimeout))
+	if v == "" {
+		return DefaultLockTimeout
+	}
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d
+	}
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return DefaultLockTimeout
+}
+
+// lockConfig acquires the lock file next to path, giving up after LockTimeout.
+func lockConfig(path string) (*flock.Flock, error) {
+	timeout := LockTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	lock := flock.New(path + ".lock")
+	start := time.Now()
+	ok, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if ok {
+		slog.Debug("config lock acquired", "path", lock.Path(), "waited", time.Since(start))
+		return lock, nil
+	}
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		slog.Debug("config lock timed out", "path", lock.Path(), "timeout", timeout)
+		return nil, fmt.Errorf("%w (timeout after %s): %s.lock", ErrConfigLocked, timeout, path)
+	}
+	return nil, err
+}
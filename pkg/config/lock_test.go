@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+func TestLockTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: DefaultLockTimeout},
+		{value: "250ms", want: 250 * time.Millisecond},
+		{value: "3", want: 3 * time.Second},
+		{value: "nonsense", want: DefaultLockTimeout},
+		{value: "-1s", want: DefaultLockTimeout},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvLockTimeout, tt.value)
+		if got := LockTimeout(); got != tt.want {
+			t.Fatalf("LockTimeout(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLoadAndSaveTimeOutWhenLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := Save(path, testConfig()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	holder := flock.New(path + ".lock")
+	if err := holder.Lock(); err != nil {
+		t.Fatalf("hold lock: %v", err)
+	}
+	defer holder.Unlock()
+
+	t.Setenv(EnvLockTimeout, "100ms")
+	start := time.Now()
+	if _, err := Load(path); !errors.Is(err, ErrConfigLocked) {
+		t.Fatalf("expected ErrConfigLocked from Load, got %v", err)
+	}
+	if err := Save(path, testConfig()); !errors.Is(err, ErrConfigLocked) {
+		t.Fatalf("expected ErrConfigLocked from Save, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("lock timeout not honored, took %s", elapsed)
+	}
+
+	if err := holder.Unlock(); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("load after unlock: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+// Merge layers overlay on top of base, as `--merge` does with a project config
+// over the global one. Contexts and token services are matched by name: an
+// overlay entry replaces the base entry in place and new ones follow the base
+// list. Overlay options, current/previous context, and current service win
+// when set; favorites from both are kept. Neither input is modified.
+func Merge(base, overlay Config) Config {
+	out := base
+	out.SchemaVersion = max(base.SchemaVersion, overlay.SchemaVersion)
+	out.Contexts = mergeByName(base.Contexts, overlay.Contexts, func(c Context) string { return c.Name })
+	out.TokenServices = mergeByName(base.TokenServices, overlay.TokenServices, func(s TokenService) string { return s.Name })
+	out.Options = mergeOptions(base.Options, overlay.Options)
+	if overlay.CurrentContext != "" {
+		out.CurrentContext = overlay.CurrentContext
+	}
+	if overlay.PreviousContext != "" {
+		out.PreviousContext = overlay.PreviousContext
+	}
+	if overlay.CurrentService != "" {
+		out.CurrentService = overlay.CurrentService
+	}
+	return out
+}
+
+func mergeByName[T any](base, overlay []T, name func(T) string) []T {
+	out := make([]T, 0, len(base)+len(overlay))
+	index := make(map[string]int, len(base))
+	for _, item := range base {
+		index[name(item)] = len(out)
+		out = append(out, item)
+	}
+	for _, item := range overlay {
+		if i, ok := index[name(item)]; ok {
+			out[i] = item
+			continue
+		}
+		index[name(item)] = len(out)
+		out = append(out, item)
+	}
+	return out
+}
+
+func mergeOptions(base, overlay Options) Options {
+	out := base
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&out.OCIConfigPath, overlay.OCIConfigPath)
+	set(&out.SocketPath, overlay.SocketPath)
+	set(&out.DefaultProfile, overlay.DefaultProfile)
+	set(&out.NetworkTimeout, overlay.NetworkTimeout)
+	if len(overlay.DaemonContexts) > 0 {
+		out.DaemonContexts = append([]string(nil), overlay.DaemonContexts...)
+	}
+	if overlay.IdentityConcurrency > 0 {
+		out.IdentityConcurrency = overlay.IdentityConcurrency
+	}
+	if overlay.APIRetries != nil {
+		n := *overlay.APIRetries
+		out.APIRetries = &n
+	}
+	if overlay.Defaults != nil {
+		d := *overlay.Defaults
+		out.Defaults = &d
+	}
+	out.FavoriteCompartments = append([]FavoriteCompartment(nil), base.FavoriteCompartments...)
+	for _, f := range overlay.FavoriteCompartments {
+		dup := false
+		for _, b := range base.FavoriteCompartments {
+			if b.Tenancy == f.Tenancy && b.ID == f.ID {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out.FavoriteCompartments = append(out.FavoriteCompartments, f)
+		}
+	}
+	return out
+}
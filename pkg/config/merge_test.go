@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeProjectOverridesGlobalByName(t *testing.T) {
+	base := Config{
+		Options: Options{OCIConfigPath: "~/.oci/config", NetworkTimeout: "5s", DaemonContexts: []string{"shared"}},
+		Contexts: []Context{
+			{Name: "shared", Profile: "DEFAULT", Region: "us-ashburn-1"},
+			{Name: "dev", Profile: "DEV", Region: "us-phoenix-1"},
+		},
+		CurrentContext: "shared",
+	}
+	overlay := Config{
+		Options: Options{NetworkTimeout: "30s"},
+		Contexts: []Context{
+			{Name: "dev", Profile: "DEV", Region: "eu-frankfurt-1"},
+			{Name: "project", Profile: "PROJ"},
+		},
+	}
+
+	got := Merge(base, overlay)
+	var names []string
+	for _, c := range got.Contexts {
+		names = append(names, c.Name)
+	}
+	if want := []string{"shared", "dev", "project"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("context order = %v, want %v", names, want)
+	}
+	if got.Contexts[1].Region != "eu-frankfurt-1" {
+		t.Fatalf("expected project dev to override global dev, got %+v", got.Contexts[1])
+	}
+	if got.CurrentContext != "shared" {
+		t.Fatalf("expected global current context when project sets none, got %q", got.CurrentContext)
+	}
+	if got.Options.NetworkTimeout != "30s" || got.Options.OCIConfigPath != "~/.oci/config" || !reflect.DeepEqual(got.Options.DaemonContexts, []string{"shared"}) {
+		t.Fatalf("unexpected merged options %+v", got.Options)
+	}
+
+	overlay.CurrentContext = "project"
+	if got := Merge(base, overlay); got.CurrentContext != "project" {
+		t.Fatalf("expected project current context to win, got %q", got.CurrentContext)
+	}
+	if base.Contexts[1].Region != "us-phoenix-1" || len(base.Contexts) != 2 {
+		t.Fatalf("Merge modified base: %+v", base.Contexts)
+	}
+}
+
+func TestMergeKeepsFavoritesFromBoth(t *testing.T) {
+	fav := func(id string) FavoriteCompartment { return FavoriteCompartment{Tenancy: "t", ID: id} }
+	got := Merge(
+		Config{Options: Options{FavoriteCompartments: []FavoriteCompartment{fav("a"), fav("b")}}},
+		Config{Options: Options{FavoriteCompartments: []FavoriteCompartment{fav("b"), fav("c")}}},
+	)
+	want := []FavoriteCompartment{fav("a"), fav("b"), fav("c")}
+	if !reflect.DeepEqual(got.Options.FavoriteCompartments, want) {
+		t.Fatalf("favorites = %+v, want %+v", got.Options.FavoriteCompartments, want)
+	}
+}
+
+func TestMergeTakesOverlayDefaults(t *testing.T) {
+	base := Config{Options: Options{Defaults: &Context{Region: "us-ashburn-1", Profile: "DEFAULT"}}}
+	if got := Merge(base, Config{}); got.Options.Defaults == nil || got.Options.Defaults.Region != "us-ashburn-1" {
+		t.Fatalf("expected base defaults when the overlay sets none, got %+v", got.Options.Defaults)
+	}
+	overlay := Config{Options: Options{Defaults: &Context{Region: "eu-frankfurt-1"}}}
+	got := Merge(base, overlay)
+	if want := (&Context{Region: "eu-frankfurt-1"}); !reflect.DeepEqual(got.Options.Defaults, want) {
+		t.Fatalf("defaults = %+v, want %+v", got.Options.Defaults, want)
+	}
+	got.Options.Defaults.Region = "changed"
+	if overlay.Options.Defaults.Region != "eu-frankfurt-1" {
+		t.Fatalf("Merge result shares defaults with overlay")
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// marshalYAMLPreserving encodes cfg as YAML, carrying comments and mapping key
+// order over from the file currently at path. Nodes are matched by key (and
+// list entries by their name field), so comments stay attached to the entries
+// they describe even when values change or contexts move. A missing or
+// unparsable file yields plain yaml.Marshal output.
+func marshalYAMLPreserving(path string, cfg *Config) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(cfg); err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	if old, err := os.ReadFile(path); err == nil {
+		var prev yaml.Node
+		if yaml.Unmarshal(old, &prev) == nil && prev.Kind == yaml.DocumentNode {
+			mergeYAMLNode(&prev, doc)
+		}
+	}
+	return encodeYAMLNode(doc)
+}
+
+func encodeYAMLNode(n *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNode copies comments from prev onto cur and recurses into matching
+// children. Mapping keys that existed in prev keep prev's order; new keys
+// follow in encoding order.
+func mergeYAMLNode(prev, cur *yaml.Node) {
+	if prev == nil || cur == nil {
+		return
+	}
+	copyYAMLComments(prev, cur)
+	if prev.Kind != cur.Kind {
+		return
+	}
+	switch cur.Kind {
+	case yaml.DocumentNode:
+		if len(prev.Content) > 0 && len(cur.Content) > 0 {
+			mergeYAMLNode(prev.Content[0], cur.Content[0])
+		}
+	case yaml.MappingNode:
+		mergeYAMLMapping(prev, cur)
+	case yaml.SequenceNode:
+		for i, item := range cur.Content {
+			mergeYAMLNode(matchYAMLSequenceItem(prev, item, i), item)
+		}
+	}
+}
+
+func mergeYAMLMapping(prev, cur *yaml.Node) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make(map[string]pair, len(cur.Content)/2)
+	var order []string
+	for i := 0; i+1 < len(cur.Content); i += 2 {
+		k := cur.Content[i].Value
+		pairs[k] = pair{cur.Content[i], cur.Content[i+1]}
+		order = append(order, k)
+	}
+	content := make([]*yaml.Node, 0, len(cur.Content))
+	used := make(map[string]bool, len(pairs))
+	for i := 0; i+1 < len(prev.Content); i += 2 {
+		k := prev.Content[i].Value
+		p, ok := pairs[k]
+		if !ok || used[k] {
+			continue
+		}
+		used[k] = true
+		copyYAMLComments(prev.Content[i], p.key)
+		mergeYAMLNode(prev.Content[i+1], p.value)
+		content = append(content, p.key, p.value)
+	}
+	for _, k := range order {
+		if !used[k] {
+			content = append(content, pairs[k].key, pairs[k].value)
+		}
+	}
+	cur.Content = content
+}
+
+// matchYAMLSequenceItem finds the prev entry for item: by its name field when
+// both are mappings with one (contexts, token services), else by index.
+func matchYAMLSequenceItem(prev, item *yaml.Node, i int) *yaml.Node {
+	if name := yamlMappingValue(item, "name"); name != "" {
+		for _, p := range prev.Content {
+			if yamlMappingValue(p, "name") == name {
+				return p
+			}
+		}
+		return nil
+	}
+	if i < len(prev.Content) {
+		return prev.Content[i]
+	}
+	return nil
+}
+
+func yamlMappingValue(n *yaml.Node, key string) string {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+func copyYAMLComments(prev, cur *yaml.Node) {
+	cur.HeadComment = prev.HeadComment
+	cur.LineComment = prev.LineComment
+	cur.FootComment = prev.FootComment
+}
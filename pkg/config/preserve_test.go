@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSaveWithoutExistingFileMatchesYAMLMarshal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	cfg := testConfig()
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	cfg.SchemaVersion = CurrentSchemaVersion
+	want, err := yaml.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("output differs from yaml.Marshal\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestSavePreservesCommentsAndKeyOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	original := `# Team config, reviewed in PRs.
+schema_version: 1
+options:
+    oci_config_path: /tmp/oci # shared CLI config
+    socket_path: ""
+    default_profile: DEFAULT
+contexts:
+    # Staging tenancy; safe to break.
+    - region: us-phoenix-1 # moves next quarter
+      name: staging
+      profile: DEFAULT
+      tenancy_ocid: ocid1.tenancy.oc1..aaaa
+      compartment_ocid: ocid1.compartment.oc1..stg
+      user: ""
+      notes: ""
+      source: manual
+    # Production: ask before changing.
+    - name: prod
+      profile: PROD
+      tenancy_ocid: ocid1.tenancy.oc1..aaaa
+      compartment_ocid: ocid1.compartment.oc1..prd
+      region: us-ashburn-1
+      user: ""
+      notes: ""
+      source: manual
+current_context: staging
+`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cfg.Contexts[0].Region = "us-sanjose-1"
+	if err := cfg.MoveContext("prod", -1); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if err := cfg.UpsertContext(Context{Name: "dev", Profile: "DEFAULT", TenancyOCID: "ocid1.tenancy.oc1..aaaa", CompartmentOCID: "ocid1.compartment.oc1..dev", Region: "us-phoenix-1"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"# Team config, reviewed in PRs.\n",
+		"oci_config_path: /tmp/oci # shared CLI config\n",
+		"# Staging tenancy; safe to break.\n    - region: us-sanjose-1 # moves next quarter\n      name: staging\n",
+		"# Production: ask before changing.\n    - name: prod\n",
+		"- name: dev\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("saved config missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "name: prod") > strings.Index(out, "name: staging") {
+		t.Fatalf("expected prod moved above staging:\n%s", out)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v\n%s", err, out)
+	}
+	if len(reloaded.Contexts) != 3 || reloaded.Contexts[1].Region != "us-sanjose-1" {
+		t.Fatalf("unexpected reloaded contexts: %+v", reloaded.Contexts)
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+)
+
+// ContextFromProfile builds an api_key context named after an OCI CLI profile,
+// scoped to the tenancy root compartment. Notes and Source are left for the
+// caller to set.
+func ContextFromProfile(name string, p ocicfg.Profile) Context {
+	return Context{
+		Name:            name,
+		Profile:         name,
+		AuthMethod:      AuthMethodAPIKey,
+		TenancyOCID:     p.Tenancy,
+		CompartmentOCID: p.Tenancy, // default to root compartment
+		Region:          p.Region,
+		User:            p.User,
+	}
+}
+
+// ContextsFromProfiles applies ContextFromProfile to every profile, sorted by
+// profile name.
+func ContextsFromProfiles(profiles map[string]ocicfg.Profile) []Context {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]Context, 0, len(names))
+	for _, name := range names {
+		out = append(out, ContextFromProfile(name, profiles[name]))
+	}
+	return out
+}
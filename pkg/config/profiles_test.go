@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+)
+
+func TestContextsFromProfiles(t *testing.T) {
+	profiles := map[string]ocicfg.Profile{
+		"PROD":    {Tenancy: "ocid1.tenancy.oc1..prod", Region: "us-ashburn-1", User: "ocid1.user.oc1..p"},
+		"DEFAULT": {Tenancy: "ocid1.tenancy.oc1..dev", Region: "us-phoenix-1"},
+	}
+	got := ContextsFromProfiles(profiles)
+	if len(got) != 2 || got[0].Name != "DEFAULT" || got[1].Name != "PROD" {
+		t.Fatalf("expected contexts sorted by profile, got %+v", got)
+	}
+	prod := got[1]
+	want := Context{
+		Name:            "PROD",
+		Profile:         "PROD",
+		AuthMethod:      AuthMethodAPIKey,
+		TenancyOCID:     "ocid1.tenancy.oc1..prod",
+		CompartmentOCID: "ocid1.tenancy.oc1..prod",
+		Region:          "us-ashburn-1",
+		User:            "ocid1.user.oc1..p",
+	}
+	if !reflect.DeepEqual(prod, want) {
+		t.Fatalf("unexpected context: %+v", prod)
+	}
+	if err := prod.Validate(); err != nil {
+		t.Fatalf("profile context should validate: %v", err)
+	}
+}
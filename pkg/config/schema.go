@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+)
+
+// CurrentSchemaVersion is the config format version written by Save. Files
+// without schema_version are treated as version 0 and migrated on Load.
+const CurrentSchemaVersion = 1
+
+// migrations[i] upgrades a config from schema version i to i+1.
+var migrations = []func(*Config){
+	// v0 -> v1: record provenance for contexts written before Source existed.
+	(*Config).normalizeSources,
+}
+
+// migrate upgrades cfg in place to CurrentSchemaVersion.
+func (c *Config) migrate() error {
+	if c.SchemaVersion < 0 {
+		return fmt.Errorf("schema_version %d is invalid", c.SchemaVersion)
+	}
+	if c.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema_version %d is newer than supported version %d; upgrade oci-context", c.SchemaVersion, CurrentSchemaVersion)
+	}
+	for v := c.SchemaVersion; v < CurrentSchemaVersion; v++ {
+		migrations[v](c)
+	}
+	c.SchemaVersion = CurrentSchemaVersion
+	return nil
+}
+
+// Validate checks a loaded config for structural problems: unsupported schema
+// versions, invalid or duplicate contexts, and dangling current_context.
+func Validate(cfg Config) error {
+	if err := ValidateFile(cfg); err != nil {
+		return err
+	}
+	for i, ctx := range cfg.Contexts {
+		if err := ctx.Validate(); err != nil {
+			return fmt.Errorf("contexts[%d] (%s): %w", i, ctx.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateFile runs the checks of Validate that concern the file as a whole
+// and leaves each context's own fields unchecked, so a caller can report
+// field errors per context.
+func ValidateFile(cfg Config) error {
+	if cfg.SchemaVersion < 0 || cfg.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema_version %d is not supported (max %d)", cfg.SchemaVersion, CurrentSchemaVersion)
+	}
+	if _, err := cfg.Options.NetworkTimeoutOr(0); err != nil {
+		return fmt.Errorf("options.%w", err)
+	}
+	if n := cfg.Options.APIRetries; n != nil && *n < 0 {
+		return fmt.Errorf("options.api_retries %d must not be negative", *n)
+	}
+	if d := cfg.Options.Defaults; d != nil && d.Name != "" {
+		return fmt.Errorf("options.defaults: name %q is not allowed; defaults apply to every new context", d.Name)
+	}
+	if d := cfg.Options.Defaults; d != nil && d.CompartmentOCID != "" && d.TenancyOCID == "" {
+		return fmt.Errorf("options.defaults: compartment_ocid needs tenancy_ocid; a compartment only exists in its own tenancy")
+	}
+	seen := make(map[string]bool, len(cfg.Contexts))
+	for i, ctx := range cfg.Contexts {
+		if seen[ctx.Name] {
+			return fmt.Errorf("contexts[%d] (%s): %w", i, ctx.Name, ErrDuplicateName)
+		}
+		seen[ctx.Name] = true
+		switch ctx.Source {
+		case "", SourceManual, SourceImport, EnvContextSource:
+		default:
+			return fmt.Errorf("contexts[%d] (%s): source %q is invalid", i, ctx.Name, ctx.Source)
+		}
+	}
+	if cfg.CurrentContext != "" && !seen[cfg.CurrentContext] {
+		return fmt.Errorf("current_context %q: %w", cfg.CurrentContext, ErrContextNotFound)
+	}
+	return nil
+}
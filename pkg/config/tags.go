@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseTags parses repeated key=value flags. An empty value is kept so callers
+// can treat "key=" as a removal when merging.
+func ParseTags(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		k, v, ok := strings.Cut(spec, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" || strings.ContainsAny(k, " \t,") {
+			return nil, fmt.Errorf("invalid tag %q: expected key=value", spec)
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out, nil
+}
+
+// MergeTags applies updates to tags, deleting keys whose update value is empty.
+// It returns nil when no tags remain so empty maps are omitted on save.
+func MergeTags(tags, updates map[string]string) map[string]string {
+	out := make(map[string]string, len(tags)+len(updates))
+	for k, v := range tags {
+		out[k] = v
+	}
+	for k, v := range updates {
+		if v == "" {
+			delete(out, k)
+			continue
+		}
+		out[k] = v
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// FormatTags renders tags as sorted "k=v,k=v" for display.
+func FormatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+tags[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// HasTags reports whether every key=value in want is set on the context.
+func (ctx Context) HasTags(want map[string]string) bool {
+	for k, v := range want {
+		got, ok := ctx.Tags[k]
+		if !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	got, err := ParseTags([]string{"env=prod", "team = core ", "old="})
+	if err != nil {
+		t.Fatalf("ParseTags: %v", err)
+	}
+	want := map[string]string{"env": "prod", "team": "core", "old": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for _, bad := range []string{"env", "=prod", "a b=c"} {
+		if _, err := ParseTags([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestMergeTagsAndFormat(t *testing.T) {
+	tags := MergeTags(map[string]string{"env": "dev", "old": "x"}, map[string]string{"env": "prod", "old": "", "team": "core"})
+	if got := FormatTags(tags); got != "env=prod,team=core" {
+		t.Fatalf("unexpected tags: %q", got)
+	}
+	if MergeTags(map[string]string{"a": "1"}, map[string]string{"a": ""}) != nil {
+		t.Fatalf("expected nil when all tags removed")
+	}
+	ctx := Context{Tags: tags}
+	if !ctx.HasTags(map[string]string{"env": "prod"}) || ctx.HasTags(map[string]string{"env": "dev"}) {
+		t.Fatalf("HasTags mismatch for %v", tags)
+	}
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	cfg := Config{
+		Contexts: []Context{{
+			Name:            "dev",
+			Profile:         "DEFAULT",
+			TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+			CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+			Region:          "us-phoenix-1",
+			Tags:            map[string]string{"env": "dev", "team": "core"},
+		}},
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Contexts[0].Tags, cfg.Contexts[0].Tags) {
+		t.Fatalf("tags not preserved: %v", loaded.Contexts[0].Tags)
+	}
+}
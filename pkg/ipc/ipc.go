@@ -3,15 +3,47 @@ package ipc
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"time"
 )
 
+// ProtocolVersion is the IPC protocol version spoken by this package. Bump it
+// when a request or response shape changes incompatibly; adding a method
+// does not need a bump because capabilities lists methods by name.
+const ProtocolVersion = 1
+
+// ErrNotImplemented is returned for unknown methods.
+var ErrNotImplemented = errors.New("method not implemented")
+
+// tcpPrefix marks a socket_path that is a TCP address rather than a file.
+const tcpPrefix = "tcp://"
+
+// ParseAddress maps a socket_path to the network and address to listen on or
+// dial: "tcp://host:port" is a TCP address, "@name" is a Linux abstract Unix
+// socket, and anything else is a filesystem Unix socket.
+func ParseAddress(socketPath string) (network, address string) {
+	if rest, ok := strings.CutPrefix(socketPath, tcpPrefix); ok {
+		return "tcp", rest
+	}
+	return "unix", socketPath
+}
+
+// IsFilesystemSocket reports whether socketPath names a socket file on disk,
+// as opposed to a TCP address or an abstract socket.
+func IsFilesystemSocket(socketPath string) bool {
+	return !strings.HasPrefix(socketPath, tcpPrefix) && !strings.HasPrefix(socketPath, "@")
+}
+
 // Request represents an IPC request.
 type Request struct {
 	Method  string          `json:"method"`
 	Name    string          `json:"name,omitempty"`
 	Format  string          `json:"format,omitempty"`
+	Style   string          `json:"style,omitempty"`
+	Parent  string          `json:"parent,omitempty"`
 	Context json.RawMessage `json:"context,omitempty"`
 }
 
@@ -22,19 +54,57 @@ type Response struct {
 	Data  interface{} `json:"data,omitempty"`
 }
 
-// Conn wraps a Unix socket connection with framed JSON.
+// Capabilities is the capabilities response: what a daemon supports.
+type Capabilities struct {
+	Protocol int      `json:"protocol"`
+	Version  string   `json:"version"`
+	Methods  []string `json:"methods"`
+}
+
+// Supports reports whether the daemon implements method.
+func (c Capabilities) Supports(method string) bool {
+	for _, m := range c.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn wraps a daemon socket connection with framed JSON.
 type Conn struct {
 	conn net.Conn
 	rw   *bufio.ReadWriter
 }
 
-// Dial connects to a Unix socket.
+// Dial connects to the daemon at socketPath (see ParseAddress).
 func Dial(socketPath string) (*Conn, error) {
-	c, err := net.Dial("unix", socketPath)
+	c, err := net.Dial(ParseAddress(socketPath))
+	if err != nil {
+		return nil, err
+	}
+	return newConn(c), nil
+}
+
+// DialTimeout is Dial with a bound on connecting, so a tcp:// socket_path on
+// an unreachable host fails after timeout instead of the kernel's connect
+// timeout.
+func DialTimeout(socketPath string, timeout time.Duration) (*Conn, error) {
+	network, address := ParseAddress(socketPath)
+	c, err := net.DialTimeout(network, address, timeout)
 	if err != nil {
 		return nil, err
 	}
-	return &Conn{conn: c, rw: bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))}, nil
+	return newConn(c), nil
+}
+
+func newConn(c net.Conn) *Conn {
+	return &Conn{conn: c, rw: bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))}
+}
+
+// SetDeadline bounds the next request/response exchange.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
 }
 
 // Close closes the connection.
@@ -65,3 +135,27 @@ func (c *Conn) ReadResponse(resp interface{}) error {
 	}
 	return nil
 }
+
+// Negotiate asks the daemon for its capabilities. A daemon that predates the
+// capabilities method fails with ErrNotImplemented; callers can then fall back
+// to calling methods blindly.
+func (c *Conn) Negotiate() (Capabilities, error) {
+	if err := c.SendRequest(Request{Method: "capabilities"}); err != nil {
+		return Capabilities{}, err
+	}
+	var resp struct {
+		OK    bool         `json:"ok"`
+		Error string       `json:"error,omitempty"`
+		Data  Capabilities `json:"data,omitempty"`
+	}
+	if err := c.ReadResponse(&resp); err != nil {
+		return Capabilities{}, err
+	}
+	if !resp.OK {
+		if resp.Error == ErrNotImplemented.Error() {
+			return Capabilities{}, ErrNotImplemented
+		}
+		return Capabilities{}, errors.New(resp.Error)
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,185 @@
+// Package occontext is a small facade for embedding oci-context in other Go
+// programs. It wraps pkg/config and pkg/oci so callers can manage contexts
+// without shelling out to the CLI or importing internal packages.
+package occontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+)
+
+// ErrNoCurrentContext is returned when the config has no current context set.
+var ErrNoCurrentContext = errors.New("no current context set")
+
+// Status describes a context together with friendly OCI names when looked up.
+type Status struct {
+	Context         string `json:"context" yaml:"context"`
+	Profile         string `json:"profile" yaml:"profile"`
+	AuthMethod      string `json:"auth_method" yaml:"auth_method"`
+	Tenancy         string `json:"tenancy" yaml:"tenancy"`
+	TenancyOCID     string `json:"tenancy_id" yaml:"tenancy_id"`
+	Compartment     string `json:"compartment" yaml:"compartment"`
+	CompartmentOCID string `json:"compartment_id" yaml:"compartment_id"`
+	User            string `json:"user" yaml:"user"`
+	UserOCID        string `json:"user_id" yaml:"user_id"`
+	Region          string `json:"region" yaml:"region"`
+}
+
+// fetchIdentity is a seam for tests.
+var fetchIdentity = oci.FetchIdentityDetails
+
+// DefaultConfigPath returns the global config path (~/.oci-context/config.yml).
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".oci-context", "config.yml"), nil
+}
+
+// LoadConfig reads the config at path, or the global config when path is empty.
+func LoadConfig(path string) (config.Config, error) {
+	path, err := configPath(path)
+	if err != nil {
+		return config.Config{}, err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("load config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ListContexts returns all contexts in config order.
+func ListContexts(path string) ([]config.Context, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Contexts, nil
+}
+
+// CurrentContext returns the current context, or ErrNoCurrentContext.
+func CurrentContext(path string) (config.Context, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return config.Context{}, err
+	}
+	return currentContext(cfg)
+}
+
+// UseContext makes name the current context.
+func UseContext(path, name string) error {
+	path, err := configPath(path)
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.GetContext(name); err != nil {
+		return fmt.Errorf("context %q: %w", name, err)
+	}
+	cfg.SwitchContext(name)
+	return save(path, cfg)
+}
+
+// AddContext validates and appends a new context. Existing names are rejected
+// with config.ErrDuplicateName; the first context added becomes current.
+func AddContext(path string, ctx config.Context) error {
+	path, err := configPath(path)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Validate(); err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.GetContext(ctx.Name); err == nil {
+		return fmt.Errorf("context %q: %w", ctx.Name, config.ErrDuplicateName)
+	}
+	if ctx.Source == "" {
+		ctx.Source = config.SourceManual
+	}
+	if err := cfg.UpsertContext(ctx); err != nil {
+		return err
+	}
+	return save(path, cfg)
+}
+
+// ResolveStatus reports the current context. When lookup is true, tenancy,
+// compartment, and user names are fetched from OCI identity.
+func ResolveStatus(ctx context.Context, path string, lookup bool) (Status, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return Status{}, err
+	}
+	cur, err := currentContext(cfg)
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{
+		Context:         cur.Name,
+		Profile:         cur.Profile,
+		AuthMethod:      config.NormalizeAuthMethod(cur.AuthMethod),
+		TenancyOCID:     cur.TenancyOCID,
+		CompartmentOCID: cur.CompartmentOCID,
+		UserOCID:        cur.User,
+		Region:          cur.Region,
+	}
+	if !lookup {
+		return st, nil
+	}
+	ociCfgPath, err := ocicfg.ResolveConfigPath("", cfg.Options.OCIConfigPath)
+	if err != nil {
+		return Status{}, err
+	}
+	details, err := fetchIdentity(oci.WithRetryAttempts(ctx, cfg.Options.APIRetryAttempts()), ociCfgPath, cur.Profile, cur.Region, cur.TenancyOCID, cur.CompartmentOCID, cur.User)
+	if err != nil {
+		return Status{}, fmt.Errorf("identity lookup: %w", err)
+	}
+	st.Tenancy = details.TenancyName
+	st.TenancyOCID = details.TenancyOCID
+	st.Compartment = details.CompartmentName
+	st.CompartmentOCID = details.CompartmentOCID
+	st.User = details.UserName
+	st.UserOCID = details.UserOCID
+	st.Region = details.Region
+	return st, nil
+}
+
+func currentContext(cfg config.Config) (config.Context, error) {
+	if cfg.CurrentContext == "" {
+		return config.Context{}, ErrNoCurrentContext
+	}
+	cur, err := cfg.GetContext(cfg.CurrentContext)
+	if err != nil {
+		return config.Context{}, fmt.Errorf("current context %q: %w", cfg.CurrentContext, err)
+	}
+	return cur, nil
+}
+
+func configPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return DefaultConfigPath()
+}
+
+func save(path string, cfg config.Config) error {
+	if err := config.Save(path, cfg); err != nil {
+		return fmt.Errorf("save config %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package occontext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianmross/oci-context/pkg/config"
+	"github.com/adrianmross/oci-context/pkg/oci"
+)
+
+func writeEmptyConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := config.Save(path, config.Config{Options: config.Options{OCIConfigPath: "/tmp/oci"}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	return path
+}
+
+func TestContextCRUDAndCurrentFlow(t *testing.T) {
+	path := writeEmptyConfig(t)
+
+	if _, err := CurrentContext(path); !errors.Is(err, ErrNoCurrentContext) {
+		t.Fatalf("expected ErrNoCurrentContext, got %v", err)
+	}
+
+	dev := config.Context{
+		Name:            "dev",
+		Profile:         "DEFAULT",
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+	}
+	prod := dev
+	prod.Name = "prod"
+	prod.Region = "us-ashburn-1"
+
+	if err := AddContext(path, dev); err != nil {
+		t.Fatalf("add dev: %v", err)
+	}
+	if err := AddContext(path, prod); err != nil {
+		t.Fatalf("add prod: %v", err)
+	}
+	if err := AddContext(path, dev); !errors.Is(err, config.ErrDuplicateName) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	bad := dev
+	bad.Name = "bad"
+	bad.TenancyOCID = "not-an-ocid"
+	if err := AddContext(path, bad); err == nil {
+		t.Fatalf("expected invalid OCID to be rejected")
+	}
+
+	list, err := ListContexts(path)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "dev" || list[1].Name != "prod" {
+		t.Fatalf("unexpected contexts: %+v", list)
+	}
+	if list[0].Source != config.SourceManual {
+		t.Fatalf("expected manual source, got %q", list[0].Source)
+	}
+
+	cur, err := CurrentContext(path)
+	if err != nil || cur.Name != "dev" {
+		t.Fatalf("expected first added context to be current, got %+v (%v)", cur, err)
+	}
+
+	if err := UseContext(path, "prod"); err != nil {
+		t.Fatalf("use prod: %v", err)
+	}
+	cur, err = CurrentContext(path)
+	if err != nil || cur.Name != "prod" {
+		t.Fatalf("expected prod current, got %+v (%v)", cur, err)
+	}
+	if err := UseContext(path, "missing"); !errors.Is(err, config.ErrContextNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "absent.yml")); err == nil {
+		t.Fatalf("expected error for missing config")
+	}
+}
+
+func TestResolveStatus(t *testing.T) {
+	path := writeEmptyConfig(t)
+	if err := AddContext(path, config.Context{
+		Name:            "dev",
+		Profile:         "DEV",
+		AuthMethod:      config.AuthMethodSecurityToken,
+		TenancyOCID:     "ocid1.tenancy.oc1..aaaa",
+		CompartmentOCID: "ocid1.compartment.oc1..bbbb",
+		Region:          "us-phoenix-1",
+	}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	orig := fetchIdentity
+	t.Cleanup(func() { fetchIdentity = orig })
+	calls := 0
+	fetchIdentity = func(ctx context.Context, profileConfigPath, profile, region, tenancyOCID, compartmentOCID, userOCID string) (oci.IdentityDetails, error) {
+		calls++
+		if profileConfigPath != "/tmp/oci" || profile != "DEV" {
+			t.Fatalf("unexpected lookup args: %s %s", profileConfigPath, profile)
+		}
+		return oci.IdentityDetails{
+			TenancyName:     "acme",
+			TenancyOCID:     tenancyOCID,
+			CompartmentName: "dev-comp",
+			CompartmentOCID: compartmentOCID,
+			UserName:        "alice",
+			UserOCID:        "ocid1.user.oc1..cccc",
+			Region:          region,
+		}, nil
+	}
+
+	st, err := ResolveStatus(context.Background(), path, false)
+	if err != nil {
+		t.Fatalf("resolve without lookup: %v", err)
+	}
+	if calls != 0 || st.Tenancy != "" || st.AuthMethod != config.AuthMethodSecurityToken {
+		t.Fatalf("unexpected offline status: %+v (calls=%d)", st, calls)
+	}
+
+	st, err = ResolveStatus(context.Background(), path, true)
+	if err != nil {
+		t.Fatalf("resolve with lookup: %v", err)
+	}
+	if calls != 1 || st.Tenancy != "acme" || st.Compartment != "dev-comp" || st.User != "alice" || st.UserOCID != "ocid1.user.oc1..cccc" {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	fetchIdentity = func(context.Context, string, string, string, string, string, string) (oci.IdentityDetails, error) {
+		return oci.IdentityDetails{}, errors.New("boom")
+	}
+	if _, err := ResolveStatus(context.Background(), path, true); err == nil || err.Error() != "identity lookup: boom" {
+		t.Fatalf("expected wrapped lookup error, got %v", err)
+	}
+}
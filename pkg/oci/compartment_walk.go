@@ -0,0 +1,82 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultWalkDepth bounds a WalkCompartments call that sets no MaxDepth. OCI
+// nests compartments at most six levels below the root, so this only stops
+// a walk over data that is already wrong.
+const DefaultWalkDepth = 16
+
+// ErrCompartmentCycle is returned when a walk reaches a compartment it has
+// already visited, such as a child whose listing points back up the hierarchy.
+var ErrCompartmentCycle = errors.New("compartment hierarchy has a cycle")
+
+// ChildLister lists the direct children of parentID; FetchCompartments bound
+// to a profile and region is one.
+type ChildLister func(ctx context.Context, parentID string) ([]Compartment, error)
+
+// WalkOptions bounds a WalkCompartments call.
+type WalkOptions struct {
+	// MaxDepth is how many levels below the root are listed; 0 means DefaultWalkDepth.
+	MaxDepth int
+	// Workers caps list calls in flight; less than 1 means 1.
+	Workers int
+	// ActiveOnly skips compartments that are not ACTIVE, and their subtrees.
+	ActiveOnly bool
+}
+
+// WalkCompartments lists rootID's descendants level by level and returns each
+// listed compartment's children keyed by parent OCID, in listing order. The
+// first list error is returned, as is ErrCompartmentCycle when a compartment
+// turns up a second time.
+func WalkCompartments(ctx context.Context, rootID string, list ChildLister, opts WalkOptions) (map[string][]Compartment, error) {
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultWalkDepth
+	}
+	sem := make(chan struct{}, max(opts.Workers, 1))
+	out := make(map[string][]Compartment)
+	visited := map[string]bool{rootID: true}
+	level := []string{rootID}
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		children := make([][]Compartment, len(level))
+		errs := make([]error, len(level))
+		var wg sync.WaitGroup
+		for i, parent := range level {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				children[i], errs[i] = list(ctx, parent)
+				<-sem
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		var next []string
+		for i, parent := range level {
+			for _, child := range children[i] {
+				if opts.ActiveOnly && child.Status != "" && child.Status != "ACTIVE" {
+					continue
+				}
+				if visited[child.ID] {
+					return nil, fmt.Errorf("%w: %s is listed under %s but was already visited", ErrCompartmentCycle, child.ID, parent)
+				}
+				visited[child.ID] = true
+				out[parent] = append(out[parent], child)
+				next = append(next, child.ID)
+			}
+		}
+		level = next
+	}
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWalkCompartmentsDetectsCyclesAndBoundsDepth(t *testing.T) {
+	stub := func(tree map[string][]Compartment) (ChildLister, *int) {
+		calls := 0
+		return func(_ context.Context, parentID string) ([]Compartment, error) {
+			calls++
+			return tree[parentID], nil
+		}, &calls
+	}
+
+	// A compartment that lists itself as its own child.
+	self, _ := stub(map[string][]Compartment{
+		"root": {{ID: "a", Name: "a"}},
+		"a":    {{ID: "a", Name: "a"}},
+	})
+	if _, err := WalkCompartments(context.Background(), "root", self, WalkOptions{}); !errors.Is(err, ErrCompartmentCycle) {
+		t.Fatalf("expected ErrCompartmentCycle for a self-referential compartment, got %v", err)
+	}
+	// A grandchild pointing back at the root.
+	back, _ := stub(map[string][]Compartment{
+		"root": {{ID: "a"}},
+		"a":    {{ID: "root"}},
+	})
+	if _, err := WalkCompartments(context.Background(), "root", back, WalkOptions{}); !errors.Is(err, ErrCompartmentCycle) {
+		t.Fatalf("expected ErrCompartmentCycle for a child pointing at the root, got %v", err)
+	}
+
+	// An endless chain of fresh OCIDs stops at the depth bound.
+	chain := func(_ context.Context, parentID string) ([]Compartment, error) {
+		return []Compartment{{ID: parentID + "/x"}}, nil
+	}
+	got, err := WalkCompartments(context.Background(), "root", chain, WalkOptions{MaxDepth: 3})
+	if err != nil || len(got) != 3 {
+		t.Fatalf("expected 3 listed levels, got %d (%v)", len(got), err)
+	}
+	if got, err = WalkCompartments(context.Background(), "root", chain, WalkOptions{}); err != nil || len(got) != DefaultWalkDepth {
+		t.Fatalf("expected the default bound of %d levels, got %d (%v)", DefaultWalkDepth, len(got), err)
+	}
+
+	tree, calls := stub(map[string][]Compartment{
+		"root": {{ID: "a", Status: "ACTIVE"}, {ID: "old", Status: "DELETED"}},
+		"a":    {{ID: "b", Status: "ACTIVE"}},
+		"old":  {{ID: "c", Status: "ACTIVE"}},
+	})
+	got, err = WalkCompartments(context.Background(), "root", tree, WalkOptions{ActiveOnly: true, Workers: 4})
+	want := map[string][]Compartment{
+		"root": {{ID: "a", Status: "ACTIVE"}},
+		"a":    {{ID: "b", Status: "ACTIVE"}},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) || *calls != 3 {
+		t.Fatalf("unexpected active walk %+v after %d calls (%v)", got, *calls, err)
+	}
+
+	failing := func(_ context.Context, parentID string) ([]Compartment, error) {
+		if parentID == "a" {
+			return nil, fmt.Errorf("boom")
+		}
+		return []Compartment{{ID: "a"}}, nil
+	}
+	if _, err := WalkCompartments(context.Background(), "root", failing, WalkOptions{}); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected the list error, got %v", err)
+	}
+}
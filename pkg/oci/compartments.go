@@ -22,32 +22,43 @@ type Compartment struct {
 // region: region to target
 // parentID: compartment or tenancy OCID
 func FetchCompartments(ctx context.Context, profileConfigPath, profile, region, parentID string) ([]Compartment, error) {
+	return listCompartments(ctx, profileConfigPath, profile, region, parentID, false)
+}
+
+// FetchCompartmentSubtree fetches every accessible compartment below tenancyID in
+// a single paginated scan (CompartmentIdInSubtree). Parent is set on each record so
+// callers can rebuild the hierarchy.
+func FetchCompartmentSubtree(ctx context.Context, profileConfigPath, profile, region, tenancyID string) ([]Compartment, error) {
+	return listCompartments(ctx, profileConfigPath, profile, region, tenancyID, true)
+}
+
+func listCompartments(ctx context.Context, profileConfigPath, profile, region, parentID string, subtree bool) ([]Compartment, error) {
 	if profileConfigPath == "" {
 		return nil, fmt.Errorf("oci config path required")
 	}
-	provider, err := common.ConfigurationProviderFromFileWithProfile(profileConfigPath, profile, "")
+	provider, err := newConfigProvider(profileConfigPath, profile)
 	if err != nil {
 		return nil, fmt.Errorf("config provider: %w", err)
 	}
-	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+	client, err := newIdentityClient(ctx, provider, region)
 	if err != nil {
 		return nil, fmt.Errorf("identity client: %w", err)
 	}
-	if region != "" {
-		client.SetRegion(region)
-	}
 
 	req := identity.ListCompartmentsRequest{
 		CompartmentId:          common.String(parentID),
-		CompartmentIdInSubtree: common.Bool(false),
+		CompartmentIdInSubtree: common.Bool(subtree),
 		Limit:                  common.Int(1000),
 	}
+	if subtree {
+		req.AccessLevel = identity.ListCompartmentsAccessLevelAccessible
+	}
 
 	var out []Compartment
 	for {
 		resp, err := client.ListCompartments(ctx, req)
 		if err != nil {
-			return nil, fmt.Errorf("list compartments: %w", err)
+			return nil, wrapAPIError("list compartments", err)
 		}
 		for _, c := range resp.Items {
 			out = append(out, Compartment{
@@ -65,9 +76,64 @@ func FetchCompartments(ctx context.Context, profileConfigPath, profile, region,
 	return out, nil
 }
 
+// CountChildCompartments returns how many direct children parentID has, from a
+// single ListCompartments page of at most limit items. A result equal to limit
+// means "limit or more". With activeOnly, only ACTIVE children are counted.
+func CountChildCompartments(ctx context.Context, profileConfigPath, profile, region, parentID string, activeOnly bool, limit int) (int, error) {
+	if profileConfigPath == "" {
+		return 0, fmt.Errorf("oci config path required")
+	}
+	provider, err := newConfigProvider(profileConfigPath, profile)
+	if err != nil {
+		return 0, fmt.Errorf("config provider: %w", err)
+	}
+	client, err := newIdentityClient(ctx, provider, region)
+	if err != nil {
+		return 0, fmt.Errorf("identity client: %w", err)
+	}
+	req := identity.ListCompartmentsRequest{
+		CompartmentId: common.String(parentID),
+		Limit:         common.Int(limit),
+	}
+	if activeOnly {
+		req.LifecycleState = identity.CompartmentLifecycleStateActive
+	}
+	resp, err := client.ListCompartments(ctx, req)
+	if err != nil {
+		return 0, wrapAPIError("list compartments", err)
+	}
+	return min(len(resp.Items), limit), nil
+}
+
 func deref(ptr *string) string {
 	if ptr == nil {
 		return ""
 	}
 	return *ptr
 }
+
+// GetCompartment fetches a single compartment by OCID, returning an error when it
+// no longer exists or is not visible to the profile.
+func GetCompartment(ctx context.Context, profileConfigPath, profile, region, compartmentID string) (Compartment, error) {
+	if profileConfigPath == "" {
+		return Compartment{}, fmt.Errorf("oci config path required")
+	}
+	provider, err := newConfigProvider(profileConfigPath, profile)
+	if err != nil {
+		return Compartment{}, fmt.Errorf("config provider: %w", err)
+	}
+	client, err := newIdentityClient(ctx, provider, region)
+	if err != nil {
+		return Compartment{}, fmt.Errorf("identity client: %w", err)
+	}
+	resp, err := client.GetCompartment(ctx, identity.GetCompartmentRequest{CompartmentId: common.String(compartmentID)})
+	if err != nil {
+		return Compartment{}, wrapAPIError("get compartment", err)
+	}
+	return Compartment{
+		ID:     deref(resp.Id),
+		Name:   deref(resp.Name),
+		Status: string(resp.LifecycleState),
+		Parent: deref(resp.CompartmentId),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// Sentinel error kinds returned (wrapped) by the OCI calls in this package.
+// Match them with errors.Is; the original SDK error stays in the chain.
+var (
+	// ErrAuth means OCI rejected the credentials (HTTP 401).
+	ErrAuth = errors.New("oci authentication failed")
+	// ErrNotFound means the resource does not exist or the principal cannot
+	// see it; OCI reports both as 404 NotAuthorizedOrNotFound.
+	ErrNotFound = errors.New("oci resource not found")
+	// ErrTimeout means the context deadline passed or the network timed out.
+	ErrTimeout = errors.New("oci request timed out")
+)
+
+// APIError is an OCI call failure classified into one of the sentinel kinds.
+type APIError struct {
+	Op   string
+	Kind error
+	Err  error
+}
+
+func (e *APIError) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap exposes both the kind and the underlying error to errors.Is/As.
+func (e *APIError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// wrapAPIError prefixes err with op and, when it can be classified, attaches
+// the matching sentinel kind.
+func wrapAPIError(op string, err error) error {
+	slog.Debug("oci call failed", "op", op, "err", err)
+	kind := classifyError(err)
+	if kind == nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return &APIError{Op: op, Kind: kind, Err: err}
+}
+
+func classifyError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, common.DeadlineExceededByBackoff) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+	var svcErr common.ServiceError
+	if errors.As(err, &svcErr) {
+		switch svcErr.GetHTTPStatusCode() {
+		case http.StatusUnauthorized:
+			return ErrAuth
+		case http.StatusNotFound:
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// ErrorHint returns a short remedy for a classified error, or "" when err is
+// not one of the sentinel kinds.
+func ErrorHint(err error) string {
+	switch {
+	case errors.Is(err, ErrAuth):
+		return "check your credentials: API key, session token, or instance principal"
+	case errors.Is(err, ErrNotFound):
+		return "the resource was deleted or your profile cannot access it"
+	case errors.Is(err, ErrTimeout):
+		return "OCI did not respond in time; check your network or region"
+	default:
+		return ""
+	}
+}
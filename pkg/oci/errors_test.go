@@ -0,0 +1,55 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeServiceError struct {
+	status int
+	code   string
+}
+
+func (e fakeServiceError) Error() string {
+	return fmt.Sprintf("Error returned by service: %d %s", e.status, e.code)
+}
+func (e fakeServiceError) GetHTTPStatusCode() int  { return e.status }
+func (e fakeServiceError) GetMessage() string      { return e.code }
+func (e fakeServiceError) GetCode() string         { return e.code }
+func (e fakeServiceError) GetOpcRequestID() string { return "req" }
+
+func TestWrapAPIErrorClassifies(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"auth", fakeServiceError{401, "NotAuthenticated"}, ErrAuth},
+		{"not found", fakeServiceError{404, "NotAuthorizedOrNotFound"}, ErrNotFound},
+		{"deadline", fmt.Errorf("do request: %w", context.DeadlineExceeded), ErrTimeout},
+		{"other service error", fakeServiceError{500, "InternalServerError"}, nil},
+		{"plain", errors.New("boom"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapAPIError("get tenancy", tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Fatalf("expected original error in chain: %v", got)
+			}
+			if !strings.HasPrefix(got.Error(), "get tenancy: ") {
+				t.Fatalf("expected op prefix, got %q", got.Error())
+			}
+			for _, kind := range []error{ErrAuth, ErrNotFound, ErrTimeout} {
+				if errors.Is(got, kind) != (kind == tt.kind) {
+					t.Fatalf("errors.Is(%v, %v) = %v", got, kind, !(kind == tt.kind))
+				}
+			}
+			if (ErrorHint(got) != "") != (tt.kind != nil) {
+				t.Fatalf("unexpected hint %q", ErrorHint(got))
+			}
+		})
+	}
+}
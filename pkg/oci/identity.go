@@ -26,7 +26,7 @@ func FetchIdentityDetails(ctx context.Context, profileConfigPath, profile, regio
 	if profileConfigPath == "" {
 		return IdentityDetails{}, fmt.Errorf("oci config path required")
 	}
-	provider, err := common.ConfigurationProviderFromFileWithProfile(profileConfigPath, profile, "")
+	provider, err := newConfigProvider(profileConfigPath, profile)
 	if err != nil {
 		return IdentityDetails{}, fmt.Errorf("config provider: %w", err)
 	}
@@ -44,18 +44,15 @@ func FetchIdentityDetails(ctx context.Context, profileConfigPath, profile, regio
 		}
 	}
 
-	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+	client, err := newIdentityClient(ctx, provider, region)
 	if err != nil {
 		return IdentityDetails{}, fmt.Errorf("identity client: %w", err)
 	}
-	if region != "" {
-		client.SetRegion(region)
-	}
 
 	// tenancy name
 	tenResp, err := client.GetTenancy(ctx, identity.GetTenancyRequest{TenancyId: common.String(tenancyOCID)})
 	if err != nil {
-		return IdentityDetails{}, fmt.Errorf("get tenancy: %w", err)
+		return IdentityDetails{}, wrapAPIError("get tenancy", err)
 	}
 
 	compName := ""
@@ -68,7 +65,7 @@ func FetchIdentityDetails(ctx context.Context, profileConfigPath, profile, regio
 
 	usrResp, err := client.GetUser(ctx, identity.GetUserRequest{UserId: common.String(userOCID)})
 	if err != nil {
-		return IdentityDetails{}, fmt.Errorf("get user: %w", err)
+		return IdentityDetails{}, wrapAPIError("get user", err)
 	}
 
 	return IdentityDetails{
@@ -88,11 +85,11 @@ func ListRegionSubscriptions(ctx context.Context, profileConfigPath, profile str
 	if profileConfigPath == "" {
 		return nil, fmt.Errorf("oci config path required")
 	}
-	provider, err := common.ConfigurationProviderFromFileWithProfile(profileConfigPath, profile, "")
+	provider, err := newConfigProvider(profileConfigPath, profile)
 	if err != nil {
 		return nil, fmt.Errorf("config provider: %w", err)
 	}
-	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+	client, err := newIdentityClient(ctx, provider, "")
 	if err != nil {
 		return nil, fmt.Errorf("identity client: %w", err)
 	}
@@ -106,7 +103,7 @@ func ListRegionSubscriptions(ctx context.Context, profileConfigPath, profile str
 		TenancyId: common.String(tid),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list region subscriptions: %w", err)
+		return nil, wrapAPIError("list region subscriptions", err)
 	}
 
 	regions := make([]string, 0, len(resp.Items))
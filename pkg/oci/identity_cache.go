@@ -0,0 +1,256 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// EnvIdentityCacheTTL overrides how long cached identity lookups stay fresh.
+// It accepts a Go duration ("30m"); "0" disables the cache.
+const EnvIdentityCacheTTL = "OCI_CONTEXT_IDENTITY_CACHE_TTL"
+
+// DefaultIdentityCacheTTL is used when EnvIdentityCacheTTL is unset or invalid.
+const DefaultIdentityCacheTTL = 10 * time.Minute
+
+// DefaultIdentityCacheEntries bounds the cache file; the oldest entries are
+// evicted first.
+const DefaultIdentityCacheEntries = 128
+
+// IdentityFetcher has the signature of FetchIdentityDetails.
+type IdentityFetcher func(ctx context.Context, profileConfigPath, profile, region, tenancyOCID, compartmentOCID, userOCID string) (IdentityDetails, error)
+
+// IdentityCache stores FetchIdentityDetails results in a JSON file keyed by
+// OCI config path, profile, region, tenancy, compartment, and user. The same
+// profile name in two OCI config files names different credentials.
+type IdentityCache struct {
+	Path       string
+	TTL        time.Duration
+	MaxEntries int
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+type identityCacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Details   IdentityDetails `json:"details"`
+	// ConfigPath, Profile, and Tenancy let Purge find a context's entries
+	// behind the hashed key.
+	ConfigPath string `json:"config_path,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+	Tenancy    string `json:"tenancy,omitempty"`
+}
+
+type identityCacheFile struct {
+	Entries map[string]identityCacheEntry `json:"entries"`
+}
+
+// IdentityCacheTTL returns the configured TTL; zero means caching is disabled.
+func IdentityCacheTTL() time.Duration {
+	v := strings.TrimSpace(os.Getenv(EnvIdentityCacheTTL))
+	if v == "" {
+		return DefaultIdentityCacheTTL
+	}
+	if v == "0" {
+		return 0
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultIdentityCacheTTL
+}
+
+// NewIdentityCache returns a cache at path using IdentityCacheTTL.
+func NewIdentityCache(path string) *IdentityCache {
+	return &IdentityCache{Path: path, TTL: IdentityCacheTTL(), MaxEntries: DefaultIdentityCacheEntries}
+}
+
+// Wrap returns a fetcher that serves fresh cache hits and stores successful
+// results of fetch. Errors are never cached, nor are results missing the
+// compartment name that was asked for (FetchIdentityDetails reports a failed
+// compartment lookup that way). A nil cache or zero TTL returns fetch unchanged.
+func (c *IdentityCache) Wrap(fetch IdentityFetcher) IdentityFetcher {
+	if c == nil || c.TTL <= 0 {
+		return fetch
+	}
+	return func(ctx context.Context, profileConfigPath, profile, region, tenancyOCID, compartmentOCID, userOCID string) (IdentityDetails, error) {
+		key := identityCacheKey(profileConfigPath, profile, region, tenancyOCID, compartmentOCID, userOCID)
+		if d, ok := c.get(key); ok {
+			slog.Debug("identity cache hit", "profile", profile, "region", region)
+			return d, nil
+		}
+		slog.Debug("identity cache miss", "profile", profile, "region", region)
+		d, err := fetch(ctx, profileConfigPath, profile, region, tenancyOCID, compartmentOCID, userOCID)
+		if err != nil {
+			return d, err
+		}
+		if compartmentOCID != "" && d.CompartmentName == "" {
+			return d, nil
+		}
+		// A cache that cannot be written only costs a future lookup.
+		_ = c.put(key, identityCacheEntry{Details: d, ConfigPath: profileConfigPath, Profile: profile, Tenancy: tenancyOCID})
+		return d, nil
+	}
+}
+
+func identityCacheKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *IdentityCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *IdentityCache) get(key string) (IdentityDetails, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := c.read()
+	e, ok := f.Entries[key]
+	if !ok || c.clock().Sub(e.FetchedAt) >= c.TTL {
+		return IdentityDetails{}, false
+	}
+	return e.Details, true
+}
+
+func (c *IdentityCache) put(key string, entry identityCacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lock, err := lockCacheFile(c.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	f := c.read()
+	now := c.clock()
+	for k, e := range f.Entries {
+		if now.Sub(e.FetchedAt) >= c.TTL {
+			delete(f.Entries, k)
+		}
+	}
+	entry.FetchedAt = now
+	f.Entries[key] = entry
+	if max := c.MaxEntries; max > 0 && len(f.Entries) > max {
+		keys := make([]string, 0, len(f.Entries))
+		for k := range f.Entries {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return f.Entries[keys[i]].FetchedAt.Before(f.Entries[keys[j]].FetchedAt)
+		})
+		for _, k := range keys[:len(keys)-max] {
+			delete(f.Entries, k)
+		}
+	}
+	return c.write(f)
+}
+
+// Purge removes the entries cached for profile and tenancy read from the OCI
+// config at configPath ("" for any file) and reports how many were removed.
+// Empty profile and tenancy remove every entry. Entries written before the
+// profile and tenancy were recorded only go with a full purge.
+func (c *IdentityCache) Purge(configPath, profile, tenancy string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	lock, err := lockCacheFile(c.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Unlock()
+	f := c.read()
+	removed := 0
+	for k, e := range f.Entries {
+		all := profile == "" && tenancy == ""
+		if all || (e.Profile == profile && e.Tenancy == tenancy && (configPath == "" || e.ConfigPath == configPath)) {
+			delete(f.Entries, k)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, c.write(f)
+}
+
+// read returns the cache contents; a missing or corrupt file reads as empty.
+func (c *IdentityCache) read() identityCacheFile {
+	f := identityCacheFile{}
+	if data, err := os.ReadFile(c.Path); err == nil {
+		_ = json.Unmarshal(data, &f)
+	}
+	if f.Entries == nil {
+		f.Entries = map[string]identityCacheEntry{}
+	}
+	return f
+}
+
+// write replaces the file via rename so concurrent readers in other processes
+// never see a partial file.
+func (c *IdentityCache) write(f identityCacheFile) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(c.Path, data)
+}
+
+// cacheLockTimeout bounds the wait for another writer's cache lock.
+const cacheLockTimeout = 2 * time.Second
+
+// lockCacheFile takes the lock file next to path for a read-modify-write of
+// the cache. It serializes writers in other processes and every cache value
+// on the same path in this one, which the per-value mutex cannot.
+func lockCacheFile(path string) (*flock.Flock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cacheLockTimeout)
+	defer cancel()
+	lock := flock.New(path + ".lock")
+	ok, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if !ok {
+		if err == nil {
+			err = context.DeadlineExceeded
+		}
+		return nil, fmt.Errorf("lock %s: %w", lock.Path(), err)
+	}
+	return lock, nil
+}
+
+// writeCacheFile writes data to path through a temp file and rename.
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,184 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIdentityCacheWrap(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &IdentityCache{Path: filepath.Join(t.TempDir(), "cache", "identity.json"), TTL: 10 * time.Minute, MaxEntries: 2}
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	fail := false
+	fetch := cache.Wrap(func(_ context.Context, _, profile, region, tenancy, compartment, user string) (IdentityDetails, error) {
+		calls++
+		if fail {
+			return IdentityDetails{}, errors.New("boom")
+		}
+		return IdentityDetails{TenancyName: "T", CompartmentName: compartment, Region: region}, nil
+	})
+	get := func(compartment string) (IdentityDetails, error) {
+		return fetch(context.Background(), "/tmp/oci", "DEFAULT", "us-phoenix-1", "ocid1.tenancy.oc1..t", compartment, "ocid1.user.oc1..u")
+	}
+
+	if d, err := get("a"); err != nil || d.CompartmentName != "a" || calls != 1 {
+		t.Fatalf("first fetch: %+v %v calls=%d", d, err, calls)
+	}
+	if d, err := get("a"); err != nil || d.CompartmentName != "a" || calls != 1 {
+		t.Fatalf("expected cache hit: %+v %v calls=%d", d, err, calls)
+	}
+	// A fresh cache instance reads the same file.
+	other := &IdentityCache{Path: cache.Path, TTL: cache.TTL, now: cache.now}
+	if _, ok := other.get(identityCacheKey("/tmp/oci", "DEFAULT", "us-phoenix-1", "ocid1.tenancy.oc1..t", "a", "ocid1.user.oc1..u")); !ok {
+		t.Fatalf("expected entry persisted to %s", cache.Path)
+	}
+
+	now = now.Add(11 * time.Minute)
+	fail = true
+	if _, err := get("a"); err == nil || calls != 2 {
+		t.Fatalf("expected expired entry to refetch and surface error, calls=%d err=%v", calls, err)
+	}
+	fail = false
+
+	// A compartment lookup that failed inside the fetch leaves the name empty
+	// and must not be cached.
+	partial := cache.Wrap(func(context.Context, string, string, string, string, string, string) (IdentityDetails, error) {
+		calls++
+		return IdentityDetails{TenancyName: "T"}, nil
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := partial(context.Background(), "/tmp/oci", "DEFAULT", "us-phoenix-1", "ocid1.tenancy.oc1..t", "z", "ocid1.user.oc1..u"); err != nil {
+			t.Fatalf("partial fetch: %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Fatalf("expected partial results to bypass the cache, calls=%d", calls)
+	}
+
+	// MaxEntries evicts the oldest entry.
+	for i, comp := range []string{"b", "c", "d"} {
+		now = now.Add(time.Duration(i+1) * time.Second)
+		if _, err := get(comp); err != nil {
+			t.Fatalf("fetch %s: %v", comp, err)
+		}
+	}
+	if n := len(cache.read().Entries); n != 2 {
+		t.Fatalf("expected 2 entries after eviction, got %d", n)
+	}
+	before := calls
+	if _, err := get("b"); err != nil || calls != before+1 {
+		t.Fatalf("expected oldest entry evicted, calls=%d", calls)
+	}
+}
+
+func TestIdentityCacheConcurrentInstancesKeepAllWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "identity.json")
+	fetch := func(_ context.Context, _, _, _, _, compartment, _ string) (IdentityDetails, error) {
+		return IdentityDetails{CompartmentName: compartment}, nil
+	}
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each caller builds its own cache on the shared file, as the CLI does.
+			cache := &IdentityCache{Path: path, TTL: 10 * time.Minute, MaxEntries: DefaultIdentityCacheEntries}
+			comp := fmt.Sprintf("c%d", i)
+			if _, err := cache.Wrap(fetch)(context.Background(), "/tmp/oci", "DEFAULT", "us-phoenix-1", "ocid1.tenancy.oc1..t", comp, ""); err != nil {
+				t.Errorf("fetch %s: %v", comp, err)
+			}
+		}()
+	}
+	wg.Wait()
+	cache := &IdentityCache{Path: path, TTL: 10 * time.Minute}
+	if got := len(cache.read().Entries); got != n {
+		t.Fatalf("expected %d entries, got %d", n, got)
+	}
+}
+
+func TestIdentityCachePurgeByProfileAndTenancy(t *testing.T) {
+	cache := &IdentityCache{Path: filepath.Join(t.TempDir(), "cache", "identity.json"), TTL: 10 * time.Minute}
+	if n, err := cache.Purge("/tmp/oci", "DEV", "ocid1.tenancy.oc1..dev"); err != nil || n != 0 {
+		t.Fatalf("purge of a missing file: %d %v", n, err)
+	}
+	fetch := cache.Wrap(func(_ context.Context, _, profile, region, tenancy, compartment, user string) (IdentityDetails, error) {
+		return IdentityDetails{TenancyName: profile, CompartmentName: compartment}, nil
+	})
+	for _, p := range []struct{ profile, tenancy, compartment string }{
+		{"DEV", "ocid1.tenancy.oc1..dev", "a"},
+		{"DEV", "ocid1.tenancy.oc1..dev", "b"},
+		{"PROD", "ocid1.tenancy.oc1..prod", "a"},
+	} {
+		if _, err := fetch(context.Background(), "/tmp/oci", p.profile, "us-phoenix-1", p.tenancy, p.compartment, ""); err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+	}
+
+	if n, err := cache.Purge("/tmp/oci", "DEV", "ocid1.tenancy.oc1..dev"); err != nil || n != 2 {
+		t.Fatalf("expected 2 DEV entries purged, got %d %v", n, err)
+	}
+	if _, ok := cache.get(identityCacheKey("/tmp/oci", "PROD", "us-phoenix-1", "ocid1.tenancy.oc1..prod", "a", "")); !ok {
+		t.Fatalf("expected PROD entry to survive")
+	}
+	if n, err := cache.Purge("", "", ""); err != nil || n != 1 {
+		t.Fatalf("expected full purge of 1 entry, got %d %v", n, err)
+	}
+}
+
+func TestIdentityCacheKeysByConfigPath(t *testing.T) {
+	cache := &IdentityCache{Path: filepath.Join(t.TempDir(), "cache", "identity.json"), TTL: 10 * time.Minute}
+	calls := map[string]int{}
+	fetch := cache.Wrap(func(_ context.Context, configPath, _, _, _, compartment, _ string) (IdentityDetails, error) {
+		calls[configPath]++
+		if configPath == "/tmp/ci-config" {
+			return IdentityDetails{}, errors.New("get tenancy: 401 NotAuthenticated")
+		}
+		return IdentityDetails{TenancyName: "T", CompartmentName: compartment}, nil
+	})
+	get := func(configPath string) error {
+		_, err := fetch(context.Background(), configPath, "DEFAULT", "us-phoenix-1", "ocid1.tenancy.oc1..t", "a", "")
+		return err
+	}
+
+	if err := get("/home/u/.oci/config"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	// The same profile in another OCI config must not be served from the
+	// entry cached for the first file.
+	if err := get("/tmp/ci-config"); err == nil || calls["/tmp/ci-config"] != 1 {
+		t.Fatalf("expected the alternate config to be fetched and fail, err=%v calls=%v", err, calls)
+	}
+	if err := get("/home/u/.oci/config"); err != nil || calls["/home/u/.oci/config"] != 1 {
+		t.Fatalf("expected a cache hit for the first config, err=%v calls=%v", err, calls)
+	}
+
+	if n, err := cache.Purge("/tmp/ci-config", "DEFAULT", "ocid1.tenancy.oc1..t"); err != nil || n != 0 {
+		t.Fatalf("expected nothing purged for the other config, got %d %v", n, err)
+	}
+	if n, err := cache.Purge("/home/u/.oci/config", "DEFAULT", "ocid1.tenancy.oc1..t"); err != nil || n != 1 {
+		t.Fatalf("expected the first config's entry purged, got %d %v", n, err)
+	}
+}
+
+func TestIdentityCacheTTLFromEnv(t *testing.T) {
+	t.Setenv(EnvIdentityCacheTTL, "")
+	if got := IdentityCacheTTL(); got != DefaultIdentityCacheTTL {
+		t.Fatalf("default TTL = %s", got)
+	}
+	t.Setenv(EnvIdentityCacheTTL, "0")
+	if got := IdentityCacheTTL(); got != 0 {
+		t.Fatalf("expected disabled cache, got %s", got)
+	}
+	t.Setenv(EnvIdentityCacheTTL, "30m")
+	if got := IdentityCacheTTL(); got != 30*time.Minute {
+		t.Fatalf("expected 30m, got %s", got)
+	}
+}
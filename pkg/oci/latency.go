@@ -0,0 +1,62 @@
+package oci
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// RegionUnreachable marks regions whose identity endpoint could not be reached
+// before the probe context expired.
+const RegionUnreachable time.Duration = -1
+
+// regionProbeConcurrency bounds simultaneous connection probes.
+const regionProbeConcurrency = 8
+
+// probeDial connects to addr and completes a TLS handshake; tests replace it.
+var probeDial = func(ctx context.Context, addr string) error {
+	d := tls.Dialer{NetDialer: &net.Dialer{}}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// RegionIdentityEndpoint returns host:port of the identity service for region,
+// using the SDK's realm-aware domain mapping.
+func RegionIdentityEndpoint(region string) string {
+	return net.JoinHostPort(common.StringToRegion(region).Endpoint("identity"), "443")
+}
+
+// ProbeRegionLatency measures TCP+TLS connect time to each region's identity
+// endpoint concurrently. Every region gets an entry; failures and timeouts are
+// recorded as RegionUnreachable. Callers bound the total time via ctx.
+func ProbeRegionLatency(ctx context.Context, regions []string) map[string]time.Duration {
+	out := make(map[string]time.Duration, len(regions))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, regionProbeConcurrency)
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			start := time.Now()
+			latency := RegionUnreachable
+			if err := probeDial(ctx, RegionIdentityEndpoint(region)); err == nil {
+				latency = time.Since(start)
+			}
+			mu.Lock()
+			out[region] = latency
+			mu.Unlock()
+		}(region)
+	}
+	wg.Wait()
+	return out
+}
@@ -0,0 +1,38 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProbeRegionLatencyMarksUnreachable(t *testing.T) {
+	orig := probeDial
+	t.Cleanup(func() { probeDial = orig })
+	probeDial = func(ctx context.Context, addr string) error {
+		switch addr {
+		case "identity.us-ashburn-1.oraclecloud.com:443":
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		case "identity.eu-frankfurt-1.oraclecloud.com:443":
+			<-ctx.Done()
+			return ctx.Err()
+		default:
+			return errors.New("unexpected addr " + addr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	got := ProbeRegionLatency(ctx, []string{"us-ashburn-1", "eu-frankfurt-1"})
+	if len(got) != 2 {
+		t.Fatalf("expected an entry per region, got %v", got)
+	}
+	if got["us-ashburn-1"] < 5*time.Millisecond {
+		t.Fatalf("expected measured latency for us-ashburn-1, got %s", got["us-ashburn-1"])
+	}
+	if got["eu-frankfurt-1"] != RegionUnreachable {
+		t.Fatalf("expected eu-frankfurt-1 unreachable, got %s", got["eu-frankfurt-1"])
+	}
+}
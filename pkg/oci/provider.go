@@ -0,0 +1,61 @@
+package oci
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/adrianmross/oci-context/pkg/ocicfg"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// newConfigProvider builds an SDK configuration provider for profile. The SDK only
+// expands "~/" in key_file, so key and security token paths are resolved with
+// ocicfg.ExpandPath to support "~user/..." and paths relative to the config file.
+func newConfigProvider(profileConfigPath, profile string) (common.ConfigurationProvider, error) {
+	base, err := common.ConfigurationProviderFromFileWithProfile(profileConfigPath, profile, "")
+	if err != nil {
+		return nil, err
+	}
+	cfgFile, err := ocicfg.ExpandPath(profileConfigPath, "")
+	if err != nil {
+		return base, nil
+	}
+	p, ok, err := ocicfg.ReadProfile(cfgFile, profile)
+	if err != nil || !ok {
+		// Let the SDK provider surface its own, more specific errors.
+		return base, nil
+	}
+	return expandedPathProvider{ConfigurationProvider: base, profile: p}, nil
+}
+
+// expandedPathProvider reads key material from ocicfg-resolved paths and defers
+// everything else to the SDK file provider.
+type expandedPathProvider struct {
+	common.ConfigurationProvider
+	profile ocicfg.Profile
+}
+
+func (p expandedPathProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	if p.profile.KeyFile == "" {
+		return p.ConfigurationProvider.PrivateRSAKey()
+	}
+	pem, err := os.ReadFile(p.profile.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read key_file: %w", err)
+	}
+	pass := p.profile.PassPhrase
+	return common.PrivateKeyFromBytes(pem, &pass)
+}
+
+func (p expandedPathProvider) KeyID() (string, error) {
+	if p.profile.User != "" || p.profile.SecurityTokenFile == "" {
+		return p.ConfigurationProvider.KeyID()
+	}
+	token, err := os.ReadFile(p.profile.SecurityTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("read security_token_file: %w", err)
+	}
+	return "ST$" + strings.TrimSpace(string(token)), nil
+}
@@ -0,0 +1,165 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvRegionCacheTTL overrides how long cached region subscriptions stay fresh.
+// It accepts a Go duration ("6h"); "0" disables the cache.
+const EnvRegionCacheTTL = "OCI_CONTEXT_REGION_CACHE_TTL"
+
+// DefaultRegionCacheTTL is used when EnvRegionCacheTTL is unset or invalid.
+// Subscriptions change rarely, so it is much longer than the identity TTL.
+const DefaultRegionCacheTTL = 24 * time.Hour
+
+// RegionLister has the signature of ListRegionSubscriptions.
+type RegionLister func(ctx context.Context, profileConfigPath, profile string) ([]string, error)
+
+// RegionCache stores ListRegionSubscriptions results in a JSON file keyed by
+// tenancy OCID.
+type RegionCache struct {
+	Path string
+	TTL  time.Duration
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+type regionCacheEntry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Regions   []string  `json:"regions"`
+}
+
+type regionCacheFile struct {
+	Tenancies map[string]regionCacheEntry `json:"tenancies"`
+}
+
+// RegionCacheTTL returns the configured TTL; zero means caching is disabled.
+func RegionCacheTTL() time.Duration {
+	v := strings.TrimSpace(os.Getenv(EnvRegionCacheTTL))
+	if v == "" {
+		return DefaultRegionCacheTTL
+	}
+	if v == "0" {
+		return 0
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultRegionCacheTTL
+}
+
+// NewRegionCache returns a cache at path using RegionCacheTTL.
+func NewRegionCache(path string) *RegionCache {
+	return &RegionCache{Path: path, TTL: RegionCacheTTL()}
+}
+
+// Lookup returns tenancy's subscribed regions from the cache when fresh, else
+// calls list and caches a successful result. Errors are never cached. A nil
+// cache, zero TTL, or empty tenancy always calls list.
+func (c *RegionCache) Lookup(ctx context.Context, tenancy, profileConfigPath, profile string, list RegionLister) ([]string, error) {
+	if c == nil || c.TTL <= 0 || tenancy == "" {
+		return list(ctx, profileConfigPath, profile)
+	}
+	if regions, ok := c.get(tenancy); ok {
+		slog.Debug("region cache hit", "tenancy", tenancy)
+		return regions, nil
+	}
+	slog.Debug("region cache miss", "tenancy", tenancy)
+	regions, err := list(ctx, profileConfigPath, profile)
+	if err != nil {
+		return nil, err
+	}
+	// A cache that cannot be written only costs a future lookup.
+	_ = c.put(tenancy, regions)
+	return regions, nil
+}
+
+// Purge removes tenancy's cached regions (every tenancy's when empty) and
+// reports how many entries were removed.
+func (c *RegionCache) Purge(tenancy string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	lock, err := lockCacheFile(c.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Unlock()
+	f := c.read()
+	removed := 0
+	for k := range f.Tenancies {
+		if tenancy == "" || k == tenancy {
+			delete(f.Tenancies, k)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, c.write(f)
+}
+
+func (c *RegionCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *RegionCache) get(tenancy string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.read().Tenancies[tenancy]
+	if !ok || c.clock().Sub(e.FetchedAt) >= c.TTL {
+		return nil, false
+	}
+	return e.Regions, true
+}
+
+func (c *RegionCache) put(tenancy string, regions []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lock, err := lockCacheFile(c.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	f := c.read()
+	now := c.clock()
+	for k, e := range f.Tenancies {
+		if now.Sub(e.FetchedAt) >= c.TTL {
+			delete(f.Tenancies, k)
+		}
+	}
+	f.Tenancies[tenancy] = regionCacheEntry{FetchedAt: now, Regions: regions}
+	return c.write(f)
+}
+
+// read returns the cache contents; a missing or corrupt file reads as empty.
+func (c *RegionCache) read() regionCacheFile {
+	f := regionCacheFile{}
+	if data, err := os.ReadFile(c.Path); err == nil {
+		_ = json.Unmarshal(data, &f)
+	}
+	if f.Tenancies == nil {
+		f.Tenancies = map[string]regionCacheEntry{}
+	}
+	return f
+}
+
+func (c *RegionCache) write(f regionCacheFile) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(c.Path, data)
+}
@@ -0,0 +1,65 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRegionCacheLookupAndPurge(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &RegionCache{Path: filepath.Join(t.TempDir(), "cache", "regions.json"), TTL: time.Hour}
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	fail := false
+	list := func(_ context.Context, _, profile string) ([]string, error) {
+		calls++
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return []string{"us-phoenix-1", profile}, nil
+	}
+	lookup := func(tenancy, profile string) ([]string, error) {
+		return cache.Lookup(context.Background(), tenancy, "/tmp/oci", profile, list)
+	}
+
+	if got, err := lookup("ocid1.tenancy.oc1..a", "A"); err != nil || !reflect.DeepEqual(got, []string{"us-phoenix-1", "A"}) || calls != 1 {
+		t.Fatalf("first lookup: %v %v calls=%d", got, err, calls)
+	}
+	// Keyed by tenancy: another profile of the same tenancy is a hit.
+	if got, err := lookup("ocid1.tenancy.oc1..a", "A2"); err != nil || got[1] != "A" || calls != 1 {
+		t.Fatalf("expected cache hit: %v %v calls=%d", got, err, calls)
+	}
+	if _, err := lookup("", "A"); err != nil || calls != 2 {
+		t.Fatalf("expected an empty tenancy to bypass the cache, calls=%d", calls)
+	}
+	if _, err := lookup("ocid1.tenancy.oc1..b", "B"); err != nil || calls != 3 {
+		t.Fatalf("second tenancy: %v calls=%d", err, calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	fail = true
+	if _, err := lookup("ocid1.tenancy.oc1..a", "A"); err == nil || calls != 4 {
+		t.Fatalf("expected expired entry to refetch and surface error, calls=%d err=%v", calls, err)
+	}
+	fail = false
+	if _, err := lookup("ocid1.tenancy.oc1..a", "A"); err != nil || calls != 5 {
+		t.Fatalf("expected errors not to be cached, calls=%d", calls)
+	}
+
+	// The expired b entry was pruned on the last write, leaving only a.
+	if n, err := cache.Purge("ocid1.tenancy.oc1..b"); err != nil || n != 0 {
+		t.Fatalf("purge b: %d %v", n, err)
+	}
+	if n, err := cache.Purge(""); err != nil || n != 1 {
+		t.Fatalf("purge all: %d %v", n, err)
+	}
+	missing := &RegionCache{Path: filepath.Join(t.TempDir(), "none.json"), TTL: time.Hour}
+	if n, err := missing.Purge(""); err != nil || n != 0 {
+		t.Fatalf("purge missing file: %d %v", n, err)
+	}
+}
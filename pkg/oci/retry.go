@@ -0,0 +1,171 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// RetryPolicy controls retries of throttled (429) and server-side (5xx other
+// than 501) identity API errors. Other errors, including 401/404, are returned
+// at once.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls; 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the first backoff; each retry doubles it up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy is used by the identity and compartment helpers unless
+// the caller's context carries another policy (see WithRetryPolicy).
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 250 * time.Millisecond, MaxDelay: 4 * time.Second}
+
+// sleep is a seam so tests can skip real backoff delays.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a copy of ctx under which identity and compartment
+// helpers retry with p instead of DefaultRetryPolicy.
+func WithRetryPolicy(ctx context.Context, p RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, p)
+}
+
+// WithRetryAttempts is WithRetryPolicy with DefaultRetryPolicy's backoff and
+// at most attempts calls per request.
+func WithRetryAttempts(ctx context.Context, attempts int) context.Context {
+	p := DefaultRetryPolicy
+	p.MaxAttempts = attempts
+	return WithRetryPolicy(ctx, p)
+}
+
+// retryPolicyFrom returns the policy set on ctx by WithRetryPolicy, or
+// DefaultRetryPolicy.
+func retryPolicyFrom(ctx context.Context) RetryPolicy {
+	if p, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return p
+	}
+	return DefaultRetryPolicy
+}
+
+// isRetryable reports whether err is a throttling or server-side service error.
+func isRetryable(err error) bool {
+	var svcErr common.ServiceError
+	if !errors.As(err, &svcErr) {
+		return false
+	}
+	code := svcErr.GetHTTPStatusCode()
+	return code == http.StatusTooManyRequests || (code >= 500 && code != http.StatusNotImplemented)
+}
+
+// backoff returns the delay before retry n (1-based): exponential, capped at
+// MaxDelay, with jitter in [d/2, d].
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay << (n - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// withRetry calls fn until it succeeds, fails with a non-retryable error, runs
+// out of attempts, or the next backoff would pass the context deadline.
+func withRetry[T any](ctx context.Context, p RetryPolicy, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !isRetryable(err) {
+			return resp, err
+		}
+		delay := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+		slog.Debug("oci call retrying", "attempt", attempt, "delay", delay, "err", err)
+		if sleep(ctx, delay) != nil {
+			return resp, err
+		}
+	}
+}
+
+// identityAPI is the subset of identity.IdentityClient used by this package.
+type identityAPI interface {
+	GetTenancy(context.Context, identity.GetTenancyRequest) (identity.GetTenancyResponse, error)
+	GetUser(context.Context, identity.GetUserRequest) (identity.GetUserResponse, error)
+	GetCompartment(context.Context, identity.GetCompartmentRequest) (identity.GetCompartmentResponse, error)
+	ListCompartments(context.Context, identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error)
+	ListRegionSubscriptions(context.Context, identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error)
+}
+
+// retryingIdentityClient applies a RetryPolicy to every identityAPI call.
+type retryingIdentityClient struct {
+	api    identityAPI
+	policy RetryPolicy
+}
+
+// newIdentityClient builds an identity client for provider, optionally pinned
+// to region, wrapped with ctx's retry policy.
+var newIdentityClient = func(ctx context.Context, provider common.ConfigurationProvider, region string) (identityAPI, error) {
+	client, err := newSDKIdentityClient(provider, region)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("oci identity client", "endpoint", client.Host)
+	return retryingIdentityClient{api: client, policy: retryPolicyFrom(ctx)}, nil
+}
+
+// newSDKIdentityClient builds the SDK client with its own retries turned off,
+// so that withRetry is the only layer retrying a call.
+func newSDKIdentityClient(provider common.ConfigurationProvider, region string) (identity.IdentityClient, error) {
+	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+	if err != nil {
+		return client, err
+	}
+	if region != "" {
+		client.SetRegion(region)
+	}
+	// Only the retry policy is replaced; the SDK's circuit breaker stays.
+	noRetry := common.NoRetryPolicy()
+	client.Configuration.RetryPolicy = &noRetry
+	return client, nil
+}
+
+func (c retryingIdentityClient) GetTenancy(ctx context.Context, req identity.GetTenancyRequest) (identity.GetTenancyResponse, error) {
+	return withRetry(ctx, c.policy, func() (identity.GetTenancyResponse, error) { return c.api.GetTenancy(ctx, req) })
+}
+
+func (c retryingIdentityClient) GetUser(ctx context.Context, req identity.GetUserRequest) (identity.GetUserResponse, error) {
+	return withRetry(ctx, c.policy, func() (identity.GetUserResponse, error) { return c.api.GetUser(ctx, req) })
+}
+
+func (c retryingIdentityClient) GetCompartment(ctx context.Context, req identity.GetCompartmentRequest) (identity.GetCompartmentResponse, error) {
+	return withRetry(ctx, c.policy, func() (identity.GetCompartmentResponse, error) { return c.api.GetCompartment(ctx, req) })
+}
+
+func (c retryingIdentityClient) ListCompartments(ctx context.Context, req identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+	return withRetry(ctx, c.policy, func() (identity.ListCompartmentsResponse, error) { return c.api.ListCompartments(ctx, req) })
+}
+
+func (c retryingIdentityClient) ListRegionSubscriptions(ctx context.Context, req identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error) {
+	return withRetry(ctx, c.policy, func() (identity.ListRegionSubscriptionsResponse, error) {
+		return c.api.ListRegionSubscriptions(ctx, req)
+	})
+}
@@ -0,0 +1,148 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// stubIdentityAPI fails GetTenancy with errs in order, then succeeds.
+type stubIdentityAPI struct {
+	identityAPI
+	errs  []error
+	calls int
+}
+
+func (s *stubIdentityAPI) GetTenancy(context.Context, identity.GetTenancyRequest) (identity.GetTenancyResponse, error) {
+	s.calls++
+	if s.calls <= len(s.errs) {
+		return identity.GetTenancyResponse{}, s.errs[s.calls-1]
+	}
+	return identity.GetTenancyResponse{Tenancy: identity.Tenancy{Name: common.String("acme")}}, nil
+}
+
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &delays
+}
+
+func TestRetryingIdentityClient(t *testing.T) {
+	throttled := fakeServiceError{429, "TooManyRequests"}
+	unavailable := fakeServiceError{503, "ServiceUnavailable"}
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"retries 429 and 5xx then succeeds", policy, []error{throttled, unavailable}, 3, false},
+		{"gives up after max attempts", policy, []error{unavailable, unavailable, unavailable, unavailable}, 3, true},
+		{"does not retry auth errors", policy, []error{fakeServiceError{401, "NotAuthenticated"}}, 1, true},
+		{"does not retry not implemented", policy, []error{fakeServiceError{501, "NotImplemented"}}, 1, true},
+		{"does not retry not found", policy, []error{fakeServiceError{404, "NotAuthorizedOrNotFound"}}, 1, true},
+		{"does not retry plain errors", policy, []error{errors.New("dial tcp: refused")}, 1, true},
+		{"disabled", RetryPolicy{MaxAttempts: 1}, []error{throttled}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays := stubSleep(t)
+			stub := &stubIdentityAPI{errs: tt.errs}
+			client := retryingIdentityClient{api: stub, policy: tt.policy}
+			resp, err := client.GetTenancy(context.Background(), identity.GetTenancyRequest{})
+			if stub.calls != tt.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tt.wantCalls, stub.calls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if !tt.wantErr && deref(resp.Name) != "acme" {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+			if len(*delays) != tt.wantCalls-1 {
+				t.Fatalf("expected %d backoffs, got %v", tt.wantCalls-1, *delays)
+			}
+			for i, d := range *delays {
+				if d < 50*time.Millisecond || d > tt.policy.MaxDelay {
+					t.Fatalf("backoff %d out of range: %s", i, d)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryHonorsContextDeadline(t *testing.T) {
+	delays := stubSleep(t)
+	stub := &stubIdentityAPI{errs: []error{fakeServiceError{500, "InternalServerError"}, fakeServiceError{500, "InternalServerError"}}}
+	client := retryingIdentityClient{api: stub, policy: RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetTenancy(ctx, identity.GetTenancyRequest{}); err == nil {
+		t.Fatalf("expected error when backoff exceeds deadline")
+	}
+	if stub.calls != 1 || len(*delays) != 0 {
+		t.Fatalf("expected no retry past the deadline, calls=%d delays=%v", stub.calls, *delays)
+	}
+}
+
+func TestRetryPolicyFromContext(t *testing.T) {
+	if got := retryPolicyFrom(context.Background()).MaxAttempts; got != DefaultRetryPolicy.MaxAttempts {
+		t.Fatalf("default attempts = %d", got)
+	}
+	ctx := WithRetryPolicy(context.Background(), RetryPolicy{MaxAttempts: 1})
+	if got := retryPolicyFrom(ctx).MaxAttempts; got != 1 {
+		t.Fatalf("expected retries disabled, got %d attempts", got)
+	}
+}
+
+func TestIdentityClientRetriesOnlyOnce(t *testing.T) {
+	delays := stubSleep(t)
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"ServiceUnavailable","message":"try later"}`))
+	}))
+	defer srv.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	provider := common.NewRawConfigurationProvider("ocid1.tenancy.oc1..t", "ocid1.user.oc1..u", "us-phoenix-1", "aa:bb", string(pemKey), nil)
+	sdk, err := newSDKIdentityClient(provider, "")
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	sdk.Host = srv.URL
+
+	client := retryingIdentityClient{api: sdk, policy: RetryPolicy{MaxAttempts: 3}}
+	if _, err := client.GetTenancy(context.Background(), identity.GetTenancyRequest{TenancyId: common.String("ocid1.tenancy.oc1..t")}); err == nil {
+		t.Fatalf("expected a 503 error")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("expected 3 HTTP attempts from withRetry alone, got %d (backoffs %v)", got, *delays)
+	}
+}
@@ -4,19 +4,148 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 // Profile holds minimal OCI CLI profile fields we need.
 type Profile struct {
-	User    string
-	Tenancy string
-	Region  string
+	User              string
+	Tenancy           string
+	Region            string
+	KeyFile           string
+	Fingerprint       string
+	PassPhrase        string
+	SecurityTokenFile string
+	// pathErr records a key_file or security_token_file that could not be
+	// expanded; that path is kept as written and VerifyKey reports the error.
+	pathErr error
+}
+
+// VerifyKey confirms the profile's key_file exists and is readable, expanding a
+// leading ~ to the user's home directory.
+func (p Profile) VerifyKey() error {
+	if p.pathErr != nil {
+		return p.pathErr
+	}
+	if p.KeyFile == "" {
+		return fmt.Errorf("key_file not set")
+	}
+	path, err := ExpandPath(p.KeyFile, "")
+	if err != nil {
+		return fmt.Errorf("key_file %s: %w", p.KeyFile, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("key_file %s: %w", p.KeyFile, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("key_file %s: %w", p.KeyFile, err)
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("key_file %s: is a directory", p.KeyFile)
+	}
+	return nil
 }
 
 // LoadProfiles parses the OCI CLI config (~/.oci/config) and returns profiles.
+// Like the OCI CLI, other profiles inherit an empty tenancy or region from
+// [DEFAULT], and an empty user when they share DEFAULT's tenancy.
 // Missing user is tolerated (session auth); missing tenancy or region remains an error.
+// key_file and security_token_file are expanded with ExpandPath relative to the config directory;
+// a path that cannot be expanded is kept as written and reported by that profile's VerifyKey.
 func LoadProfiles(path string) (map[string]Profile, error) {
+	profiles, err := parseProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	inheritDefaults(profiles)
+
+	for name, p := range profiles {
+		if err := checkProfile(name, &p); err != nil {
+			return nil, err
+		}
+		profiles[name] = p
+	}
+
+	return profiles, nil
+}
+
+// LoadProfile returns one profile as LoadProfiles would, but only that
+// profile is validated, so a broken sibling profile does not hide it.
+func LoadProfile(path, name string) (Profile, bool, error) {
+	profiles, err := parseProfiles(path)
+	if err != nil {
+		return Profile{}, false, err
+	}
+	inheritDefaults(profiles)
+	p, ok := profiles[name]
+	if !ok {
+		return Profile{}, false, nil
+	}
+	if err := checkProfile(name, &p); err != nil {
+		return Profile{}, true, err
+	}
+	return p, true, nil
+}
+
+// checkProfile requires tenancy and region; a missing user is filled with the
+// tenancy as a placeholder for session auth.
+func checkProfile(name string, p *Profile) error {
+	if p.Tenancy == "" {
+		return fmt.Errorf("profile %s missing tenancy", name)
+	}
+	if p.Region == "" {
+		return fmt.Errorf("profile %s missing region", name)
+	}
+	if p.User == "" {
+		p.User = p.Tenancy
+	}
+	return nil
+}
+
+// defaultProfileName is the section other profiles inherit values from.
+const defaultProfileName = "DEFAULT"
+
+// inheritDefaults fills empty tenancy and region from the DEFAULT profile. A
+// user is only inherited within the same tenancy, since a user OCID from
+// another tenancy can never authenticate.
+func inheritDefaults(profiles map[string]Profile) {
+	def, ok := profiles[defaultProfileName]
+	if !ok {
+		return
+	}
+	for name, p := range profiles {
+		if name == defaultProfileName {
+			continue
+		}
+		if p.Tenancy == "" {
+			p.Tenancy = def.Tenancy
+		}
+		if p.Region == "" {
+			p.Region = def.Region
+		}
+		if p.User == "" && p.Tenancy == def.Tenancy {
+			p.User = def.User
+		}
+		profiles[name] = p
+	}
+}
+
+// ReadProfile returns a single profile as written in the config file, without the
+// validation or placeholder user that LoadProfiles applies. Paths are expanded.
+func ReadProfile(path, name string) (Profile, bool, error) {
+	profiles, err := parseProfiles(path)
+	if err != nil {
+		return Profile{}, false, err
+	}
+	p, ok := profiles[name]
+	return p, ok, nil
+}
+
+func parseProfiles(path string) (map[string]Profile, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -52,6 +181,14 @@ func LoadProfiles(path string) (map[string]Profile, error) {
 			p.Tenancy = val
 		case "region":
 			p.Region = val
+		case "key_file":
+			p.KeyFile = val
+		case "fingerprint":
+			p.Fingerprint = val
+		case "pass_phrase":
+			p.PassPhrase = val
+		case "security_token_file":
+			p.SecurityTokenFile = val
 		}
 		profiles[current] = p
 	}
@@ -59,19 +196,22 @@ func LoadProfiles(path string) (map[string]Profile, error) {
 		return nil, err
 	}
 
-	// validate (tenancy and region required; user optional for session auth)
+	// A path that cannot be expanded only fails its own profile.
+	baseDir := filepath.Dir(path)
 	for name, p := range profiles {
-		if p.Tenancy == "" {
-			return nil, fmt.Errorf("profile %s missing tenancy", name)
+		if expanded, err := ExpandPath(p.KeyFile, baseDir); err != nil {
+			p.pathErr = fmt.Errorf("key_file %s: %w", p.KeyFile, err)
+		} else {
+			p.KeyFile = expanded
 		}
-		if p.Region == "" {
-			return nil, fmt.Errorf("profile %s missing region", name)
-		}
-		if p.User == "" {
-			p.User = p.Tenancy // placeholder for session auth
-			profiles[name] = p
+		if expanded, err := ExpandPath(p.SecurityTokenFile, baseDir); err != nil {
+			if p.pathErr == nil {
+				p.pathErr = fmt.Errorf("security_token_file %s: %w", p.SecurityTokenFile, err)
+			}
+		} else {
+			p.SecurityTokenFile = expanded
 		}
+		profiles[name] = p
 	}
-
 	return profiles, nil
 }
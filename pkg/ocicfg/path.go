@@ -0,0 +1,62 @@
+package ocicfg
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// EnvConfigFile is the environment variable the OCI CLI reads to locate its config file.
+const EnvConfigFile = "OCI_CLI_CONFIG_FILE"
+
+// ResolveConfigPath returns the OCI CLI config path to use.
+// Precedence: explicit flag > configured option > $OCI_CLI_CONFIG_FILE > ~/.oci/config.
+func ResolveConfigPath(explicit, configured string) (string, error) {
+	if p := strings.TrimSpace(explicit); p != "" {
+		return p, nil
+	}
+	if p := strings.TrimSpace(configured); p != "" {
+		return p, nil
+	}
+	if p := strings.TrimSpace(os.Getenv(EnvConfigFile)); p != "" {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".oci", "config"), nil
+}
+
+// ExpandPath expands a leading "~" or "~user" to a home directory and resolves
+// relative paths against baseDir (normally the OCI config file's directory).
+// Absolute paths and empty strings are returned unchanged; an empty baseDir
+// leaves relative paths as-is.
+func ExpandPath(path, baseDir string) (string, error) {
+	if path == "" || filepath.IsAbs(path) {
+		return path, nil
+	}
+	if strings.HasPrefix(path, "~") {
+		name, rest, _ := strings.Cut(path[1:], "/")
+		var home string
+		if name == "" {
+			h, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			home = h
+		} else {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			home = u.HomeDir
+		}
+		return filepath.Join(home, rest), nil
+	}
+	if baseDir == "" {
+		return path, nil
+	}
+	return filepath.Join(baseDir, path), nil
+}
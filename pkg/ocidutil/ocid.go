@@ -0,0 +1,119 @@
+// Package ocidutil classifies and validates Oracle Cloud Identifiers (OCIDs).
+package ocidutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the resource type segment of an OCID (for example "tenancy").
+type Type string
+
+const (
+	TypeUnknown      Type = ""
+	TypeTenancy      Type = "tenancy"
+	TypeCompartment  Type = "compartment"
+	TypeUser         Type = "user"
+	TypeGroup        Type = "group"
+	TypeDynamicGroup Type = "dynamicgroup"
+	TypePolicy       Type = "policy"
+)
+
+// KnownTypes returns the identity resource types oci-context reasons about.
+func KnownTypes() []Type {
+	return []Type{
+		TypeTenancy,
+		TypeCompartment,
+		TypeUser,
+		TypeGroup,
+		TypeDynamicGroup,
+		TypePolicy,
+	}
+}
+
+// Classify parses an OCID of the form
+// ocid1.<type>.<realm>.[region][.future].<unique-id> and returns its resource
+// type and whether the string is well formed. Malformed input returns
+// TypeUnknown and false.
+func Classify(ocid string) (Type, bool) {
+	parts := strings.Split(strings.TrimSpace(ocid), ".")
+	if len(parts) < 5 || parts[0] != "ocid1" {
+		return TypeUnknown, false
+	}
+	typ, realm, unique := parts[1], parts[2], parts[len(parts)-1]
+	if !isSegment(typ) || !isSegment(realm) || !isSegment(unique) {
+		return TypeUnknown, false
+	}
+	for _, p := range parts[3 : len(parts)-1] {
+		if p != "" && !isSegment(p) {
+			return TypeUnknown, false
+		}
+	}
+	return Type(typ), true
+}
+
+// IsValid reports whether ocid is a well-formed OCID of any type.
+func IsValid(ocid string) bool {
+	_, ok := Classify(ocid)
+	return ok
+}
+
+// IsType reports whether ocid is well formed and of one of the given types.
+func IsType(ocid string, types ...Type) bool {
+	typ, ok := Classify(ocid)
+	if !ok {
+		return false
+	}
+	for _, t := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTenancy reports whether ocid is a well-formed tenancy OCID.
+func IsTenancy(ocid string) bool { return IsType(ocid, TypeTenancy) }
+
+// IsCompartment reports whether ocid can be used as a compartment. The root
+// compartment of a tenancy shares the tenancy OCID, so both types qualify.
+func IsCompartment(ocid string) bool { return IsType(ocid, TypeCompartment, TypeTenancy) }
+
+// IsUser reports whether ocid is a well-formed user OCID.
+func IsUser(ocid string) bool { return IsType(ocid, TypeUser) }
+
+// Validate returns a descriptive error when ocid is malformed or not one of the
+// expected types. field names the value in the error message.
+func Validate(field, ocid string, types ...Type) error {
+	typ, ok := Classify(ocid)
+	if !ok {
+		return fmt.Errorf("%s %q is not a valid OCID", field, ocid)
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	for _, t := range types {
+		if typ == t {
+			return nil
+		}
+	}
+	want := make([]string, 0, len(types))
+	for _, t := range types {
+		want = append(want, string(t))
+	}
+	return fmt.Errorf("%s %q is a %s OCID, expected %s", field, ocid, typ, strings.Join(want, " or "))
+}
+
+func isSegment(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package ocidutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyKnownTypes(t *testing.T) {
+	tests := []struct {
+		ocid string
+		want Type
+	}{
+		{"ocid1.tenancy.oc1..aaaaaaaabbbb", TypeTenancy},
+		{"ocid1.compartment.oc1..aaaaaaaacccc", TypeCompartment},
+		{"ocid1.user.oc1..aaaaaaaadddd", TypeUser},
+		{"ocid1.group.oc1..aaaaaaaaeeee", TypeGroup},
+		{"ocid1.dynamicgroup.oc1..aaaaaaaaffff", TypeDynamicGroup},
+		{"ocid1.policy.oc1..aaaaaaaagggg", TypePolicy},
+		{"ocid1.instance.oc1.phx.anyhqljt1234", Type("instance")},
+		{"ocid1.bucket.oc1.iad.future.abcd", Type("bucket")},
+	}
+	for _, tt := range tests {
+		got, ok := Classify(tt.ocid)
+		if !ok {
+			t.Fatalf("Classify(%q) reported invalid", tt.ocid)
+		}
+		if got != tt.want {
+			t.Fatalf("Classify(%q) = %q, want %q", tt.ocid, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyRejectsMalformed(t *testing.T) {
+	for _, ocid := range []string{
+		"",
+		"ocid1",
+		"ocid1.tenancy.oc1",
+		"ocid1.tenancy.oc1..",
+		"ocid2.tenancy.oc1..aaaa",
+		"ocid1..oc1..aaaa",
+		"ocid1.tenancy...aaaa",
+		"ocid1.tenancy.oc1..aa aa",
+		"ocid1.tenancy.oc1.p$x.aaaa",
+		"tenancy",
+	} {
+		if typ, ok := Classify(ocid); ok || typ != TypeUnknown {
+			t.Fatalf("Classify(%q) = (%q, %t), want invalid", ocid, typ, ok)
+		}
+	}
+}
+
+func TestIsTypeHelpers(t *testing.T) {
+	tenancy := "ocid1.tenancy.oc1..aaaa"
+	compartment := "ocid1.compartment.oc1..bbbb"
+	user := "ocid1.user.oc1..cccc"
+
+	if !IsTenancy(tenancy) || IsTenancy(compartment) {
+		t.Fatalf("IsTenancy mismatch")
+	}
+	if !IsCompartment(compartment) || !IsCompartment(tenancy) || IsCompartment(user) {
+		t.Fatalf("IsCompartment should accept compartment and root tenancy OCIDs only")
+	}
+	if !IsUser(user) || IsUser(tenancy) {
+		t.Fatalf("IsUser mismatch")
+	}
+	if IsType("not-an-ocid", TypeTenancy) {
+		t.Fatalf("IsType accepted malformed OCID")
+	}
+}
+
+func TestValidateMessages(t *testing.T) {
+	if err := Validate("tenancy", "ocid1.tenancy.oc1..aaaa", TypeTenancy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := Validate("tenancy", "ocid1.compartment.oc1..bbbb", TypeTenancy)
+	if err == nil || !strings.Contains(err.Error(), "is a compartment OCID, expected tenancy") {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+	err = Validate("compartment", "bogus", TypeCompartment, TypeTenancy)
+	if err == nil || !strings.Contains(err.Error(), "not a valid OCID") {
+		t.Fatalf("expected malformed error, got %v", err)
+	}
+}